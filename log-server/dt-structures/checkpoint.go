@@ -0,0 +1,212 @@
+package dt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// A map checkpoint is a MapHead in the C2SP signed note checkpoint format
+// (see https://c2sp.org/tlog-checkpoint and https://c2sp.org/signed-note).
+// The note text is:
+//
+//	<origin>
+//	<map size>
+//	<map root hash, base64>
+//	<source tree root hash, base64>
+//	<source log tree size> <source log root hash, base64> (one line per source log)
+//
+// The note is signed with the map's ECDSA key, using the origin as the key name.
+// Since the checkpoint has no timestamp, republished SMHs share the same checkpoint.
+
+// checkpointSignatureType is the signed note signature type for ECDSA (P-256, SHA-256) signatures.
+const checkpointSignatureType = 0x02
+
+const noteSignaturePrefix = "— "
+
+// CheckpointKeyID returns the signed note key ID for the specified key name and public key,
+// i.e., the first 4 bytes of SHA-256(name || "\n" || 0x02 || DER-encoded public key).
+func CheckpointKeyID(name string, publicKey *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling public key: %w", err)
+	}
+	return util.HashBytes([]byte(name), []byte{'\n', checkpointSignatureType}, der)[:4], nil
+}
+
+// DefaultCheckpointOrigin returns the origin of the checkpoints of a map whose origin was not
+// configured, derived from the map's public key: "domain-transparency/" followed by the first
+// 16 bytes of the SHA-256 hash of the DER-encoded public key, in hexadecimal.
+func DefaultCheckpointOrigin(publicKey *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling public key: %w", err)
+	}
+	return fmt.Sprintf("domain-transparency/%x", util.HashBytes(der)[:16]), nil
+}
+
+// FormatCheckpoint returns the (unsigned) note text for the specified map head.
+func FormatCheckpoint(origin string, head MapHead) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n%d\n", origin, head.MapSize)
+	fmt.Fprintf(&sb, "%s\n", base64.StdEncoding.EncodeToString(head.MapRootHash[:]))
+	fmt.Fprintf(&sb, "%s\n", base64.StdEncoding.EncodeToString(head.SourceTreeRootHash[:]))
+	for _, rev := range head.SourceLogRevisions {
+		fmt.Fprintf(&sb, "%d %s\n", rev.TreeSize, base64.StdEncoding.EncodeToString(rev.RootHash[:]))
+	}
+	return []byte(sb.String())
+}
+
+// ParseCheckpoint parses the note text produced by FormatCheckpoint.
+// The timestamp of the returned MapHead is always zero.
+func ParseCheckpoint(text []byte) (origin string, head MapHead, err error) {
+	if len(text) == 0 || text[len(text)-1] != '\n' {
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint: missing final newline")
+	}
+	lines := strings.Split(string(text[:len(text)-1]), "\n")
+	if len(lines) < 4 {
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint: expected at least 4 lines, got %d", len(lines))
+	}
+
+	origin = lines[0]
+	if origin == "" {
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint: empty origin")
+	}
+	head.Version = Version
+	if head.MapSize, err = strconv.ParseUint(lines[1], 10, 64); err != nil {
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint map size: %w", err)
+	}
+	if err := decodeHash(head.MapRootHash[:], lines[2]); err != nil {
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint map root hash: %w", err)
+	}
+	if err := decodeHash(head.SourceTreeRootHash[:], lines[3]); err != nil {
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint source tree root hash: %w", err)
+	}
+	head.SourceLogRevisions = make([]LogRevision, len(lines)-4)
+	for i, line := range lines[4:] {
+		parts := strings.Split(line, " ")
+		if len(parts) != 2 {
+			return "", MapHead{}, fmt.Errorf("invalid checkpoint source log revision (log %d): %q", i, line)
+		}
+		rev := &head.SourceLogRevisions[i]
+		if rev.TreeSize, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+			return "", MapHead{}, fmt.Errorf("invalid checkpoint source log size (log %d): %w", i, err)
+		}
+		if err := decodeHash(rev.RootHash[:], parts[1]); err != nil {
+			return "", MapHead{}, fmt.Errorf("invalid checkpoint source log root hash (log %d): %w", i, err)
+		}
+	}
+	return origin, head, nil
+}
+
+func decodeHash(dst []byte, s string) error {
+	bs, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(bs) != len(dst) {
+		return fmt.Errorf("length=%d, expected %d", len(bs), len(dst))
+	}
+	copy(dst, bs)
+	return nil
+}
+
+// VerifyCheckpoint verifies a signed checkpoint and returns its origin and map head.
+// Signatures from other keys are ignored, but at least one signature must be
+// a valid signature by the specified key, using the origin as the key name.
+func VerifyCheckpoint(note []byte, publicKey *ecdsa.PublicKey) (string, MapHead, error) {
+	sep := bytes.LastIndex(note, []byte("\n\n"))
+	if sep < 0 {
+		return "", MapHead{}, fmt.Errorf("invalid signed note: no signatures")
+	}
+	text, sigs := note[:sep+1], note[sep+2:]
+	origin, head, err := ParseCheckpoint(text)
+	if err != nil {
+		return "", MapHead{}, err
+	}
+	keyID, err := CheckpointKeyID(origin, publicKey)
+	if err != nil {
+		return "", MapHead{}, err
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(sigs), "\n"), "\n") {
+		if !strings.HasPrefix(line, noteSignaturePrefix) {
+			return "", MapHead{}, fmt.Errorf("invalid signed note signature line: %q", line)
+		}
+		parts := strings.Split(strings.TrimPrefix(line, noteSignaturePrefix), " ")
+		if len(parts) != 2 || parts[0] != origin {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil || len(sig) < 5 || !bytes.Equal(sig[:4], keyID) {
+			continue
+		}
+		if ecdsa.VerifyASN1(publicKey, util.HashBytes(text), sig[4:]) {
+			return origin, head, nil
+		}
+		return "", MapHead{}, fmt.Errorf("invalid checkpoint signature")
+	}
+	return "", MapHead{}, fmt.Errorf("no checkpoint signature found for %q with key ID %x", origin, keyID)
+}
+
+// GetLatestCheckpoint returns the latest SMH as a signed checkpoint.
+// If the origin is empty, the map's default origin is used (see DefaultCheckpointOrigin).
+// Mirrors ignore the origin and return the upstream checkpoint (see AddUpstreamCheckpoint).
+func (dm *DomainMap) GetLatestCheckpoint(origin string) ([]byte, error) {
+	smh := dm.GetLatestSMH()
+	if smh.MapHeadSignature == nil {
+		return nil, fmt.Errorf("no SMH has been published yet")
+	}
+	if dm.signer == nil {
+		dm.mCheckpoint.Lock()
+		defer dm.mCheckpoint.Unlock()
+		if dm.checkpoint == nil || dm.checkpointMapSize != smh.MapSize {
+			return nil, fmt.Errorf("no upstream checkpoint for the latest SMH yet")
+		}
+		return dm.checkpoint, nil
+	}
+
+	publicKey, ok := dm.publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("checkpoints require an ECDSA key")
+	}
+	if origin == "" {
+		var err error
+		if origin, err = DefaultCheckpointOrigin(publicKey); err != nil {
+			return nil, err
+		}
+	} else if strings.ContainsAny(origin, " +\n") {
+		return nil, fmt.Errorf("invalid checkpoint origin %q", origin)
+	}
+
+	dm.mCheckpoint.Lock()
+	defer dm.mCheckpoint.Unlock()
+	if dm.checkpoint != nil && dm.checkpointOrigin == origin && dm.checkpointMapSize == smh.MapSize {
+		return dm.checkpoint, nil
+	}
+
+	keyID, err := CheckpointKeyID(origin, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	text := FormatCheckpoint(origin, smh.MapHead)
+	sig, err := dm.signBytes(text)
+	if err != nil {
+		return nil, fmt.Errorf("error signing checkpoint: %w", err)
+	}
+
+	var note bytes.Buffer
+	note.Write(text)
+	fmt.Fprintf(&note, "\n%s%s %s\n", noteSignaturePrefix, origin, base64.StdEncoding.EncodeToString(append(keyID, sig...)))
+
+	dm.checkpoint = note.Bytes()
+	dm.checkpointMapSize = smh.MapSize
+	dm.checkpointOrigin = origin
+	return dm.checkpoint, nil
+}
@@ -0,0 +1,158 @@
+package dt_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+)
+
+func TestFormatCheckpoint(t *testing.T) {
+	head := dt.MapHead{
+		Version:            dt.Version,
+		MapSize:            12,
+		MapRootHash:        ct.SHA256Hash{1, 2},
+		SourceTreeRootHash: ct.SHA256Hash{3},
+		SourceLogRevisions: []dt.LogRevision{{TreeSize: 5, RootHash: ct.SHA256Hash{4}}, {TreeSize: 7, RootHash: ct.SHA256Hash{5}}},
+	}
+	text := dt.FormatCheckpoint("example.com/map", head)
+	origin, parsed, err := dt.ParseCheckpoint(text)
+	if err != nil {
+		t.Fatalf("ParseCheckpoint: %v", err)
+	}
+	if origin != "example.com/map" || !reflect.DeepEqual(parsed, head) {
+		t.Errorf("ParseCheckpoint: got %q, %+v, expected %+v", origin, parsed, head)
+	}
+
+	for _, invalid := range []string{
+		"",
+		strings.TrimSuffix(string(text), "\n"),
+		"example.com/map\n12\n",
+		strings.Replace(string(text), "\n12\n", "\nx\n", 1),
+		strings.Replace(string(text), "\n5 ", "\n5  ", 1),
+		strings.Replace(string(text), "example.com/map", "", 1),
+		strings.Replace(string(text), "AQI", "AQ", 1),
+	} {
+		if _, _, err := dt.ParseCheckpoint([]byte(invalid)); err == nil {
+			t.Errorf("ParseCheckpoint(%q): expected an error", invalid)
+		}
+	}
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	if _, err := dm.GetLatestCheckpoint("example.com/map"); err == nil {
+		t.Errorf("GetLatestCheckpoint: got a checkpoint before the first SMH")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+	addTestCertificates(t, dm, c, 0, 0, 4, []string{"a.com", "b.com"})
+	addTestCertificates(t, dm, c, 1, 0, 2, []string{"c.com"})
+	smh := dm.GetLatestSMH()
+
+	note, err := dm.GetLatestCheckpoint("example.com/map")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin, head, err := dt.VerifyCheckpoint(note, &key.PublicKey)
+	if err != nil {
+		t.Fatalf("VerifyCheckpoint: %v", err)
+	}
+	want := smh.MapHead
+	want.Timestamp = 0
+	if origin != "example.com/map" || !reflect.DeepEqual(head, want) {
+		t.Errorf("VerifyCheckpoint: got %q, %+v, expected %+v", origin, head, want)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(note string) string
+	}{
+		{"other map size", func(note string) string { return strings.Replace(note, "\n6\n", "\n7\n", 1) }},
+		{"other source log size", func(note string) string { return strings.Replace(note, "\n2 ", "\n3 ", 1) }},
+		{"extra source log", func(note string) string {
+			return strings.Replace(note, "\n\n", "\n1 "+strings.Repeat("A", 43)+"=\n\n", 1)
+		}},
+		{"other origin", func(note string) string { return strings.Replace(note, "example.com/map\n", "example.com/other\n", 1) }},
+		{"other origin everywhere", func(note string) string { return strings.ReplaceAll(note, "example.com/map", "example.com/other") }},
+		{"no signatures", func(note string) string { return note[:strings.Index(note, "\n\n")+1] }},
+		{"invalid signature line", func(note string) string { return strings.Replace(note, "\n\n", "\n\ninvalid\n", 1) }},
+		{"tampered signature", func(note string) string {
+			i := strings.LastIndex(note, " ") + 10
+			b := []byte(note)
+			if b[i] == 'A' {
+				b[i] = 'B'
+			} else {
+				b[i] = 'A'
+			}
+			return string(b)
+		}},
+	}
+	for _, tt := range tests {
+		tampered := tt.tamper(string(note))
+		if tampered == string(note) {
+			t.Fatalf("%s: the note was not changed", tt.name)
+		}
+		if _, _, err := dt.VerifyCheckpoint([]byte(tampered), &key.PublicKey); err == nil {
+			t.Errorf("%s: tampered checkpoint verified", tt.name)
+		}
+	}
+	if _, _, err := dt.VerifyCheckpoint(note, &otherKey.PublicKey); err == nil {
+		t.Errorf("VerifyCheckpoint: verified with the wrong key")
+	}
+
+	// Signatures by other keys are ignored
+	otherID, err := dt.CheckpointKeyID("example.com/map", &otherKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSig := base64.StdEncoding.EncodeToString(append(otherID, 1, 2, 3))
+	cosigned := []byte(strings.Replace(string(note), "\n\n", "\n\n— example.com/map "+otherSig+"\n", 1))
+	if _, _, err := dt.VerifyCheckpoint(cosigned, &key.PublicKey); err != nil {
+		t.Errorf("VerifyCheckpoint with another signature: %v", err)
+	}
+
+	// The default origin is derived from the map's key
+	defaultOrigin, err := dt.DefaultCheckpointOrigin(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherOrigin, err := dt.DefaultCheckpointOrigin(&otherKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(defaultOrigin, "domain-transparency/") || defaultOrigin == otherOrigin {
+		t.Errorf("DefaultCheckpointOrigin: got %q and %q for different keys", defaultOrigin, otherOrigin)
+	}
+	note, err = dm.GetLatestCheckpoint("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if origin, _, err := dt.VerifyCheckpoint(note, &key.PublicKey); err != nil || origin != defaultOrigin {
+		t.Errorf("VerifyCheckpoint (default origin): got %q (%v), expected %q", origin, err, defaultOrigin)
+	}
+	if !bytes.HasPrefix(note, []byte(defaultOrigin+"\n")) {
+		t.Errorf("GetLatestCheckpoint: the default origin is not the origin line")
+	}
+	if _, err := dm.GetLatestCheckpoint("example.com/a map"); err == nil {
+		t.Errorf("GetLatestCheckpoint: accepted an origin with a space")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+var (
+	cmd        = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	mapURI     = cmd.String("map_uri", "http://127.0.0.1:8021/", "")
+	mapKeyPEM  = cmd.String("map_key", "config/publickey.pem", "the map's public key")
+	privatePEM = cmd.String("private_key", "config/witness-privatekey.pem", "the pem file with this witness's private key (the file will be created if missing)")
+	publicPEM  = cmd.String("public_key", "config/witness-publickey.pem", "the pem file with this witness's public key (the file will be created if missing)")
+	stateFile  = cmd.String("state", "config/witness-state.json", "the file in which the last cosigned SMH is stored")
+	interval   = cmd.Duration("interval", 5*time.Second, "how often to check for new SMHs")
+)
+
+func init() {
+	log.SetFlags(log.Ldate | log.Ltime)
+}
+
+func main() {
+	cmd.Parse(os.Args[1:])
+
+	mapPubKey, err := util.LoadPublicKey(*mapKeyPEM)
+	if err != nil {
+		log.Printf("Error loading map public key: %v", err)
+		return
+	}
+	key, err := util.LoadOrGenerateKeys(*privatePEM, *publicPEM)
+	if err != nil {
+		log.Printf("Error creating or loading key: %v", err)
+		return
+	}
+
+	mc := mapclient.New(*mapURI, http.DefaultClient, mapPubKey)
+	w, err := newWitness(mc, key, *stateFile)
+	if err != nil {
+		log.Printf("Error starting witness: %v", err)
+		return
+	}
+	log.Printf("Witness started...")
+
+	ctx := context.Background()
+	for ; ; time.Sleep(*interval) {
+		smh, err := mc.GetAndVerifySMH(ctx)
+		if err != nil {
+			log.Printf("Error fetching SMH: %v", err)
+			continue
+		}
+		if ok, err := w.CheckSMH(ctx, smh); err != nil {
+			log.Printf("Inconsistent SMH (size=%d, timestamp=%d), refusing to cosign: %v", smh.MapSize, smh.Timestamp, err)
+			continue
+		} else if !ok {
+			continue
+		}
+		if err := w.Cosign(ctx, smh); err != nil {
+			log.Printf("Error cosigning SMH: %v", err)
+			continue
+		}
+		log.Printf("Cosigned SMH: timestamp=%d, size=%d, rootHash=%x...", smh.Timestamp, smh.MapSize, smh.MapRootHash[:4])
+	}
+}
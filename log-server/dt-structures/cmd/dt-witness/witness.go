@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// A Witness checks that the SMHs published by a map are consistent with each other
+// and cosigns the ones that are.
+type Witness struct {
+	mc        mapclient.Client
+	key       *ecdsa.PrivateKey
+	stateFile string
+
+	last       *ds.GetSMHResponse
+	logClients []*client.LogClient
+	verifier   merkle.LogVerifier
+}
+
+// loadState loads the last cosigned SMH from the state file, if it exists.
+func (w *Witness) loadState() error {
+	data, err := os.ReadFile(w.stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error reading state file %q: %w", w.stateFile, err)
+	}
+	var smh ds.GetSMHResponse
+	if err := json.Unmarshal(data, &smh); err != nil {
+		return fmt.Errorf("error parsing state file %q: %w", w.stateFile, err)
+	}
+	if smh.Version == 0 {
+		smh.Version = dt.VersionV1
+	}
+	w.last = &smh
+	return nil
+}
+
+// saveState saves the last cosigned SMH to the state file.
+func (w *Witness) saveState() error {
+	data, err := json.Marshal(w.last)
+	if err != nil {
+		return err
+	}
+	tmp := w.stateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing state file %q: %w", tmp, err)
+	}
+	return os.Rename(tmp, w.stateFile)
+}
+
+// CheckSMH checks that `smh` is consistent with the last SMH seen by the witness.
+// Returns (false, nil) if the SMH has already been cosigned.
+func (w *Witness) CheckSMH(ctx context.Context, smh *ds.GetSMHResponse) (bool, error) {
+	if w.last == nil {
+		return true, nil
+	}
+	if bytes.Equal(w.last.MapHeadSignature, smh.MapHeadSignature) {
+		return false, nil
+	}
+	if err := w.mc.VerifySMHConsistency(ctx, w.last, smh); err != nil {
+		return false, err
+	}
+	for i, rev := range w.last.SourceLogRevisions {
+		if err := w.verifyLogConsistency(ctx, uint64(i), rev, smh.SourceLogRevisions[i], smh); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (w *Witness) getLogClient(ctx context.Context, logIndex uint64, smh *ds.GetSMHResponse) (*client.LogClient, error) {
+	for uint64(len(w.logClients)) <= logIndex {
+		w.logClients = append(w.logClients, nil)
+	}
+	if w.logClients[logIndex] != nil {
+		return w.logClients[logIndex], nil
+	}
+	logID, err := w.mc.GetAndVerifySourceLogAndProof(ctx, logIndex, smh)
+	if err != nil {
+		return nil, err
+	}
+	var id [32]byte
+	copy(id[:], logID)
+	log := util.GetLogList().FindLogByKeyHash(id)
+	if log == nil {
+		return nil, fmt.Errorf("unknown log with key %x", id)
+	}
+	lc, err := client.New(log.URL, http.DefaultClient, jsonclient.Options{PublicKeyDER: log.Key})
+	if err != nil {
+		return nil, err
+	}
+	w.logClients[logIndex] = lc
+	return lc, nil
+}
+
+// verifyLogConsistency checks that two revisions of a source log are consistent.
+func (w *Witness) verifyLogConsistency(ctx context.Context, logIndex uint64, first, second dt.LogRevision, smh *ds.GetSMHResponse) error {
+	if first.TreeSize == second.TreeSize || first.TreeSize == 0 {
+		return nil
+	}
+	lc, err := w.getLogClient(ctx, logIndex, smh)
+	if err != nil {
+		return err
+	}
+	proof, err := lc.GetSTHConsistency(ctx, first.TreeSize, second.TreeSize)
+	if err != nil {
+		return fmt.Errorf("error fetching consistency proof for log %d: %w", logIndex, err)
+	}
+	err = w.verifier.VerifyConsistencyProof(int64(first.TreeSize), int64(second.TreeSize), first.RootHash[:], second.RootHash[:], proof)
+	if err != nil {
+		return fmt.Errorf("source log %d revisions are inconsistent: %w", logIndex, err)
+	}
+	return nil
+}
+
+// Cosign cosigns and submits an SMH that has already been checked, and saves it as the last seen SMH.
+func (w *Witness) Cosign(ctx context.Context, smh *ds.GetSMHResponse) error {
+	req, err := mapclient.CosignSMH(w.key, &w.key.PublicKey, smh)
+	if err != nil {
+		return err
+	}
+	if err := w.mc.AddCosignature(ctx, req); err != nil {
+		return fmt.Errorf("error submitting cosignature: %w", err)
+	}
+	w.last = smh
+	return w.saveState()
+}
+
+func newWitness(mc mapclient.Client, key *ecdsa.PrivateKey, stateFile string) (*Witness, error) {
+	w := &Witness{
+		mc:        mc,
+		key:       key,
+		stateFile: stateFile,
+		verifier:  merkle.NewLogVerifier(rfc6962.DefaultHasher),
+	}
+	if err := w.loadState(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,8 +19,11 @@ import (
 	"github.com/google/certificate-transparency-go/jsonclient"
 	"github.com/google/certificate-transparency-go/loglist2"
 	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
 	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
 	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var (
@@ -28,15 +33,32 @@ var (
 	publicPEM         = cmd.String("public_key", "config/publickey.pem", "the pem file with this map's public key (the file will be created if missing)")
 	ip                = cmd.String("ip", "127.0.0.1", "the IP address on which to run the server")
 	port              = cmd.Uint("port", 8021, "the port address on which to run the server")
-	smhUpdateInterval = cmd.Duration("smh_interval", 5*time.Second, "how often to try to publish SMHs")
+	grpcPort          = cmd.Uint("grpc_port", 0, "the port address on which to run the gRPC server (0 to disable)")
+	smhUpdateInterval = cmd.Duration("smh_interval", 5*time.Second, "how often to try to publish SMHs (or, with --mirror_of, to check for new upstream SMHs)")
 	sthUpdateInterval = cmd.Duration("sth_interval", 5*time.Second, "how often to check for STH updates")
 	mmd               = cmd.Duration("mmd", 60*time.Second, "the max interval between SMHs")
+	origin            = cmd.String("origin", "", "the origin line of this map's checkpoints (default: derived from the map's public key, see dt.DefaultCheckpointOrigin)")
+	tlsCert           = cmd.String("tls_cert", "", "the pem file with the TLS certificate of the server (reloaded when changed; if empty, serve plain HTTP)")
+	tlsKey            = cmd.String("tls_key", "", "the pem file with the private key of the TLS certificate")
+	adminPort         = cmd.Uint("admin_port", 0, "the port address on which to run the admin server, which requires --tls_cert and --admin_client_ca (0 to disable)")
+	rateLimit         = cmd.Float64("rate_limit", 0, "the number of request tokens per second granted to each client IP (0 to disable rate limiting)")
+	rateBurst         = cmd.Int("rate_burst", 100, "the max number of request tokens a client IP may accumulate")
+	apiKeys           = cmd.String("api_keys", "", "a file with API keys (one per line), whose clients are rate limited separately")
+	apiKeyRateLimit   = cmd.Float64("api_key_rate_limit", 100, "the number of request tokens per second granted to each API key")
+	apiKeyRateBurst   = cmd.Int("api_key_rate_burst", 1000, "the max number of request tokens an API key may accumulate")
+	maxLag            = cmd.Uint64("max_lag", ds.DefaultMaxLag, "the max number of entries a log (or the upstream map) may be ahead of the map for /readyz to succeed")
+	ctCacheDir        = cmd.String("ct_cache_dir", "config/ct-cache", "the directory where the CT log entries of the map's certificates are stored (if empty, only the most recent entries are kept in memory)")
+	mirrorOf          = cmd.String("mirror_of", "", "the URL of a DT server to replicate as a read-only mirror, whose public key is read from --public_key (if empty, run a map from the --log logs)")
 
 	logSpecifiers stringSliceFlags
+	witnessKeys   stringSliceFlags
+	adminCAs      stringSliceFlags
 )
 
 func init() {
 	cmd.Var(&logSpecifiers, "log", "a log from which to pull map updates (by name, url or hash). This log must be listed in the loglist.json file. If empty, use preset data. (repeatable)")
+	cmd.Var(&witnessKeys, "witness_key", "the pem file with the public key of a witness whose cosignatures should be accepted (repeatable)")
+	cmd.Var(&adminCAs, "admin_client_ca", "the pem file with a CA whose client certificates may access the admin server (repeatable)")
 
 	// Remove glog output
 	flag.Set("logtostderr", "false")
@@ -46,45 +68,189 @@ func init() {
 
 func main() {
 	cmd.Parse(os.Args[1:])
-
-	key, err := loadOrGenerateKeys(*privatePEM, *publicPEM)
-	if err != nil {
-		fmt.Printf("Error creating or loading key: %v\n", err)
+	if *rateLimit < 0 {
+		fmt.Printf("Invalid --rate_limit %v: must be 0 (disabled) or positive\n", *rateLimit)
+		return
+	}
+	if *rateLimit > 0 && *apiKeys != "" && *apiKeyRateLimit <= 0 {
+		fmt.Printf("Invalid --api_key_rate_limit %v: must be positive\n", *apiKeyRateLimit)
 		return
 	}
 
-	dm := dt.NewDomainMap(key)
-	svr, _ := ds.NewServer(dm, *ip, int(*port))
-	ctx, cancel := context.WithCancel(context.Background())
-	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{
-		BufferSize:   32,
-		UpdatePeriod: *smhUpdateInterval,
-		MMD:          *mmd,
-	})
+	var dm *dt.DomainMap
+	var upstreamKey *ecdsa.PublicKey
+	if *mirrorOf != "" {
+		if len(logSpecifiers) != 0 {
+			fmt.Printf("Mirrors replicate the source logs of the upstream map: --log cannot be used with --mirror_of\n")
+			return
+		}
+		var err error
+		if upstreamKey, err = util.LoadPublicKey(*publicPEM); err != nil {
+			fmt.Printf("Error loading the public key of the upstream map: %v\n", err)
+			return
+		}
+		dm = dt.NewMirrorDomainMap(upstreamKey)
+	} else {
+		key, err := util.LoadOrGenerateKeys(*privatePEM, *publicPEM)
+		if err != nil {
+			fmt.Printf("Error creating or loading key: %v\n", err)
+			return
+		}
+		dm = dt.NewDomainMap(key)
+	}
+	for _, pemfile := range witnessKeys {
+		witnessKey, err := util.LoadPublicKey(pemfile)
+		if err != nil {
+			fmt.Printf("Error loading witness key: %v\n", err)
+			return
+		}
+		keyHash, err := dm.AddWitness(witnessKey)
+		if err != nil {
+			fmt.Printf("Error adding witness: %v\n", err)
+			return
+		}
+		fmt.Printf("Accepting cosignatures from witness %x\n", keyHash)
+	}
+	svr, h := ds.NewServer(dm, *ip, int(*port))
+	h.CheckpointOrigin = *origin
+	if *ctCacheDir != "" {
+		store, err := ds.NewLeafStore(*ctCacheDir)
+		if err != nil {
+			fmt.Printf("Error opening the CT entry cache: %v\n", err)
+			return
+		}
+		h.CTLogs = ds.NewPersistentCTLogCache(store)
+	} else {
+		h.CTLogs = ds.NewCTLogCache()
+	}
+	h.Readiness = ds.NewReadiness(*maxLag)
+	if *rateLimit > 0 {
+		h.RateLimiter = ds.NewRateLimiter(ds.RateLimit{Rate: *rateLimit, Burst: *rateBurst})
+		if *apiKeys != "" {
+			keys, err := loadAPIKeys(*apiKeys)
+			if err != nil {
+				fmt.Printf("Error loading API keys: %v\n", err)
+				return
+			}
+			for _, key := range keys {
+				h.RateLimiter.AddAPIKey(key, ds.RateLimit{Rate: *apiKeyRateLimit, Burst: *apiKeyRateBurst})
+			}
+		}
+	}
+	// Mirrors have no worker or fetchers to control
+	var admin ds.AdminConfig
+	if *mirrorOf == "" {
+		admin = ds.AdminConfig{Worker: dt.NewWorkerControl(), Fetchers: ds.NewFetcherControl()}
+	}
+
+	var certs *ds.CertificateReloader
+	if *tlsCert != "" {
+		var err error
+		certs, err = ds.NewCertificateReloader(*tlsCert, *tlsKey)
+		if err != nil {
+			fmt.Printf("Error loading TLS certificate: %v\n", err)
+			return
+		}
+		svr.TLSConfig = certs.TLSConfig()
+	}
+	var adminSvr *http.Server
+	if *adminPort != 0 {
+		if certs == nil {
+			fmt.Printf("The admin server requires --tls_cert and --tls_key\n")
+			return
+		}
+		tlsConfig, err := ds.AdminTLSConfig(certs, adminCAs)
+		if err != nil {
+			fmt.Printf("Error configuring the admin server: %v\n", err)
+			return
+		}
+		adminSvr = ds.NewAdminServer(net.JoinHostPort(*ip, strconv.Itoa(int(*adminPort))), admin, tlsConfig)
+	}
 
-	if len(logSpecifiers) == 0 {
+	ctx, cancel := context.WithCancel(context.Background())
+	var stopped <-chan struct{}
+	if *mirrorOf != "" {
+		fmt.Printf("Mirroring %s\n", *mirrorOf)
+		stopped = startMirror(ctx, &mirror{
+			mc:        mapclient.New(*mirrorOf, http.DefaultClient, upstreamKey),
+			dm:        dm,
+			m:         dt.NewMirror(dm),
+			ctLogs:    h.CTLogs,
+			readiness: h.Readiness,
+			interval:  *smhUpdateInterval,
+			logClient: loglistClient,
+		})
+	} else if len(logSpecifiers) == 0 {
 		fmt.Printf("No logs specified\n")
 		return
 	} else {
+		var c chan<- dt.WorkerTransaction
+		c, stopped = dt.StartWorker(ctx, dm, dt.WorkerConfig{
+			BufferSize:   32,
+			UpdatePeriod: *smhUpdateInterval,
+			MMD:          *mmd,
+			Control:      admin.Worker,
+		})
+
 		timestamps, logClients, err := specsToLogs(logSpecifiers)
 		if err != nil {
 			fmt.Printf("Error creating log fetchers: %s\n", err)
 			return
 		}
+		// Register every log before the fetchers start (after a delay), so that the
+		// server isn't reported ready before all of them have caught up.
+		for i := range logClients {
+			h.Readiness.AddLog(uint64(i))
+		}
 		for i, lc := range logClients {
 			t := timestamps[i]
-			go fetcherData(ctx, t, dm, c, lc, uint64(i))
+			go fetcherData(ctx, t, dm, c, h.CTLogs, h.Readiness, admin.Fetchers, lc, uint64(i))
 		}
 	}
 
 	go func() {
 		fmt.Printf("Starting server on %s\n", svr.Addr)
-		if err = svr.ListenAndServe(); err != nil {
+		var err error
+		if certs != nil {
+			err = svr.ListenAndServeTLS("", "")
+		} else {
+			err = svr.ListenAndServe()
+		}
+		if err != nil {
 			fmt.Printf("Server error: %v\n", err)
 		}
 	}()
+	if adminSvr != nil {
+		go func() {
+			fmt.Printf("Starting admin server on %s\n", adminSvr.Addr)
+			if err := adminSvr.ListenAndServeTLS("", ""); err != nil {
+				fmt.Printf("Admin server error: %v\n", err)
+			}
+		}()
+	}
 
-	handleInterrupts(cancel, svr, stopped)
+	var grpcSvr *grpc.Server
+	if *grpcPort != 0 {
+		addr := net.JoinHostPort(*ip, strconv.Itoa(int(*grpcPort)))
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			fmt.Printf("Error listening on %s: %v\n", addr, err)
+			return
+		}
+		var opts []grpc.ServerOption
+		if certs != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+		}
+		grpcSvr = ds.NewGRPCServer(h, opts...)
+		go func() {
+			fmt.Printf("Starting gRPC server on %s\n", addr)
+			if err := grpcSvr.Serve(lis); err != nil {
+				fmt.Printf("gRPC server error: %v\n", err)
+			}
+		}()
+	}
+
+	handleInterrupts(cancel, svr, adminSvr, grpcSvr, stopped)
 }
 
 func specsToLogs(specs []string) ([]time.Time, []*loglist2.Log, error) {
@@ -117,7 +283,7 @@ func specToLog(logSpec string) (time.Time, *loglist2.Log, error) {
 	return t, logs[0], nil
 }
 
-func fetcherData(ctx context.Context, t time.Time, dm *dt.DomainMap, c chan<- dt.WorkerTransaction, logData *loglist2.Log, logIndex uint64) {
+func fetcherData(ctx context.Context, t time.Time, dm *dt.DomainMap, c chan<- dt.WorkerTransaction, ctLogs *ds.CTLogCache, readiness *ds.Readiness, control *ds.FetcherControl, logData *loglist2.Log, logIndex uint64) {
 	// Wait until log is active
 	// If t <= time.Now(), time.Sleep() will return immediately
 	time.Sleep(time.Until(t))
@@ -133,6 +299,9 @@ func fetcherData(ctx context.Context, t time.Time, dm *dt.DomainMap, c chan<- dt
 		STHCheckInterval: *sthUpdateInterval,
 		LogIndex:         logIndex,
 		LogClient:        lc,
+		CTLogs:           ctLogs,
+		Readiness:        readiness,
+		Control:          control,
 		C:                c,
 		ReturnOnError:    false,
 	}
@@ -142,7 +311,7 @@ func fetcherData(ctx context.Context, t time.Time, dm *dt.DomainMap, c chan<- dt
 	}
 }
 
-func handleInterrupts(cancel context.CancelFunc, svr *http.Server, svrStopped <-chan struct{}) {
+func handleInterrupts(cancel context.CancelFunc, svr, adminSvr *http.Server, grpcSvr *grpc.Server, svrStopped <-chan struct{}) {
 	c := make(chan os.Signal, 10)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGBUS, syscall.SIGPIPE)
 
@@ -161,6 +330,14 @@ shutdown:
 	fmt.Println("Shutting down server... Press Ctrl+C again to force quit")
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute))
 	defer cancel()
+	if grpcSvr != nil {
+		grpcSvr.GracefulStop()
+	}
+	if adminSvr != nil {
+		if err := adminSvr.Shutdown(ctx); err != nil {
+			fmt.Printf("Admin server shutdown error: %v\n", err)
+		}
+	}
 	if err := svr.Shutdown(ctx); err != nil {
 		fmt.Printf("Shutdown error: %v\n", err)
 	}
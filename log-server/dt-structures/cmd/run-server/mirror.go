@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	"github.com/google/certificate-transparency-go/logid"
+	"github.com/google/certificate-transparency-go/x509"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// A mirror follows an upstream map and replicates it into a read-only DomainMap.
+//
+// For each new upstream SMH, the mirror fetches the new source logs, the changed
+// domains and their new entries, and checks each entry against its CT log. Only then
+// does it add the entries to the domain trees, and it publishes the upstream SMH only if
+// the recomputed map root is the same. Errors while fetching are retried, but once the
+// mirror diverges from the upstream map (e.g. an invalid entry or a different root),
+// it stops following it.
+type mirror struct {
+	mc        mapclient.Client
+	dm        *dt.DomainMap
+	m         *dt.Mirror
+	ctLogs    *ds.CTLogCache
+	readiness *ds.Readiness
+	interval  time.Duration
+
+	// logClient returns the client of the CT log with the specified log ID.
+	logClient func(logID logid.LogID) (*client.LogClient, error)
+	// logClients are the clients of the source logs, by log index, used to verify the entries.
+	// The entries are fetched directly, so that the mirror doesn't fill ctLogs.
+	logClients map[uint64]*client.LogClient
+}
+
+// maxCTEntriesPerRequest is the maximum number of entries the mirror requests from a CT log at once.
+const maxCTEntriesPerRequest = 256
+
+// A divergenceError means that the upstream map is invalid or differs from the mirror.
+type divergenceError struct {
+	err error
+}
+
+func (e divergenceError) Error() string { return e.err.Error() }
+func (e divergenceError) Unwrap() error { return e.err }
+
+func diverged(format string, a ...interface{}) error {
+	return divergenceError{fmt.Errorf(format, a...)}
+}
+
+// A mirrorUpdate holds the verified changes between two upstream SMHs.
+type mirrorUpdate struct {
+	smh        *dt.SignedMapHead
+	sourceLogs []logid.LogID
+	domains    []string                             // changed domains, in order
+	entries    map[string][]dt.DomainTreeEntry      // new entries, by domain
+	roots      map[string]ds.DomainRootInMultiProof // new domain tree roots, by domain
+}
+
+// startMirror starts following the upstream map until ctx is cancelled or the mirror diverges.
+// Wait for the returned channel to be closed to ensure that the mirror has stopped.
+func startMirror(ctx context.Context, mr *mirror) <-chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		if err := mr.run(ctx); err != nil {
+			fmt.Printf("Stopped mirror: %v\n", err)
+		}
+		close(stopped)
+	}()
+	return stopped
+}
+
+func (mr *mirror) run(ctx context.Context) error {
+	for {
+		err := mr.update(ctx)
+		if errors.As(err, &divergenceError{}) {
+			if mr.readiness != nil {
+				mr.readiness.SetUpstreamError(err)
+			}
+			return err
+		} else if err != nil {
+			fmt.Printf("Mirror error: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(mr.interval):
+		}
+	}
+}
+
+// update mirrors every upstream SMH published after the latest SMH of the mirror.
+func (mr *mirror) update(ctx context.Context) error {
+	latest, err := mr.mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching the latest upstream SMH: %w", err)
+	}
+	current := mr.dm.GetLatestSMH()
+	if mr.readiness != nil {
+		mr.readiness.SetUpstreamProgress(latest.MapSize, current.MapSize)
+	}
+
+	if latest.MapSize < current.MapSize {
+		return diverged("upstream map size went back from %d to %d", current.MapSize, latest.MapSize)
+	}
+	if mr.dm.HasPublishedSMH() && latest.MapSize == current.MapSize {
+		if !bytes.Equal(latest.MapRootHash[:], current.MapRootHash[:]) {
+			return diverged("upstream map root changed without a change in map size (%d)", latest.MapSize)
+		}
+		if latest.Timestamp > current.Timestamp {
+			if err := mr.m.Publish(&latest.SignedMapHead); err != nil {
+				return divergenceError{fmt.Errorf("error republishing upstream SMH: %w", err)}
+			}
+		}
+		return mr.updateCheckpoint(ctx)
+	}
+
+	for current.MapSize < latest.MapSize {
+		resp, err := mr.mc.GetAndVerifySMHRange(ctx, current.MapSize+1, latest.MapSize)
+		if err != nil {
+			return fmt.Errorf("error fetching upstream SMHs: %w", err)
+		}
+		if len(resp.SMHs) == 0 {
+			return fmt.Errorf("error fetching upstream SMHs: no SMHs in [%d,%d]", current.MapSize+1, latest.MapSize)
+		}
+		for i := range resp.SMHs {
+			next := &resp.SMHs[i]
+			u, err := mr.fetchUpdate(ctx, current, next)
+			if err != nil {
+				return err
+			}
+			if err := mr.apply(u); err != nil {
+				return err
+			}
+			current = next
+			if mr.readiness != nil {
+				mr.readiness.SetUpstreamProgress(latest.MapSize, current.MapSize)
+			}
+			fmt.Printf("Mirrored SMH: size=%d, timestamp=%d, changed domains=%d\n", next.MapSize, next.Timestamp, len(u.domains))
+		}
+	}
+	return mr.updateCheckpoint(ctx)
+}
+
+// updateCheckpoint serves the upstream checkpoint, if it matches the latest SMH of the mirror.
+func (mr *mirror) updateCheckpoint(ctx context.Context) error {
+	note, err := mr.mc.GetCheckpoint(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching the upstream checkpoint: %w", err)
+	}
+	if err := mr.dm.AddUpstreamCheckpoint(note); err != nil {
+		return fmt.Errorf("error adding the upstream checkpoint: %w", err)
+	}
+	return nil
+}
+
+// fetchUpdate fetches and verifies the changes between the SMHs `prev` and `next`,
+// without modifying the mirror.
+func (mr *mirror) fetchUpdate(ctx context.Context, prev, next *dt.SignedMapHead) (*mirrorUpdate, error) {
+	if next.Version != dt.Version {
+		return nil, diverged("unsupported upstream SMH version %d", next.Version)
+	}
+	if len(next.SourceLogRevisions) < len(prev.SourceLogRevisions) {
+		return nil, diverged("upstream source logs went back from %d to %d", len(prev.SourceLogRevisions), len(next.SourceLogRevisions))
+	}
+	u := &mirrorUpdate{
+		smh:     next,
+		entries: make(map[string][]dt.DomainTreeEntry),
+		roots:   make(map[string]ds.DomainRootInMultiProof),
+	}
+
+	if n := uint64(len(next.SourceLogRevisions)); uint64(len(prev.SourceLogRevisions)) < n {
+		err := mr.mc.ForEachSourceLog(ctx, uint64(len(prev.SourceLogRevisions)), n-1, func(index uint64, id []byte) error {
+			var logID logid.LogID
+			if len(id) != len(logID) {
+				return diverged("invalid log ID for source log %d: length=%d, expected %d", index, len(id), len(logID))
+			}
+			copy(logID[:], id)
+			u.sourceLogs = append(u.sourceLogs, logID)
+			lc, err := mr.logClient(logID)
+			if err != nil {
+				return fmt.Errorf("error creating client for source log %d: %w", index, err)
+			}
+			if mr.logClients == nil {
+				mr.logClients = make(map[uint64]*client.LogClient)
+			}
+			mr.logClients[index] = lc
+			mr.ctLogs.AddLog(index, lc)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching upstream source logs: %w", err)
+		}
+	}
+
+	var first *ds.GetSMHResponse
+	if mr.dm.HasPublishedSMH() {
+		first = &ds.GetSMHResponse{SignedMapHead: *prev}
+	}
+	second := &ds.GetSMHResponse{SignedMapHead: *next}
+	for start, total := uint64(0), uint64(1); start < total; {
+		resp, err := mr.mc.GetAndVerifyChangedDomains(ctx, first, second, start)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching changed domains between map sizes %d and %d: %w", prev.MapSize, next.MapSize, err)
+		}
+		if len(resp.Second.Domains) == 0 && start < resp.Total {
+			return nil, fmt.Errorf("error fetching changed domains: no domains starting at %d", start)
+		}
+		for i, newRoot := range resp.Second.Domains {
+			domain := newRoot.NormalizedDomainName
+			if err := mr.fetchEntries(ctx, u, prev, domain, resp.First.Domains[i].DomainTreeSize, newRoot); err != nil {
+				return nil, err
+			}
+		}
+		start += uint64(len(resp.Second.Domains))
+		total = resp.Total
+	}
+	if err := mr.verifyEntries(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// fetchEntries fetches and verifies the new entries of the domain tree for `domain`.
+func (mr *mirror) fetchEntries(ctx context.Context, u *mirrorUpdate, prev *dt.SignedMapHead, domain string, oldSize uint64, newRoot ds.DomainRootInMultiProof) error {
+	oldRoot, err := mr.dm.GetDomainTreeRoot(prev.MapRootHash[:], domain)
+	if err != nil {
+		return diverged("invalid changed domain %q: %v", domain, err)
+	}
+	if oldSize != oldRoot.DomainTreeSize {
+		return diverged("domain tree for %q had size %d upstream, but has size %d in the mirror", domain, oldSize, oldRoot.DomainTreeSize)
+	}
+
+	var entries []dt.DomainTreeEntry
+	err = mr.mc.ForEachEntry(ctx, domain, oldSize, newRoot.DomainTreeSize-1, func(index uint64, entry dt.DomainTreeEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching upstream entries: %w", err)
+	}
+	u.domains = append(u.domains, domain)
+	u.entries[domain] = entries
+	u.roots[domain] = newRoot
+	return nil
+}
+
+// verifyEntries checks that the new entries of an update point to certificates for their
+// domains, and that the certificates are included in their CT logs at the tree sizes of the
+// SMH. The CT log entries are fetched in batches of contiguous indices, and the entries and
+// inclusion proofs of certificates in several domain trees are only fetched once.
+func (mr *mirror) verifyEntries(ctx context.Context, u *mirrorUpdate) error {
+	smh := u.smh
+	leafHashes := make(map[[2]uint64]ct.SHA256Hash)
+	indices := make(map[uint64][]uint64) // certificate indices, by log index
+	for _, domain := range u.domains {
+		for _, entry := range u.entries[domain] {
+			if entry.LogIndex >= uint64(len(smh.SourceLogRevisions)) {
+				return diverged("invalid log index %d in the domain tree for %q: the SMH has %d source logs", entry.LogIndex, domain, len(smh.SourceLogRevisions))
+			}
+			if rev := smh.SourceLogRevisions[entry.LogIndex]; entry.CertificateIndex >= rev.TreeSize {
+				return diverged("invalid certificate index %d: source log %d has %d entries", entry.CertificateIndex, entry.LogIndex, rev.TreeSize)
+			}
+			key := [2]uint64{entry.LogIndex, entry.CertificateIndex}
+			if _, ok := leafHashes[key]; !ok {
+				leafHashes[key] = entry.LeafHash
+				indices[entry.LogIndex] = append(indices[entry.LogIndex], entry.CertificateIndex)
+			}
+		}
+	}
+
+	logEntries := make(map[[2]uint64]*ct.LogEntry, len(leafHashes))
+	for logIndex, certIndices := range indices {
+		lc, ok := mr.logClients[logIndex]
+		if !ok {
+			return fmt.Errorf("no client for source log %d", logIndex)
+		}
+		sort.Slice(certIndices, func(i, j int) bool { return certIndices[i] < certIndices[j] })
+		for _, r := range contiguousRanges(certIndices, maxCTEntriesPerRequest) {
+			if err := mr.fetchLogEntries(ctx, lc, logIndex, r[0], r[1], logEntries); err != nil {
+				return err
+			}
+		}
+	}
+	for _, domain := range u.domains {
+		for _, entry := range u.entries[domain] {
+			if err := verifyEntry(domain, entry, logEntries[[2]uint64{entry.LogIndex, entry.CertificateIndex}]); err != nil {
+				return err
+			}
+		}
+	}
+
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	for key, leafHash := range leafHashes {
+		logIndex, certIndex := key[0], key[1]
+		rev := smh.SourceLogRevisions[logIndex]
+		resp, err := mr.logClients[logIndex].GetProofByHash(ctx, leafHash[:], rev.TreeSize)
+		if err != nil {
+			return fmt.Errorf("error fetching inclusion proof for entry %d of log %d: %w", certIndex, logIndex, err)
+		}
+		if resp.LeafIndex != int64(certIndex) {
+			return diverged("certificate %d of source log %d is at index %d", certIndex, logIndex, resp.LeafIndex)
+		}
+		if err := verifier.VerifyInclusionProof(int64(certIndex), int64(rev.TreeSize), resp.AuditPath, rev.RootHash[:], leafHash[:]); err != nil {
+			return diverged("certificate %d is not included in source log %d at tree size %d: %v", certIndex, logIndex, rev.TreeSize, err)
+		}
+	}
+	return nil
+}
+
+// contiguousRanges splits sorted indices into ranges [start, end] of contiguous indices,
+// with at most maxSize indices each.
+func contiguousRanges(indices []uint64, maxSize uint64) [][2]uint64 {
+	var ranges [][2]uint64
+	for i := 0; i < len(indices); {
+		start, end := indices[i], indices[i]
+		for i++; i < len(indices) && indices[i] == end+1 && indices[i]-start < maxSize; i++ {
+			end = indices[i]
+		}
+		ranges = append(ranges, [2]uint64{start, end})
+	}
+	return ranges
+}
+
+// fetchLogEntries fetches and parses the entries [start, end] of a source log.
+func (mr *mirror) fetchLogEntries(ctx context.Context, lc *client.LogClient, logIndex, start, end uint64, logEntries map[[2]uint64]*ct.LogEntry) error {
+	for start <= end {
+		resp, err := lc.GetRawEntries(ctx, int64(start), int64(end))
+		if err != nil {
+			return fmt.Errorf("error fetching entries [%d,%d] of log %d: %w", start, end, logIndex, err)
+		}
+		if len(resp.Entries) == 0 || uint64(len(resp.Entries)) > end-start+1 {
+			return fmt.Errorf("error fetching entries [%d,%d] of log %d: got %d entries", start, end, logIndex, len(resp.Entries))
+		}
+		for i := range resp.Entries {
+			certIndex := start + uint64(i)
+			logEntry, err := ct.LogEntryFromLeaf(int64(certIndex), &resp.Entries[i])
+			if logEntry == nil || x509.IsFatal(err) {
+				return diverged("invalid entry %d of source log %d: %v", certIndex, logIndex, err)
+			}
+			// Non-fatal parsing errors are accepted, as by the fetchers of the upstream map
+			logEntries[[2]uint64{logIndex, certIndex}] = logEntry
+		}
+		start += uint64(len(resp.Entries))
+	}
+	return nil
+}
+
+// verifyEntry checks that a domain tree entry matches its CT log entry,
+// which must contain a certificate for `domain`.
+func verifyEntry(domain string, entry dt.DomainTreeEntry, logEntry *ct.LogEntry) error {
+	hashes, err := ds.HashesForLogEntry(logEntry)
+	if err != nil {
+		return diverged("error hashing CT log entry: %v", err)
+	}
+	if hashes.LeafHash != entry.LeafHash || hashes.Fingerprint != entry.CertificateFingerprint {
+		return diverged("the hashes of the entry do not match certificate %d of source log %d", entry.CertificateIndex, entry.LogIndex)
+	}
+	var cert *x509.Certificate
+	if logEntry.X509Cert != nil {
+		cert = logEntry.X509Cert
+	} else if logEntry.Precert != nil {
+		cert = logEntry.Precert.TBSCertificate
+	}
+	if cert == nil || !containsDomain(ds.CertificateDomainNames(cert), domain) {
+		return diverged("certificate %d of source log %d is not for %q", entry.CertificateIndex, entry.LogIndex, domain)
+	}
+	return nil
+}
+
+func containsDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// apply adds a verified update to the mirror and publishes its SMH.
+// Since the mirror is modified, any error is a divergence.
+func (mr *mirror) apply(u *mirrorUpdate) error {
+	for _, logID := range u.sourceLogs {
+		mr.m.AddSourceLog(logID)
+	}
+	for _, domain := range u.domains {
+		root, err := mr.m.AddEntries(domain, u.entries[domain])
+		if err != nil {
+			return divergenceError{err}
+		}
+		expected := u.roots[domain]
+		if root.DomainTreeSize != expected.DomainTreeSize || !bytes.Equal(root.DomainTreeRootHash[:], expected.DomainTreeRootHash) {
+			return diverged("recomputed domain tree root for %q (size %d) differs from the upstream map", domain, root.DomainTreeSize)
+		}
+	}
+	if err := mr.m.Publish(u.smh); err != nil {
+		return divergenceError{fmt.Errorf("error publishing upstream SMH: %w", err)}
+	}
+	return nil
+}
+
+// loglistClient returns the client of a CT log listed in the loglist.json file.
+func loglistClient(logID logid.LogID) (*client.LogClient, error) {
+	logs := util.FindLogs(base64.StdEncoding.EncodeToString(logID[:]))
+	if len(logs) != 1 {
+		return nil, fmt.Errorf("log %s: got %d matches in the loglist", base64.StdEncoding.EncodeToString(logID[:]), len(logs))
+	}
+	return client.New(logs[0].URL, http.DefaultClient, jsonclient.Options{PublicKeyDER: logs[0].Key})
+}
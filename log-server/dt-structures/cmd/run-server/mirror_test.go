@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/logid"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+func TestContiguousRanges(t *testing.T) {
+	tests := []struct {
+		indices []uint64
+		maxSize uint64
+		want    [][2]uint64
+	}{
+		{nil, 4, nil},
+		{[]uint64{3}, 4, [][2]uint64{{3, 3}}},
+		{[]uint64{1, 2, 3, 5, 6, 9}, 4, [][2]uint64{{1, 3}, {5, 6}, {9, 9}}},
+		{[]uint64{0, 1, 2, 3, 4, 5, 6, 7, 8}, 4, [][2]uint64{{0, 3}, {4, 7}, {8, 8}}},
+	}
+	for _, tt := range tests {
+		if got := contiguousRanges(tt.indices, tt.maxSize); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("contiguousRanges(%v, %d): got %v, expected %v", tt.indices, tt.maxSize, got, tt.want)
+		}
+	}
+}
+
+// newUpstreamMap starts a map with `size` entries of log 0 for a.com and b.com, whose leaf
+// hashes depend on `salt`, and serves it over HTTP.
+func newUpstreamMap(t *testing.T, key *ecdsa.PrivateKey, size uint64, salt byte) (*dt.DomainMap, *httptest.Server) {
+	dm := dt.NewDomainMap(key)
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	t.Cleanup(func() { cancel(); <-stopped })
+
+	tx := dt.WorkerTransaction{
+		LogID:                  logid.LogID{1},
+		LogRevision:            dt.LogRevision{TreeSize: size},
+		NewCertificatesIndices: make(map[string][]uint64),
+		CertificateHashes:      make(map[uint64]dt.CertificateHashes),
+	}
+	for i := uint64(0); i < size; i++ {
+		d := []string{"a.com", "b.com"}[i%2]
+		tx.NewCertificatesIndices[d] = append(tx.NewCertificatesIndices[d], i)
+		tx.CertificateHashes[i] = dt.CertificateHashes{LeafHash: ct.SHA256Hash{byte(i), salt, 1}}
+	}
+	c <- tx
+	for deadline := time.Now().Add(10 * time.Second); dm.GetLatestSMH().MapSize != size; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for an SMH")
+		}
+	}
+	svr, _ := ds.NewServer(dm, "localhost", 0)
+	hs := httptest.NewServer(svr.Handler)
+	t.Cleanup(hs.Close)
+	return dm, hs
+}
+
+// newMirrorOf returns a mirror that has replicated the latest SMH of the upstream map.
+func newMirrorOf(t *testing.T, upstream *dt.DomainMap, key *ecdsa.PrivateKey) (*dt.DomainMap, *dt.Mirror) {
+	mdm := dt.NewMirrorDomainMap(&key.PublicKey)
+	m := dt.NewMirror(mdm)
+	smh := upstream.GetLatestSMH()
+	m.AddSourceLog(logid.LogID{1})
+	for _, d := range []string{"a.com", "b.com"} {
+		tree, err := upstream.GetDomainTree(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := upstream.GetDomainTreeRoot(smh.MapRootHash[:], d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := tree.GetEntries(0, root.DomainTreeSize-1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.AddEntries(d, entries); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Publish(smh); err != nil {
+		t.Fatal(err)
+	}
+	return mdm, m
+}
+
+func TestMirrorDivergence(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, _ := newUpstreamMap(t, key, 6, 0)
+	// other maps signed with the same key
+	_, smaller := newUpstreamMap(t, key, 4, 0)
+	_, forked := newUpstreamMap(t, key, 6, 1)
+	_, closed := newUpstreamMap(t, key, 6, 0)
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		upstream  *httptest.Server
+		divergent bool
+	}{
+		{"smaller map", smaller, true},
+		{"different root", forked, true},
+		{"unreachable upstream", closed, false},
+	}
+	for _, tt := range tests {
+		mdm, m := newMirrorOf(t, original, key)
+		mc := mapclient.New(tt.upstream.URL+"/", tt.upstream.Client(), &key.PublicKey)
+		mc.SetRetryPolicy(mapclient.RetryPolicy{})
+		readiness := ds.NewReadiness(0)
+		mr := &mirror{mc: mc, dm: mdm, m: m, ctLogs: ds.NewCTLogCache(), readiness: readiness, interval: time.Millisecond}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		err := mr.run(ctx)
+		cancel()
+		if divergent := errors.As(err, &divergenceError{}); divergent != tt.divergent {
+			t.Errorf("%s: got error %v, expected a divergence: %v", tt.name, err, tt.divergent)
+		}
+		if !tt.divergent && !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s: got error %v, expected the mirror to run until the context is done", tt.name, err)
+		}
+		if mdm.GetLatestSMH().MapSize != 6 {
+			t.Errorf("%s: the mirror changed its map size to %d", tt.name, mdm.GetLatestSMH().MapSize)
+		}
+	}
+}
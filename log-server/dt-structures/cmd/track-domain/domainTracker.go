@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,110 +12,179 @@ import (
 	ct "github.com/google/certificate-transparency-go"
 	"github.com/google/certificate-transparency-go/client"
 	"github.com/google/certificate-transparency-go/jsonclient"
-	"github.com/google/certificate-transparency-go/tls"
 	"github.com/google/certificate-transparency-go/x509"
 	"github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
 	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
 	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
 	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Update struct {
-	Cert      *x509.Certificate
-	Domains   []string
-	LogIndex  uint64
-	LeafIndex uint64
+	Cert        *x509.Certificate
+	Fingerprint ct.SHA256Hash
+	Domains     []string
+	LogIndex    uint64
+	LeafIndex   uint64
 }
 
 type DomainTracker struct {
-	mc      *mapclient.MapClient
+	mc      mapclient.Client
+	smhs    *mapclient.SMHStore // checks and records every SMH returned by the map
 	domains []string
 
 	lastTreeSizes map[string]uint64
+	lastTreeRoots map[string][]byte
 	smh           *ds.GetSMHResponse
-	logClients    []*client.LogClient
-	verifier      merkle.LogVerifier
+	promises      []*ds.GetInclusionPromiseResponse
+
+	// used to fetch the entries from the CT logs when the map doesn't serve them (see getTreeUpdatesFromCT)
+	logClients []*client.LogClient
+	verifier   merkle.LogVerifier
+
+	// receives a value whenever the map publishes an SMH, if subscribed (see Subscribe)
+	smhNotifications chan struct{}
+
+	// certificates with these fingerprints are expected, so they are neither fetched nor reported
+	knownFingerprints map[ct.SHA256Hash]struct{}
 }
 
-func NewDomainTracker(mc *mapclient.MapClient, domains []string) *DomainTracker {
+func NewDomainTracker(mc mapclient.Client, smhs *mapclient.SMHStore, domains []string, knownFingerprints []ct.SHA256Hash) *DomainTracker {
+	known := make(map[ct.SHA256Hash]struct{}, len(knownFingerprints))
+	for _, fp := range knownFingerprints {
+		known[fp] = struct{}{}
+	}
 	return &DomainTracker{
 		mc:      mc,
+		smhs:    smhs,
 		domains: domains,
 
+		knownFingerprints: known,
+
 		lastTreeSizes: make(map[string]uint64),
+		lastTreeRoots: make(map[string][]byte),
 		smh:           nil,
-		logClients:    nil,
 		verifier:      merkle.NewLogVerifier(rfc6962.DefaultHasher),
 	}
 }
 
-func (t *DomainTracker) FetchSMH() (updated bool, err error) {
-	smh, err := t.mc.GetAndVerifySMH()
+func (t *DomainTracker) FetchSMH(ctx context.Context) (updated bool, err error) {
+	smh, err := t.mc.GetAndVerifySMH(ctx)
 	if err != nil {
 		return false, err
 	}
 	if t.smh != nil && bytes.Equal(t.smh.MapHeadSignature, smh.MapHeadSignature) {
 		return false, nil
 	}
-	t.smh = smh
+	if err := t.smhs.Add(ctx, smh); errors.Is(err, mapclient.ErrStaleSMH) {
+		// an older copy of the latest SMH, e.g. from a cache
+		fmt.Printf("Ignoring SMH: %v\n", err)
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("inconsistent SMH (map size %d): %w", smh.MapSize, err)
+	}
+	// the store keeps its own copy of an SMH republished with the same timestamp
+	latest := t.smhs.Latest()
+	if t.smh != nil && bytes.Equal(t.smh.MapHeadSignature, latest.MapHeadSignature) {
+		return false, nil
+	}
+	t.smh = latest
 	return true, nil
 }
 
-func (t *DomainTracker) WaitForSMH(fetchInterval time.Duration) error {
+// subscriptionPollInterval is how often WaitForSMH polls the map while subscribed,
+// in case the subscription stopped without an error.
+const subscriptionPollInterval = time.Minute
+
+func (t *DomainTracker) WaitForSMH(ctx context.Context, fetchInterval time.Duration) error {
 	for {
-		updated, err := t.FetchSMH()
+		updated, err := t.FetchSMH(ctx)
 		if err != nil {
 			return err
 		} else if updated {
 			return nil
 		}
-		time.Sleep(fetchInterval)
+		if t.smhNotifications == nil {
+			time.Sleep(fetchInterval)
+			continue
+		}
+		select {
+		case <-t.smhNotifications:
+		case <-time.After(subscriptionPollInterval):
+		}
 	}
 }
 
-func (t *DomainTracker) GetClient(logIndex uint64) (*client.LogClient, error) {
-	for uint64(len(t.logClients)) <= logIndex {
-		t.logClients = append(t.logClients, nil)
-	}
-	if t.logClients[logIndex] != nil {
-		return t.logClients[logIndex], nil
-	}
-	resp, err := t.mc.GetSourceLogAndProof(&ds.GetSourceLogAndProofRequest{
-		Index:          logIndex,
-		SourceTreeSize: uint64(len(t.smh.SourceLogRevisions)),
-	})
-	if err != nil {
-		return nil, err
-	}
-	var id [32]byte
-	copy(id[:], resp.LogID)
-	log := util.GetLogList().FindLogByKeyHash(id)
-	if log == nil {
-		return nil, fmt.Errorf("unknown log with key %x", id)
+// Subscribe subscribes to the SMHs published by the map, so that WaitForSMH
+// waits for them instead of polling the map every fetchInterval.
+// If the subscription fails (e.g. the map doesn't support it), it is retried
+// every retryInterval, and WaitForSMH polls the map after each attempt.
+func (t *DomainTracker) Subscribe(ctx context.Context, retryInterval time.Duration) {
+	t.smhNotifications = make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case t.smhNotifications <- struct{}{}:
+		default:
+		}
 	}
-	return client.New(log.URL, http.DefaultClient, jsonclient.Options{PublicKeyDER: log.Key})
+	go func() {
+		failing := false
+		for ctx.Err() == nil {
+			err := t.mc.Subscribe(ctx, nil, func(e *mapclient.SubscriptionEvent) error {
+				if failing {
+					log.Printf("Subscribed to new SMHs")
+					failing = false
+				}
+				if e.SMH != nil {
+					notify()
+				}
+				return nil
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if !failing && !errors.Is(err, mapclient.ErrSubscriptionClosed) {
+				log.Printf("Error subscribing to new SMHs (polling instead): %v", err)
+				failing = true
+			}
+			notify()
+			time.Sleep(retryInterval)
+		}
+	}()
 }
 
-func (t *DomainTracker) UpdateDomainTreeRoots(returnUpdates bool) []*Update {
+// maxDomainsPerRequest is the maximum number of domains in each multiproof request.
+const maxDomainsPerRequest = 256
+
+func (t *DomainTracker) UpdateDomainTreeRoots(ctx context.Context, returnUpdates bool) []*Update {
 	updatesMap := make(map[[2]uint64]*Update)
-	for _, d := range t.domains {
-		domainRoot, err := t.mc.GetDomainRootAndProof(&ds.GetDomainRootAndProofRequest{
-			DomainName:    d,
-			DomainMapSize: t.smh.MapSize,
-		})
+	for start := 0; start < len(t.domains); start += maxDomainsPerRequest {
+		end := start + maxDomainsPerRequest
+		if end > len(t.domains) {
+			end = len(t.domains)
+		}
+		resp, err := t.mc.GetAndVerifyDomainRootsAndMultiProof(ctx, t.domains[start:end], t.smh)
 		if err != nil {
-			log.Printf("Error getting domain tree root for %q: %v", d, err)
+			log.Printf("Error getting domain tree roots for %d domains: %v", end-start, err)
 			continue
 		}
-		if returnUpdates {
-			err := t.getTreeUpdates(domainRoot, t.lastTreeSizes[d], domainRoot.DomainTreeSize, updatesMap)
-			if err != nil {
+		for i := range resp.Domains {
+			domainRoot := &resp.Domains[i]
+			d := domainRoot.NormalizedDomainName
+			if returnUpdates {
+				if err := t.getTreeUpdates(ctx, domainRoot, updatesMap); err != nil {
+					log.Printf("Error updating tree for %q: %v", d, err)
+					continue
+				}
+			} else if err := t.mc.GetAndVerifyConsistencyProof(ctx, d, t.lastTreeSizes[d], t.lastTreeRoots[d], domainRoot.DomainTreeSize, domainRoot.DomainTreeRootHash); err != nil {
 				log.Printf("Error updating tree for %q: %v", d, err)
 				continue
 			}
-		} else {
 			t.lastTreeSizes[d] = domainRoot.DomainTreeSize
+			t.lastTreeRoots[d] = domainRoot.DomainTreeRootHash
 		}
 	}
 	updates := make([]*Update, 0, len(updatesMap))
@@ -124,67 +194,217 @@ func (t *DomainTracker) UpdateDomainTreeRoots(returnUpdates bool) []*Update {
 	return updates
 }
 
-func (t *DomainTracker) getTreeUpdates(domainRoot *ds.GetDomainRootAndProofResponse, from, to uint64, updatesMap map[[2]uint64]*Update) error {
-	for i := from; i < to; i++ {
-		if err := t.getTreeUpdate(domainRoot, i, updatesMap); err != nil {
+// getTreeUpdates fetches and verifies the entries added to a domain tree since its last known size.
+func (t *DomainTracker) getTreeUpdates(ctx context.Context, domainRoot *ds.DomainRootInMultiProof, updatesMap map[[2]uint64]*Update) error {
+	d := domainRoot.NormalizedDomainName
+	from := t.lastTreeSizes[d]
+	if from >= domainRoot.DomainTreeSize {
+		// No new entries (get-domain-updates checks consistency otherwise)
+		return t.mc.GetAndVerifyConsistencyProof(ctx, d, from, t.lastTreeRoots[d], domainRoot.DomainTreeSize, domainRoot.DomainTreeRootHash)
+	}
+	for start := from; start < domainRoot.DomainTreeSize; {
+		req := &ds.GetDomainUpdatesRequest{
+			DomainName: d,
+			From:       from,
+			MapSize:    t.smh.MapSize,
+			Start:      start,
+		}
+		bundle, updates, err := t.mc.GetAndVerifyDomainUpdates(ctx, req, t.lastTreeRoots[d])
+		if err != nil {
+			if domainUpdatesUnsupported(err) {
+				log.Printf("Fetching the entries of %q from the CT logs: get-domain-updates failed: %v", d, err)
+				return t.getTreeUpdatesFromCT(ctx, domainRoot, start, updatesMap)
+			}
 			return err
 		}
-		t.lastTreeSizes[domainRoot.NormalizedDomainName] = i + 1
+		if bundle.SMH.MapRootHash != t.smh.MapRootHash {
+			return fmt.Errorf("domain updates refer to a different map root: %x != %x", bundle.SMH.MapRootHash, t.smh.MapRootHash)
+		}
+		if len(updates) == 0 {
+			return fmt.Errorf("no entries returned starting at %d", start)
+		}
+		for i := range updates {
+			if err := t.addUpdate(d, &updates[i], updatesMap); err != nil {
+				return err
+			}
+		}
+		start += uint64(len(updates))
 	}
 	return nil
 }
 
-func (t *DomainTracker) getTreeUpdate(domainRoot *ds.GetDomainRootAndProofResponse, index uint64, updatesMap map[[2]uint64]*Update) error {
-	entryAndProof, err := t.mc.GetEntryAndProof(&ds.GetEntryAndProofRequest{
-		DomainName:     domainRoot.NormalizedDomainName,
-		DomainTreeSize: domainRoot.DomainTreeSize,
-		Index:          index,
-	})
+// domainUpdatesUnsupported returns whether get-domain-updates failed because the map doesn't
+// support it (e.g. older servers) or can't serve the CT entries, in which case the tracker
+// fetches the entries from the CT logs itself.
+func domainUpdatesUnsupported(err error) bool {
+	return errors.Is(err, mapclient.ErrUnavailable) || errors.Is(err, mapclient.ErrNotFound) ||
+		status.Code(err) == codes.Unimplemented
+}
+
+// getLogClient returns a client for the CT log with the specified index in the source tree.
+func (t *DomainTracker) getLogClient(ctx context.Context, logIndex uint64) (*client.LogClient, error) {
+	for uint64(len(t.logClients)) <= logIndex {
+		t.logClients = append(t.logClients, nil)
+	}
+	if t.logClients[logIndex] != nil {
+		return t.logClients[logIndex], nil
+	}
+	logID, err := t.mc.GetAndVerifySourceLogAndProof(ctx, logIndex, t.smh)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	update, ok := updatesMap[entryAndProof.Entry]
-	if ok {
-		update.Domains = append(update.Domains, domainRoot.NormalizedDomainName)
-		return nil
+	var id [32]byte
+	copy(id[:], logID)
+	log := util.GetLogList().FindLogByKeyHash(id)
+	if log == nil {
+		return nil, fmt.Errorf("unknown log with key %x", id)
 	}
-	update = &Update{
-		Domains:   []string{domainRoot.NormalizedDomainName},
-		LogIndex:  entryAndProof.Entry[0],
-		LeafIndex: entryAndProof.Entry[1],
+	lc, err := client.New(log.URL, http.DefaultClient, jsonclient.Options{PublicKeyDER: log.Key})
+	if err != nil {
+		return nil, err
 	}
-	client, err := t.GetClient(update.LogIndex)
+	t.logClients[logIndex] = lc
+	return lc, nil
+}
+
+// getTreeUpdatesFromCT fetches and verifies the entries of a domain tree from index start,
+// one at a time, fetching the certificates from the CT logs.
+func (t *DomainTracker) getTreeUpdatesFromCT(ctx context.Context, domainRoot *ds.DomainRootInMultiProof, start uint64, updatesMap map[[2]uint64]*Update) error {
+	d := domainRoot.NormalizedDomainName
+	err := t.mc.GetAndVerifyConsistencyProof(ctx, d, t.lastTreeSizes[d], t.lastTreeRoots[d], domainRoot.DomainTreeSize, domainRoot.DomainTreeRootHash)
 	if err != nil {
 		return err
 	}
-	resp, err := client.GetEntryAndProof(context.Background(), update.LeafIndex, t.smh.SourceLogRevisions[update.LogIndex].TreeSize)
+	for i := start; i < domainRoot.DomainTreeSize; i++ {
+		entry, err := t.mc.GetAndVerifyEntryAndProof(ctx, &ds.GetEntryAndProofRequest{
+			DomainName:     d,
+			DomainTreeSize: domainRoot.DomainTreeSize,
+			Index:          i,
+		}, t.smh.Version, domainRoot.DomainTreeRootHash)
+		if err != nil {
+			return err
+		}
+		if entry.LogIndex >= uint64(len(t.smh.SourceLogRevisions)) {
+			return fmt.Errorf("entry (%d,%d) refers to an unknown log", entry.LogIndex, entry.CertificateIndex)
+		}
+		if t.smh.Version >= dt.VersionV2 {
+			// known certificates need not be fetched from the CT log
+			if _, ok := t.knownFingerprints[entry.CertificateFingerprint]; ok {
+				continue
+			}
+		}
+		logEntry, err := t.getCTEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+		if err := t.addUpdate(d, &mapclient.DomainUpdate{Index: i, Entry: entry, LogEntry: logEntry}, updatesMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCTEntry fetches the CT log entry of a domain tree entry and verifies it against the
+// source log revision in the current SMH and, for v2 maps, the hashes in the domain tree entry.
+func (t *DomainTracker) getCTEntry(ctx context.Context, entry dt.DomainTreeEntry) (*ct.LogEntry, error) {
+	lc, err := t.getLogClient(ctx, entry.LogIndex)
 	if err != nil {
-		log.Panic(err)
+		return nil, err
 	}
-	var leaf ct.MerkleTreeLeaf
-	_, err = tls.Unmarshal(resp.LeafInput, &leaf)
+	rev := t.smh.SourceLogRevisions[entry.LogIndex]
+	resp, err := lc.GetEntryAndProof(ctx, entry.CertificateIndex, rev.TreeSize)
 	if err != nil {
-		return fmt.Errorf("error unmarshalling log entry: %w", err)
+		return nil, fmt.Errorf("error fetching CT entry (%d,%d): %w", entry.LogIndex, entry.CertificateIndex, err)
 	}
-	logLeafHash, err := ct.LeafHashForLeaf(&leaf)
+	logEntry, err := ct.LogEntryFromLeaf(int64(entry.CertificateIndex), &ct.LeafEntry{LeafInput: resp.LeafInput, ExtraData: resp.ExtraData})
+	if logEntry == nil {
+		return nil, fmt.Errorf("error parsing CT entry (%d,%d): %w", entry.LogIndex, entry.CertificateIndex, err)
+	}
+	hashes, err := ds.HashesForLogEntry(logEntry)
 	if err != nil {
-		return fmt.Errorf("error calculating leaf hash: %w", err)
+		return nil, fmt.Errorf("error hashing CT entry (%d,%d): %w", entry.LogIndex, entry.CertificateIndex, err)
 	}
-	err = t.verifier.VerifyInclusionProof(int64(update.LeafIndex), int64(t.smh.SourceLogRevisions[update.LogIndex].TreeSize), resp.AuditPath, t.smh.SourceLogRevisions[update.LogIndex].RootHash[:], logLeafHash[:])
+	err = t.verifier.VerifyInclusionProof(int64(entry.CertificateIndex), int64(rev.TreeSize), resp.AuditPath, rev.RootHash[:], hashes.LeafHash[:])
 	if err != nil {
-		return fmt.Errorf("error verifying CT audit proof: %w", err)
+		return nil, fmt.Errorf("error verifying CT audit proof of entry (%d,%d): %w", entry.LogIndex, entry.CertificateIndex, err)
+	}
+	if t.smh.Version >= dt.VersionV2 {
+		if hashes.LeafHash != entry.LeafHash {
+			return nil, fmt.Errorf("CT leaf hash of entry (%d,%d) does not match the domain tree: %x != %x", entry.LogIndex, entry.CertificateIndex, hashes.LeafHash, entry.LeafHash)
+		}
+		if hashes.Fingerprint != entry.CertificateFingerprint {
+			return nil, fmt.Errorf("certificate fingerprint of entry (%d,%d) does not match the domain tree: %x != %x", entry.LogIndex, entry.CertificateIndex, hashes.Fingerprint, entry.CertificateFingerprint)
+		}
+	}
+	return logEntry, nil
+}
+
+func (t *DomainTracker) addUpdate(domain string, du *mapclient.DomainUpdate, updatesMap map[[2]uint64]*Update) error {
+	entry := du.Entry
+	if t.smh.Version >= dt.VersionV2 {
+		// The map commits to the certificate's fingerprint, so known certificates
+		// need not be reported.
+		if _, ok := t.knownFingerprints[entry.CertificateFingerprint]; ok {
+			return nil
+		}
+	}
+
+	key := [2]uint64{entry.LogIndex, entry.CertificateIndex}
+	update, ok := updatesMap[key]
+	if ok {
+		update.Domains = append(update.Domains, domain)
+		return nil
 	}
-	if leaf.TimestampedEntry.EntryType == ct.X509LogEntryType {
-		update.Cert, err = leaf.X509Certificate()
-	} else if leaf.TimestampedEntry.EntryType == ct.PrecertLogEntryType {
-		update.Cert, err = leaf.Precertificate()
+	update = &Update{
+		Domains:   []string{domain},
+		LogIndex:  entry.LogIndex,
+		LeafIndex: entry.CertificateIndex,
+	}
+	if du.LogEntry.X509Cert != nil {
+		update.Cert = du.LogEntry.X509Cert
+	} else if du.LogEntry.Precert != nil {
+		update.Cert = du.LogEntry.Precert.TBSCertificate
 	} else {
 		log.Printf("Entry (%d,%d) has json type, skipping", update.LogIndex, update.LeafIndex)
 		return nil
 	}
+	hashes, err := ds.HashesForLogEntry(du.LogEntry)
 	if err != nil {
-		return fmt.Errorf("error parsing certificate: %w", err)
+		return fmt.Errorf("error hashing log entry: %w", err)
 	}
-	updatesMap[entryAndProof.Entry] = update
+	update.Fingerprint = hashes.Fingerprint
+	updatesMap[key] = update
 	return nil
 }
+
+// AddInclusionPromise fetches and verifies an inclusion promise,
+// which will be checked against subsequent SMHs by CheckInclusionPromises.
+func (t *DomainTracker) AddInclusionPromise(ctx context.Context, req *ds.GetInclusionPromiseRequest) (*ds.GetInclusionPromiseResponse, error) {
+	promise, err := t.mc.GetAndVerifyInclusionPromise(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	t.promises = append(t.promises, promise)
+	return promise, nil
+}
+
+// CheckInclusionPromises checks all pending inclusion promises that are due, i.e. that are
+// older than the current SMH, against the first SMH published after each promise
+// (see mapclient.CheckInclusionPromise).
+// Promises that were either fulfilled or broken are removed from the pending list.
+func (t *DomainTracker) CheckInclusionPromises(ctx context.Context, mmd time.Duration) {
+	pending := t.promises[:0]
+	for _, promise := range t.promises {
+		fulfilled, err := t.mc.CheckInclusionPromise(ctx, promise, t.smh, mmd)
+		if err != nil {
+			log.Printf("Broken inclusion promise for entry (%d,%d) in %q (timestamp=%d): %v",
+				promise.LogIndex, promise.CertificateIndex, promise.NormalizedDomainName, promise.Timestamp, err)
+		} else if fulfilled {
+			log.Printf("Inclusion promise fulfilled for entry (%d,%d) in %q (timestamp=%d)",
+				promise.LogIndex, promise.CertificateIndex, promise.NormalizedDomainName, promise.Timestamp)
+		} else {
+			pending = append(pending, promise)
+		}
+	}
+	t.promises = pending
+}
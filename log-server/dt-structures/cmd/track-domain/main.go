@@ -1,30 +1,51 @@
 package main
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	ct "github.com/google/certificate-transparency-go"
 	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+	"google.golang.org/grpc"
 )
 
 var (
 	cmd       = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	mapURI    = cmd.String("map_uri", "http://127.0.0.1:8021/", "")
+	mapGRPC   = cmd.String("map_grpc", "", "the address of the map's gRPC API (e.g. 127.0.0.1:8022); if set, it is used instead of --map_uri")
 	mapKeyPEM = cmd.String("map_key", "config/publickey.pem", "the map's public key")
-	interval  = cmd.Duration("interval", 2*time.Second, "")
+	interval  = cmd.Duration("interval", 2*time.Second, "how often to poll the map for new SMHs, if the map doesn't support subscriptions")
+	mmd       = cmd.Duration("mmd", 60*time.Second, "the map's max interval between SMHs, used to check inclusion promises")
 	verbose   = cmd.Bool("verbose", false, "")
+	smhStore  = cmd.String("smh_store", "config/smh-store.json", "the file where the SMHs returned by the map are recorded, so that rollbacks and forks are detected across restarts; empty to keep them only in memory")
 
-	domains []string
+	witnessesRequired = cmd.Int("witnesses_required", 0, "require SMHs to be cosigned by at least this many of the witnesses specified with --witness_key")
+
+	domains           []string
+	promises          []string
+	witnessKeys       []string
+	knownFingerprints []string
 )
 
 func init() {
 	cmd.Var((*stringSliceFlags)(&domains), "domain", "track the specified domain (repeatable)")
+	cmd.Var((*stringSliceFlags)(&promises), "promise", "request and check an inclusion promise, as LOG_INDEX:CERTIFICATE_INDEX:DOMAIN (repeatable)")
+	cmd.Var((*stringSliceFlags)(&witnessKeys), "witness_key", "the pem file with the public key of a trusted witness (repeatable)")
+	cmd.Var((*stringSliceFlags)(&knownFingerprints), "known_fingerprint", "the hex SHA-256 fingerprint of an expected certificate, which won't be fetched nor reported; requires a version 2 map (repeatable)")
 
 	log.SetFlags(log.Ldate | log.Ltime)
 }
@@ -37,31 +58,94 @@ func main() {
 		return
 	}
 
-	mapPubKey, err := loadPublicKey(*mapKeyPEM)
+	mapPubKey, err := util.LoadPublicKey(*mapKeyPEM)
 	if err != nil {
 		fmt.Printf("Error loading public key: %v\n", err)
 		return
 	}
 
-	mc := mapclient.New(*mapURI, http.DefaultClient, mapPubKey)
-	tracker := NewDomainTracker(mc, domains)
+	var mc *mapclient.MapClient
+	if *mapGRPC != "" {
+		conn, err := grpc.Dial(*mapGRPC, grpc.WithInsecure())
+		if err != nil {
+			fmt.Printf("Error connecting to %s: %v\n", *mapGRPC, err)
+			return
+		}
+		defer conn.Close()
+		mc = mapclient.NewGRPC(conn, mapPubKey)
+	} else {
+		mc = mapclient.New(*mapURI, http.DefaultClient, mapPubKey)
+	}
+	if *witnessesRequired > 0 {
+		keys := make([]*ecdsa.PublicKey, len(witnessKeys))
+		for i, pemfile := range witnessKeys {
+			if keys[i], err = util.LoadPublicKey(pemfile); err != nil {
+				fmt.Printf("Error loading witness key: %v\n", err)
+				return
+			}
+		}
+		policy, err := mapclient.NewWitnessPolicy(*witnessesRequired, keys)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		mc.RequireWitnesses(policy)
+	}
+	fingerprints := make([]ct.SHA256Hash, len(knownFingerprints))
+	for i, fp := range knownFingerprints {
+		bs, err := hex.DecodeString(strings.ReplaceAll(fp, ":", ""))
+		if err != nil || len(bs) != sha256.Size {
+			fmt.Printf("Invalid certificate fingerprint %q\n", fp)
+			return
+		}
+		copy(fingerprints[i][:], bs)
+	}
+	smhs, err := mapclient.OpenSMHStore(mc, *smhStore)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if latest := smhs.Latest(); latest != nil {
+		log.Printf("Recorded SMHs up to map size %d (timestamp %d)", latest.MapSize, latest.Timestamp)
+	}
+	for _, evidence := range smhs.Evidence() {
+		data, _ := json.Marshal(evidence)
+		log.Printf("Previously detected %v\nEvidence: %s", evidence, data)
+	}
+	tracker := NewDomainTracker(mc, smhs, domains, fingerprints)
 
 	// init the tracker
+	ctx := context.Background()
 	for {
-		if _, err := tracker.FetchSMH(); err != nil {
-			log.Printf("Error fetching SMH: %v", err)
+		if _, err := tracker.FetchSMH(ctx); err != nil {
+			logSMHError(err)
 			time.Sleep(*interval)
 		} else {
 			break
 		}
 	}
-	tracker.UpdateDomainTreeRoots(false)
+	tracker.UpdateDomainTreeRoots(ctx, false)
+	for _, p := range promises {
+		req, err := parsePromiseSpecifier(p)
+		if err != nil {
+			log.Printf("Error parsing promise %q: %v", p, err)
+			continue
+		}
+		promise, err := tracker.AddInclusionPromise(ctx, req)
+		if err != nil {
+			log.Printf("Error fetching inclusion promise %q: %v", p, err)
+			continue
+		}
+		log.Printf("Got inclusion promise for entry (%d,%d) in %q (timestamp=%d)",
+			promise.LogIndex, promise.CertificateIndex, promise.NormalizedDomainName, promise.Timestamp)
+	}
+	tracker.Subscribe(ctx, *interval)
 	log.Printf("Domain tracker started...")
 
 	// run the tracker
 	for {
-		if err := tracker.WaitForSMH(*interval); err != nil {
-			log.Printf("Error fetching SMH: %v", err)
+		if err := tracker.WaitForSMH(ctx, *interval); err != nil {
+			logSMHError(err)
 			time.Sleep(*interval)
 			continue
 		}
@@ -72,18 +156,49 @@ func main() {
 			log.Printf("New SMH: timestamp=%d, size=%d, rootHash=%x..., sourceRootHash=%x..., sourceLogCount=%d",
 				tracker.smh.Timestamp, tracker.smh.MapSize, tracker.smh.MapRootHash[:4], tracker.smh.SourceTreeRootHash[:4], len(tracker.smh.SourceLogRevisions))
 		}
-		updates := tracker.UpdateDomainTreeRoots(true)
+		tracker.CheckInclusionPromises(ctx, *mmd)
+		updates := tracker.UpdateDomainTreeRoots(ctx, true)
 		for _, update := range updates {
-			h := sha256.New()
-			h.Write(update.Cert.Raw)
 			if *verbose {
 				log.Printf("New certificate for %s:\n  Issuer: %s\n  Subject: %s\n  SHA-256 Fingerprint: %x\n  Leaf Index: %d",
-					strings.Join(update.Domains, ", "), update.Cert.Issuer, update.Cert.Subject, h.Sum(nil), update.LeafIndex)
+					strings.Join(update.Domains, ", "), update.Cert.Issuer, update.Cert.Subject, update.Fingerprint, update.LeafIndex)
 			} else {
 				log.Printf("New certificate for %s:\n  SHA-256 Fingerprint: %x\n  Leaf Index: %d",
-					strings.Join(update.Domains, ", "), h.Sum(nil), update.LeafIndex)
+					strings.Join(update.Domains, ", "), update.Fingerprint, update.LeafIndex)
 			}
 		}
 	}
 
 }
+
+func parsePromiseSpecifier(spec string) (*ds.GetInclusionPromiseRequest, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("expected LOG_INDEX:CERTIFICATE_INDEX:DOMAIN")
+	}
+	logIndex, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log index: %w", err)
+	}
+	certIndex, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate index: %w", err)
+	}
+	return &ds.GetInclusionPromiseRequest{
+		DomainName:       parts[2],
+		LogIndex:         logIndex,
+		CertificateIndex: certIndex,
+	}, nil
+}
+
+// logSMHError logs an error returned while fetching an SMH. If the map forked,
+// the evidence is logged as JSON, so that it can be reported.
+func logSMHError(err error) {
+	var evidence *mapclient.ForkEvidence
+	if errors.As(err, &evidence) {
+		data, _ := json.Marshal(evidence)
+		log.Printf("Error: %v\nEvidence: %s", err, data)
+		return
+	}
+	log.Printf("Error fetching SMH: %v", err)
+}
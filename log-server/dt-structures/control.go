@@ -0,0 +1,174 @@
+package dt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	ct "github.com/google/certificate-transparency-go"
+)
+
+// A WorkerControl sends commands to a running worker, which are run between two
+// transactions. It is passed to the worker in WorkerConfig.Control.
+type WorkerControl struct {
+	requests chan workerRequest
+}
+
+type workerRequest struct {
+	fn     func(w *worker) error
+	result chan error
+}
+
+// NewWorkerControl creates a WorkerControl.
+func NewWorkerControl() *WorkerControl {
+	return &WorkerControl{requests: make(chan workerRequest)}
+}
+
+// do runs fn in the worker's goroutine and returns its result.
+func (ctl *WorkerControl) do(ctx context.Context, fn func(w *worker) error) error {
+	req := workerRequest{fn, make(chan error, 1)}
+	select {
+	case ctl.requests <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ErrNothingToPublish is returned by PublishSMH if the worker hasn't received any transaction yet.
+var ErrNothingToPublish = errors.New("no certificates were added to the map yet")
+
+// PublishSMH makes the worker publish an SMH immediately, without waiting for
+// the update period. If the map didn't change, the latest SMH is republished.
+func (ctl *WorkerControl) PublishSMH(ctx context.Context) (*SignedMapHead, error) {
+	var smh *SignedMapHead
+	err := ctl.do(ctx, func(w *worker) error {
+		if w.mapSize == 0 {
+			return ErrNothingToPublish
+		}
+		if err := w.publishSMH(" (forced)"); err != nil {
+			return err
+		}
+		smh = w.dm.GetLatestSMH()
+		return nil
+	})
+	return smh, err
+}
+
+// A WorkerState describes the state of a worker, including changes not yet published in an SMH.
+type WorkerState struct {
+	MapSize            uint64              `json:"map_size"`
+	MapRootHash        []byte              `json:"map_root_hash"`
+	SourceLogRevisions []LogRevision       `json:"source_log_revisions"`
+	ChannelLength      int                 `json:"channel_length"`
+	ChannelCapacity    int                 `json:"channel_capacity"`
+	Queue              []QueuedTransaction `json:"queue"`
+}
+
+// A QueuedTransaction is a transaction waiting for earlier source logs to be added to the map.
+type QueuedTransaction struct {
+	LogIndex     uint64 `json:"log_index"`
+	TreeSize     uint64 `json:"tree_size"`
+	Domains      int    `json:"domains"`
+	Certificates int    `json:"certificates"`
+}
+
+// State returns the current state of the worker.
+func (ctl *WorkerControl) State(ctx context.Context) (*WorkerState, error) {
+	var state *WorkerState
+	err := ctl.do(ctx, func(w *worker) error {
+		state = &WorkerState{
+			MapSize:            w.mapSize,
+			MapRootHash:        append([]byte(nil), w.mapRoot...),
+			SourceLogRevisions: append([]LogRevision(nil), w.sourceRevisions...),
+			ChannelLength:      len(w.c),
+			ChannelCapacity:    cap(w.c),
+			Queue:              make([]QueuedTransaction, len(w.queue)),
+		}
+		for i, t := range w.queue {
+			state.Queue[i] = QueuedTransaction{
+				LogIndex:     t.LogIndex,
+				TreeSize:     t.LogRevision.TreeSize,
+				Domains:      len(t.NewCertificatesIndices),
+				Certificates: len(t.CertificateHashes),
+			}
+		}
+		return nil
+	})
+	return state, err
+}
+
+// A MapSnapshot is a copy of the contents of the map: its source logs and the entries
+// of every domain tree, from which the map root hash can be recomputed.
+type MapSnapshot struct {
+	MapSize            uint64               `json:"map_size"`
+	MapRootHash        []byte               `json:"map_root_hash"`
+	SourceLogRevisions []LogRevision        `json:"source_log_revisions"`
+	SourceLogs         []ct.SHA256Hash      `json:"source_logs"` // log IDs, in source tree order
+	DomainTrees        []DomainTreeSnapshot `json:"domain_trees"`
+	LatestSMH          *SignedMapHead       `json:"latest_smh"`
+}
+
+// A DomainTreeSnapshot holds the entries of a domain tree.
+type DomainTreeSnapshot struct {
+	DomainName string            `json:"domain_name"`
+	Entries    []DomainTreeEntry `json:"entries"`
+}
+
+// Snapshot returns a consistent copy of the contents of the map, including the
+// changes not yet published in an SMH. The worker is paused while the map is copied.
+func (ctl *WorkerControl) Snapshot(ctx context.Context) (*MapSnapshot, error) {
+	var snapshot *MapSnapshot
+	err := ctl.do(ctx, func(w *worker) error {
+		var err error
+		snapshot, err = w.snapshot()
+		return err
+	})
+	return snapshot, err
+}
+
+func (w *worker) snapshot() (*MapSnapshot, error) {
+	snapshot := &MapSnapshot{
+		MapSize:            w.mapSize,
+		MapRootHash:        append([]byte(nil), w.mapRoot...),
+		SourceLogRevisions: append([]LogRevision(nil), w.sourceRevisions...),
+		SourceLogs:         []ct.SHA256Hash{},
+		DomainTrees:        []DomainTreeSnapshot{},
+		LatestSMH:          w.dm.GetLatestSMH(),
+	}
+	if len(w.sourceRevisions) != 0 {
+		logIDs, err := w.dm.GetSourceTree().GetEntries(0, uint64(len(w.sourceRevisions))-1)
+		if err != nil {
+			return nil, fmt.Errorf("error copying the source tree: %w", err)
+		}
+		for _, id := range logIDs {
+			snapshot.SourceLogs = append(snapshot.SourceLogs, ct.SHA256Hash(id))
+		}
+	}
+	for _, dtree := range w.dm.getDomainTrees() {
+		entries, err := dtree.GetEntries(0, ^uint64(0))
+		if err != nil {
+			return nil, fmt.Errorf("error copying the domain tree for %q: %w", dtree.DomainName, err)
+		}
+		snapshot.DomainTrees = append(snapshot.DomainTrees, DomainTreeSnapshot{dtree.DomainName, entries})
+	}
+	return snapshot, nil
+}
+
+// getDomainTrees returns all domain trees, sorted by domain name.
+func (dm *DomainMap) getDomainTrees() []*DomainTree {
+	dm.m.RLock()
+	trees := make([]*DomainTree, 0, len(dm.subtrees))
+	for _, dtree := range dm.subtrees {
+		trees = append(trees, dtree)
+	}
+	dm.m.RUnlock()
+	sort.Slice(trees, func(i, j int) bool { return trees[i].DomainName < trees[j].DomainName })
+	return trees
+}
@@ -0,0 +1,125 @@
+package dt
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// CosignedMapHead is the structure which is signed by a witness to produce a MapHeadCosignature.
+type CosignedMapHead struct {
+	Timestamp uint64
+	MapHead   MapHead
+}
+
+// A MapHeadCosignature is a witness's signature over a MapHead.
+// By cosigning a head, a witness attests that the head is consistent
+// with every other head it has seen.
+type MapHeadCosignature struct {
+	WitnessKeyHash ct.SHA256Hash `json:"witness_key_hash"`
+	Timestamp      uint64        `json:"timestamp"`
+	Signature      []byte        `json:"signature"`
+}
+
+// WitnessKeyHash returns the hash used to identify a witness's public key,
+// i.e., the SHA-256 hash of the DER-encoded public key.
+func WitnessKeyHash(publicKey *ecdsa.PublicKey) (ct.SHA256Hash, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return ct.SHA256Hash{}, fmt.Errorf("error marshalling witness public key: %w", err)
+	}
+	return util.HashBytesFixed(der), nil
+}
+
+// VerifyCosignature verifies a cosignature over the specified head.
+func VerifyCosignature(publicKey *ecdsa.PublicKey, head MapHead, cosig MapHeadCosignature) error {
+	data, err := tls.Marshal(CosignedMapHead{cosig.Timestamp, head})
+	if err != nil {
+		return fmt.Errorf("error marshaling CosignedMapHead: %w", err)
+	}
+	if !ecdsa.VerifyASN1(publicKey, util.HashBytes(data), cosig.Signature) {
+		return fmt.Errorf("invalid cosignature for witness %x", cosig.WitnessKeyHash)
+	}
+	return nil
+}
+
+// AddWitness registers a witness whose cosignatures will be accepted by this map.
+func (dm *DomainMap) AddWitness(publicKey *ecdsa.PublicKey) (ct.SHA256Hash, error) {
+	keyHash, err := WitnessKeyHash(publicKey)
+	if err != nil {
+		return ct.SHA256Hash{}, err
+	}
+
+	dm.m.Lock()
+	defer dm.m.Unlock()
+	dm.witnesses[keyHash] = publicKey
+	return keyHash, nil
+}
+
+// AddCosignature verifies and stores a cosignature for the current SMH with the specified map size.
+// A newer cosignature from the same witness replaces the previous one.
+func (dm *DomainMap) AddCosignature(mapSize uint64, cosig MapHeadCosignature) error {
+	dm.m.RLock()
+	publicKey, ok := dm.witnesses[cosig.WitnessKeyHash]
+	smh := dm.smhs.get(mapSize)
+	dm.m.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("unknown witness %x", cosig.WitnessKeyHash)
+	}
+	if smh == nil {
+		return fmt.Errorf("no SMH with map size %d", mapSize)
+	}
+	if err := VerifyCosignature(publicKey, smh.MapHead, cosig); err != nil {
+		return err
+	}
+
+	dm.m.Lock()
+	defer dm.m.Unlock()
+	if dm.smhs.get(mapSize) != smh {
+		return fmt.Errorf("SMH with map size %d was republished while adding the cosignature", mapSize)
+	}
+	cosigs := dm.cosignatures[mapSize]
+	for i, c := range cosigs {
+		if c.WitnessKeyHash == cosig.WitnessKeyHash {
+			if c.Timestamp < cosig.Timestamp {
+				cosigs[i] = cosig
+			}
+			return nil
+		}
+	}
+	dm.cosignatures[mapSize] = append(cosigs, cosig)
+	return nil
+}
+
+// GetLatestSMHAndCosignatures returns the latest SMH along with all of its cosignatures.
+func (dm *DomainMap) GetLatestSMHAndCosignatures() (*SignedMapHead, []MapHeadCosignature) {
+	dm.m.RLock()
+	defer dm.m.RUnlock()
+	return dm.smh, dm.getCosignatures(dm.smh.MapSize)
+}
+
+// GetSMHAndCosignatures returns the SMH with the specified map size along with all of its cosignatures,
+// or nil if the specified SMH does not exist.
+func (dm *DomainMap) GetSMHAndCosignatures(mapSize uint64) (*SignedMapHead, []MapHeadCosignature) {
+	dm.m.RLock()
+	defer dm.m.RUnlock()
+	smh := dm.smhs.get(mapSize)
+	if smh == nil {
+		return nil, nil
+	}
+	return smh, dm.getCosignatures(mapSize)
+}
+
+// getCosignatures returns a copy of the cosignatures for the specified map size.
+// dm.m must be locked by the caller.
+func (dm *DomainMap) getCosignatures(mapSize uint64) []MapHeadCosignature {
+	cosigs := dm.cosignatures[mapSize]
+	cp := make([]MapHeadCosignature, len(cosigs))
+	copy(cp, cosigs)
+	return cp
+}
@@ -3,9 +3,11 @@ package dt
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
 	"crypto/sha256"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
@@ -16,8 +18,26 @@ import (
 	"github.com/lazyledger/smt"
 )
 
-// Version is the Domain Transparency version
-const Version = 1
+// Version is the Domain Transparency version.
+// It determines the format of the domain tree entries (see DomainTreeEntry.LeafData).
+const Version = VersionV2
+
+// Domain Transparency versions.
+const (
+	VersionV1 = 1 // domain tree entries only point to a certificate in a CT log
+	VersionV2 = 2 // domain tree entries also commit to the CT leaf hash and certificate fingerprint
+)
+
+// SignatureType identifies the structure signed by the map's key. Like CT's SignatureType,
+// it prefixes the signed data (see SignedData), so that a signature over one structure
+// can never be verified as a signature over another.
+type SignatureType uint8
+
+// Signature types.
+const (
+	MapHeadSignatureType          SignatureType = 0
+	InclusionPromiseSignatureType SignatureType = 1
+)
 
 var emptySMH = SignedMapHead{
 	MapHead{
@@ -32,9 +52,13 @@ var emptySMH = SignedMapHead{
 }
 
 // A DomainProof proves the (non-)containment of a node.
+// Proof lists the side nodes from the leaf up to the root.
+// NonMembershipLeafData is set only if the path of the domain ends in a leaf
+// belonging to another domain.
 type DomainProof struct {
-	Proof    [][]byte
-	LeafHash []byte
+	Proof                 [][]byte
+	LeafHash              []byte
+	NonMembershipLeafData []byte
 }
 
 // LogRevision is used in MapHead to identify a source log revision
@@ -45,7 +69,7 @@ type LogRevision struct {
 
 // MapHead is the structure which is signed to produce the SignedMapHead.
 type MapHead struct {
-	Version   ct.Version `json:"-" tls:"maxval:255"`
+	Version   ct.Version `json:"version,omitempty" tls:"maxval:255"`
 	Timestamp uint64     `json:"timestamp"`
 	MapSize   uint64     `json:"map_size"`
 
@@ -64,15 +88,34 @@ type SignedMapHead struct {
 // A DomainMap maps domains to CT certificates.
 type DomainMap struct {
 	// locked by m
-	smhs       map[uint64]*SignedMapHead
-	smh        *SignedMapHead
-	sparseTree *smt.SparseMerkleTree
-	subtrees   map[string]*DomainTree
+	smhs          smhArchive
+	smh           *SignedMapHead
+	sparseTree    *smt.SparseMerkleTree
+	subtrees      map[string]*DomainTree
+	domainsByPath map[ct.SHA256Hash]string             // sparse merkle tree path -> normalized domain
+	placements    map[[2]uint64][]CertificatePlacement // (log index, certificate index) -> placements, never removed
+	witnesses     map[ct.SHA256Hash]*ecdsa.PublicKey
+	cosignatures  map[uint64][]MapHeadCosignature
 
 	// const, internally thread-safe
 	sparseStore mapstore.Interface
 	sourceTree  *SourceTree
-	signer      crypto.Signer
+	signer      crypto.Signer // nil for mirrors (see NewMirrorDomainMap)
+	publicKey   crypto.PublicKey
+
+	// cached by GetLatestCheckpoint, locked by mCheckpoint
+	checkpoint        []byte
+	checkpointMapSize uint64
+	checkpointOrigin  string
+	mCheckpoint       sync.Mutex
+
+	// results of GetChangedDomains by (first, second) map roots, locked by mChangedDomains
+	changedDomains  map[[2]ct.SHA256Hash][]ChangedDomain
+	mChangedDomains sync.Mutex
+
+	// channels returned by SubscribeSMHs, locked by mSubscribers
+	subscribers  map[chan *SignedMapHead]struct{}
+	mSubscribers sync.Mutex
 
 	// mPublishSMH ensures only one call to CheckAndPublishSMH is running at any time.
 	// It should only be locked by CheckAndPublishSMH.
@@ -83,21 +126,66 @@ type DomainMap struct {
 // NewDomainMap creates a new DomainMap.
 // The domain map starts with unsigned an empty SMH.
 func NewDomainMap(signer crypto.Signer) *DomainMap {
+	dm := newDomainMap(signer.Public())
+	dm.signer = signer
+	return dm
+}
+
+func newDomainMap(publicKey crypto.PublicKey) *DomainMap {
 	ms := mapstore.NewMem(sha256.Size)
 	return &DomainMap{
-		smhs:        make(map[uint64]*SignedMapHead),
-		smh:         &emptySMH,
-		sparseStore: ms,
-		sparseTree:  smt.NewSparseMerkleTree(ms, sha256.New()),
-		sourceTree:  NewSourceTree(),
-		subtrees:    make(map[string]*DomainTree),
-		signer:      signer,
+		smhs:          newSMHArchive(),
+		smh:           &emptySMH,
+		sparseStore:   ms,
+		sparseTree:    smt.NewSparseMerkleTree(ms, sha256.New()),
+		sourceTree:    NewSourceTree(),
+		subtrees:      make(map[string]*DomainTree),
+		domainsByPath: make(map[ct.SHA256Hash]string),
+		placements:    make(map[[2]uint64][]CertificatePlacement),
+		witnesses:     make(map[ct.SHA256Hash]*ecdsa.PublicKey),
+		cosignatures:  make(map[uint64][]MapHeadCosignature),
+		subscribers:   make(map[chan *SignedMapHead]struct{}),
+		publicKey:     publicKey,
+
+		changedDomains: make(map[[2]ct.SHA256Hash][]ChangedDomain),
 	}
 }
 
 // PublicKey returns this map's public key.
 func (dm *DomainMap) PublicKey() crypto.PublicKey {
-	return dm.signer.Public()
+	return dm.publicKey
+}
+
+// SignedData returns the data signed by the map's key for a structure of the specified
+// type: the signature type, followed by the TLS encoding of the structure.
+func SignedData(sigType SignatureType, v interface{}) ([]byte, error) {
+	tlsEncoded, err := tls.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %T: %w", v, err)
+	}
+	return append([]byte{byte(sigType)}, tlsEncoded...), nil
+}
+
+// sign signs the specified value, prefixed by its signature type, with this map's private key.
+func (dm *DomainMap) sign(sigType SignatureType, v interface{}) ([]byte, error) {
+	data, err := SignedData(sigType, v)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := dm.signBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("error signing %T: %w", v, err)
+	}
+	return sig, nil
+}
+
+// signBytes signs the SHA-256 hash of the specified data with this map's private key.
+func (dm *DomainMap) signBytes(data []byte) ([]byte, error) {
+	if dm.signer == nil {
+		return nil, ErrReadOnly
+	}
+	return dm.signer.Sign(rand.New(rand.NewSource(42)), util.HashBytes(data), nil)
 }
 
 func (dm *DomainMap) getDomain(root []byte, domain string, failIfEmpty bool) ([]byte, error) {
@@ -158,14 +246,20 @@ func (dm *DomainMap) CheckAndPublishSMH(root []byte, mapSize uint64, sourceRevis
 		}
 	}
 
+	// Timestamps never decrease, so that SMHs can be looked up by time
+	timestamp := uint64(time.Now().UTC().Unix())
+	if timestamp < currentSMH.Timestamp {
+		timestamp = currentSMH.Timestamp
+	}
+
 	var head MapHead
 	if isRepublish {
 		head = currentSMH.MapHead
-		head.Timestamp = uint64(time.Now().UTC().Unix())
+		head.Timestamp = timestamp
 	} else {
 		head = MapHead{
 			Version:            Version,
-			Timestamp:          uint64(time.Now().UTC().Unix()),
+			Timestamp:          timestamp,
 			MapSize:            mapSize,
 			SourceLogRevisions: make([]LogRevision, len(sourceRevisions)),
 		}
@@ -182,30 +276,34 @@ func (dm *DomainMap) CheckAndPublishSMH(root []byte, mapSize uint64, sourceRevis
 		copy(head.SourceTreeRootHash[:], sourceRoot)
 	}
 
-	tlsEncodedHead, err := tls.Marshal(head)
-	if err != nil {
-		return fmt.Errorf("error marshaling MapHead: %w", err)
-	}
-
-	sig, err := dm.signer.Sign(rand.New(rand.NewSource(42)), util.HashBytes(tlsEncodedHead), nil)
+	sig, err := dm.sign(MapHeadSignatureType, head)
 	if err != nil {
-		return fmt.Errorf("error signing MapHead: %w", err)
+		return err
 	}
-	smh := &SignedMapHead{head, sig}
+	return dm.publish(&SignedMapHead{head, sig}, isRepublish)
+}
 
+// publish makes smh the latest SMH. dm.mPublishSMH must be locked by the caller.
+func (dm *DomainMap) publish(smh *SignedMapHead, isRepublish bool) error {
 	// Delete "orphan" nodes at the last possible moment, to ensure
 	// that the MapStore is only modified if the SMH update is successful.
 	if !isRepublish {
-		if err := dm.sparseStore.SaveNodesForRoot(root); err != nil {
+		if err := dm.sparseStore.SaveNodesForRoot(smh.MapRootHash[:]); err != nil {
 			return err
 		}
 	}
 
 	dm.m.Lock()
-	defer dm.m.Unlock()
-
 	dm.smh = smh
-	dm.smhs[smh.MapSize] = smh
+	dm.smhs.add(smh)
+	// Cosignatures refer to the previous head for this map size (if any).
+	delete(dm.cosignatures, smh.MapSize)
+	dm.m.Unlock()
+
+	smhsPublished.WithLabelValues(strconv.FormatBool(isRepublish)).Inc()
+	smhMapSize.Set(float64(smh.MapSize))
+	smhTimestamp.Set(float64(smh.Timestamp))
+	dm.notifySubscribers(smh)
 	return nil
 }
 
@@ -276,9 +374,33 @@ func (dm *DomainMap) AddDomainTree(tree *DomainTree) error {
 		return fmt.Errorf("domain tree already exists for %q", tree.DomainName)
 	}
 	dm.subtrees[normalizedDomain] = tree
+	dm.domainsByPath[util.HashBytesFixed([]byte(normalizedDomain))] = normalizedDomain
+	domainTrees.Set(float64(len(dm.subtrees)))
 	return nil
 }
 
+// getOrAddDomainTree returns the domain tree for the specified domain,
+// adding a new empty domain tree if there is none.
+func (dm *DomainMap) getOrAddDomainTree(domain string) (*DomainTree, error) {
+	normalizedDomain, err := util.NormalizeDomainName(domain)
+	if err != nil {
+		return nil, fmt.Errorf("error normalizing domain name %q: %w", domain, err)
+	}
+	dtree, err := dm.GetDomainTree(normalizedDomain)
+	if err == nil {
+		return dtree, nil
+	}
+
+	dtree, err = NewDomainTree(normalizedDomain)
+	if err != nil {
+		return nil, fmt.Errorf("error creating domain tree for %q: %w", normalizedDomain, err)
+	}
+	if err := dm.AddDomainTree(dtree); err != nil {
+		return nil, fmt.Errorf("error adding new domain tree do the domain map: %w", err)
+	}
+	return dtree, nil
+}
+
 // GetLatestSMH returns the latest SMH, or nil if the tree is empty.
 func (dm *DomainMap) GetLatestSMH() *SignedMapHead {
 	dm.m.RLock()
@@ -286,11 +408,35 @@ func (dm *DomainMap) GetLatestSMH() *SignedMapHead {
 	return dm.smh
 }
 
+// HasPublishedSMH reports whether the map has published a signed SMH.
+// Until then, GetLatestSMH returns an unsigned empty map head.
+func (dm *DomainMap) HasPublishedSMH() bool {
+	dm.m.RLock()
+	defer dm.m.RUnlock()
+	return dm.smh != &emptySMH
+}
+
 // GetSMH returns the specified SMH, or nil if the specified SMH does not exist.
 func (dm *DomainMap) GetSMH(treeSize uint64) *SignedMapHead {
 	dm.m.RLock()
 	defer dm.m.RUnlock()
-	return dm.smhs[treeSize]
+	return dm.smhs.get(treeSize)
+}
+
+// GetSMHRange returns the latest SMH for each published map size in the interval [start, end],
+// in increasing order of map size, up to a maximum of `max` SMHs.
+func (dm *DomainMap) GetSMHRange(start, end uint64, max int) []*SignedMapHead {
+	dm.m.RLock()
+	defer dm.m.RUnlock()
+	return dm.smhs.getRange(start, end, max)
+}
+
+// GetSMHAt returns the SMH that was current at the specified timestamp,
+// or nil if no SMH had been published by then.
+func (dm *DomainMap) GetSMHAt(timestamp uint64) *SignedMapHead {
+	dm.m.RLock()
+	defer dm.m.RUnlock()
+	return dm.smhs.getAt(timestamp)
 }
 
 // GetDomainTreeRoot returns the STH for the specified domain tree at the specified map head,
@@ -358,9 +504,16 @@ func (dm *DomainMap) GetProofForDomain(root []byte, domain string) (DomainProof,
 		rev[i] = proof.SideNodes[len(rev)-i-1]
 	}
 
+	// The proof includes the leaf data even if the leaf belongs to the domain
+	var nonMembershipLeafData []byte
+	if data := proof.NonMembershipLeafData; data != nil && !bytes.Equal(data[1:1+sha256.Size], util.HashBytes([]byte(normalizedDomain))) {
+		nonMembershipLeafData = data
+	}
+
 	return DomainProof{
-		Proof:    rev,
-		LeafHash: leafHash,
+		Proof:                 rev,
+		LeafHash:              leafHash,
+		NonMembershipLeafData: nonMembershipLeafData,
 	}, nil
 }
 
@@ -18,11 +18,36 @@ type DomainTreeRoot struct {
 
 // A DomainTreeEntry points to a certificate in a CT log.
 // The LogIndex refers to the index of the CT log in the domain map's source tree.
+//
+// LeafHash is the Merkle leaf hash of the entry in the CT log, and
+// CertificateFingerprint is the SHA-256 hash of the DER-encoded certificate
+// (or precertificate, for precertificate entries).
+// Both are only committed to by version 2 (and later) domain trees.
 type DomainTreeEntry struct {
+	LogIndex               uint64        `json:"log_index"`
+	CertificateIndex       uint64        `json:"certificate_index"`
+	LeafHash               ct.SHA256Hash `json:"leaf_hash"`
+	CertificateFingerprint ct.SHA256Hash `json:"certificate_fingerprint"`
+}
+
+// domainTreeEntryV1 is the leaf format of version 1 domain trees.
+type domainTreeEntryV1 struct {
 	LogIndex         uint64
 	CertificateIndex uint64
 }
 
+// LeafData returns the domain tree leaf for this entry, in the format of the specified version.
+func (e DomainTreeEntry) LeafData(version ct.Version) ([]byte, error) {
+	switch version {
+	case VersionV1:
+		return tls.Marshal(domainTreeEntryV1{e.LogIndex, e.CertificateIndex})
+	case VersionV2:
+		return tls.Marshal(e)
+	default:
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+}
+
 type certAndDTIndex struct {
 	CertificateIndex uint64
 	DomainTreeIndex  uint64
@@ -69,6 +94,8 @@ func (dtree *DomainTree) GetRoot(treeSize uint64) (*DomainTreeRoot, error) {
 }
 
 // GetEntries returns the entries in the specified interval, inclusive.
+// As in CT's get-entries, the interval is truncated to the current tree size,
+// so fewer entries are returned if end is not in the tree; start must be in the tree.
 func (dtree *DomainTree) GetEntries(start, end uint64) ([]DomainTreeEntry, error) {
 	if start > end {
 		return nil, fmt.Errorf("invalid interval: start (%d) > end (%d)", start, end)
@@ -77,8 +104,11 @@ func (dtree *DomainTree) GetEntries(start, end uint64) ([]DomainTreeEntry, error
 	dtree.m.RLock()
 	defer dtree.m.RUnlock()
 
+	if start >= uint64(len(dtree.leaves)) {
+		return nil, fmt.Errorf("invalid interval: start (%d) >= tree size (%d)", start, len(dtree.leaves))
+	}
 	if end >= uint64(len(dtree.leaves)) {
-		return nil, fmt.Errorf("invalid interval: end (%d) >= tree size (%d)", end, len(dtree.leaves))
+		end = uint64(len(dtree.leaves)) - 1
 	}
 	entries := dtree.leaves[start : end+1]
 	cp := make([]DomainTreeEntry, len(entries))
@@ -108,7 +138,7 @@ func (dtree *DomainTree) growLeavesPerLog(minSize uint64) {
 
 // AddEntry adds an entry to the tree and returns its current leaf count.
 func (dtree *DomainTree) AddEntry(entry DomainTreeEntry) uint64 {
-	leafData, err := tls.Marshal(entry)
+	leafData, err := entry.LeafData(Version)
 	if err != nil {
 		panic(fmt.Errorf("unexpected error marshaling entry: %w", err))
 	}
@@ -133,10 +163,12 @@ func (dtree *DomainTree) EntryToDomainTreeIndex(entry DomainTreeEntry) (uint64,
 	dtree.m.RLock()
 	defer dtree.m.RUnlock()
 
-	dtree.growLeavesPerLog(entry.LogIndex + 1)
+	if entry.LogIndex >= uint64(len(dtree.leavesPerLog)) {
+		return 0, fmt.Errorf("no entry with log index %d found", entry.LogIndex)
+	}
 	leaves := dtree.leavesPerLog[entry.LogIndex]
 	i := sort.Search(len(leaves), func(i int) bool { return leaves[i].CertificateIndex >= entry.CertificateIndex })
-	if leaves[i].CertificateIndex != entry.CertificateIndex {
+	if i == len(leaves) || leaves[i].CertificateIndex != entry.CertificateIndex {
 		return 0, fmt.Errorf("no entry with log index %d found", entry.LogIndex)
 	}
 
@@ -0,0 +1,85 @@
+package dt_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+)
+
+func TestLeafData(t *testing.T) {
+	entry := dt.DomainTreeEntry{
+		LogIndex:               3,
+		CertificateIndex:       1 << 40,
+		LeafHash:               ct.SHA256Hash{1, 2, 3},
+		CertificateFingerprint: ct.SHA256Hash{4, 5, 6},
+	}
+	indices := make([]byte, 16)
+	binary.BigEndian.PutUint64(indices, entry.LogIndex)
+	binary.BigEndian.PutUint64(indices[8:], entry.CertificateIndex)
+
+	tests := []struct {
+		version ct.Version
+		want    []byte
+	}{
+		{dt.VersionV1, indices},
+		{dt.VersionV2, append(append(append([]byte(nil), indices...), entry.LeafHash[:]...), entry.CertificateFingerprint[:]...)},
+	}
+	for _, tt := range tests {
+		data, err := entry.LeafData(tt.version)
+		if err != nil {
+			t.Fatalf("LeafData(%d): %v", tt.version, err)
+		}
+		if !bytes.Equal(data, tt.want) {
+			t.Errorf("LeafData(%d): got %x, expected %x", tt.version, data, tt.want)
+		}
+	}
+
+	// Version 1 leaves only decode to the indices of the entry
+	v1, err := entry.LeafData(dt.VersionV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded dt.DomainTreeEntry
+	if _, err := tls.Unmarshal(v1, &decoded); err == nil {
+		t.Errorf("a version 1 leaf decoded as a version 2 leaf")
+	}
+	v2, err := entry.LeafData(dt.VersionV2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest, err := tls.Unmarshal(v2, &decoded); err != nil || len(rest) != 0 || decoded != entry {
+		t.Errorf("version 2 leaf: decoded %+v (%v), expected %+v", decoded, err, entry)
+	}
+
+	for _, version := range []ct.Version{0, dt.VersionV2 + 1} {
+		if _, err := entry.LeafData(version); err == nil {
+			t.Errorf("LeafData(%d): expected an error", version)
+		}
+	}
+
+	// Domain trees hash their entries with the leaf format of the current version
+	tree, err := dt.NewDomainTree("a.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree.AddEntry(entry)
+	root, err := tree.GetRoot(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []ct.Version{dt.VersionV1, dt.VersionV2} {
+		data, err := entry.LeafData(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		match := bytes.Equal(rfc6962.DefaultHasher.HashLeaf(data), root.DomainTreeRootHash[:])
+		if match != (version == dt.Version) {
+			t.Errorf("version %d: leaf hash matches the domain tree root: %v, expected %v", version, match, version == dt.Version)
+		}
+	}
+}
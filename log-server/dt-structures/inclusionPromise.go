@@ -0,0 +1,56 @@
+package dt
+
+import (
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+)
+
+// MapInclusionPromise is the structure which is signed to produce the SignedMapInclusionPromise.
+type MapInclusionPromise struct {
+	Version          ct.Version `tls:"maxval:255"`
+	Timestamp        uint64
+	LogIndex         uint64
+	CertificateIndex uint64
+	DomainName       []byte `tls:"minlen:1,maxlen:255"`
+}
+
+// A SignedMapInclusionPromise is the map's commitment that a certificate
+// will be present in the domain tree of the specified (normalized) domain in
+// the first SMH published after the promise, i.e. whose timestamp is greater
+// than the promise's timestamp. That SMH must be published within the MMD.
+type SignedMapInclusionPromise struct {
+	MapInclusionPromise
+	Signature []byte
+}
+
+// IssueInclusionPromise issues a signed inclusion promise for the specified entry
+// and domain (after domain name normalization).
+//
+// A promise can only be issued once the worker has added the entry to the domain tree,
+// so it is an error to request a promise for an entry the map hasn't observed yet.
+func (dm *DomainMap) IssueInclusionPromise(entry DomainTreeEntry, domain string) (*SignedMapInclusionPromise, error) {
+	if dm.signer == nil {
+		return nil, ErrReadOnly
+	}
+	tree, err := dm.GetDomainTree(domain)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tree.EntryToDomainTreeIndex(entry); err != nil {
+		return nil, err
+	}
+
+	promise := MapInclusionPromise{
+		Version:          Version,
+		Timestamp:        uint64(time.Now().UTC().Unix()),
+		LogIndex:         entry.LogIndex,
+		CertificateIndex: entry.CertificateIndex,
+		DomainName:       []byte(tree.DomainName),
+	}
+	sig, err := dm.sign(InclusionPromiseSignatureType, promise)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedMapInclusionPromise{promise, sig}, nil
+}
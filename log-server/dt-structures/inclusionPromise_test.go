@@ -0,0 +1,76 @@
+package dt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// verifyMapSignature verifies a signature of the map's key over a structure of the specified type.
+func verifyMapSignature(t *testing.T, key *ecdsa.PublicKey, sigType dt.SignatureType, v interface{}, sig []byte) bool {
+	data, err := dt.SignedData(sigType, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ecdsa.VerifyASN1(key, util.HashBytes(data), sig)
+}
+
+func TestIssueInclusionPromise(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+	addTestCertificates(t, dm, c, 0, 0, 4, []string{"a.com", "b.com"})
+
+	before := uint64(time.Now().Unix())
+	promise, err := dm.IssueInclusionPromise(dt.DomainTreeEntry{LogIndex: 0, CertificateIndex: 2}, "a.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promise.Version != dt.Version || promise.LogIndex != 0 || promise.CertificateIndex != 2 ||
+		string(promise.DomainName) != "a.com" || promise.Timestamp < before || promise.Timestamp > uint64(time.Now().Unix()) {
+		t.Errorf("got promise %+v", promise.MapInclusionPromise)
+	}
+	if !verifyMapSignature(t, &key.PublicKey, dt.InclusionPromiseSignatureType, promise.MapInclusionPromise, promise.Signature) {
+		t.Errorf("the promise signature doesn't verify")
+	}
+
+	// Signatures can't be verified as signatures over other types of structures
+	if verifyMapSignature(t, &key.PublicKey, dt.MapHeadSignatureType, promise.MapInclusionPromise, promise.Signature) {
+		t.Errorf("the promise signature verifies as an SMH signature")
+	}
+	smh := dm.GetLatestSMH()
+	if verifyMapSignature(t, &key.PublicKey, dt.InclusionPromiseSignatureType, smh.MapHead, smh.MapHeadSignature) {
+		t.Errorf("the SMH signature verifies as a promise signature")
+	}
+	if err := dt.VerifySMH(&key.PublicKey, smh); err != nil {
+		t.Errorf("VerifySMH: %v", err)
+	}
+
+	// The entry must be in the domain tree of the domain
+	if _, err := dm.IssueInclusionPromise(dt.DomainTreeEntry{LogIndex: 0, CertificateIndex: 1}, "a.com"); err == nil {
+		t.Errorf("issued a promise for an entry of another domain")
+	}
+	if _, err := dm.IssueInclusionPromise(dt.DomainTreeEntry{LogIndex: 0, CertificateIndex: 4}, "a.com"); err == nil {
+		t.Errorf("issued a promise for an entry the map hasn't observed")
+	}
+	if _, err := dm.IssueInclusionPromise(dt.DomainTreeEntry{LogIndex: 0, CertificateIndex: 0}, "missing.com"); err == nil {
+		t.Errorf("issued a promise for a missing domain")
+	}
+
+	mirror := dt.NewMirrorDomainMap(&key.PublicKey)
+	if _, err := mirror.IssueInclusionPromise(dt.DomainTreeEntry{}, "a.com"); !errors.Is(err, dt.ErrReadOnly) {
+		t.Errorf("mirror: got %v, expected %v", err, dt.ErrReadOnly)
+	}
+}
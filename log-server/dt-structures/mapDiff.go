@@ -0,0 +1,190 @@
+package dt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+)
+
+// A ChangedDomain is a domain whose domain tree differs between two map roots.
+type ChangedDomain struct {
+	DomainName  string // normalized
+	OldTreeSize uint64
+	NewTreeSize uint64
+}
+
+// smtLeaf is a leaf of the sparse merkle tree, as stored in the map store.
+type smtLeaf struct {
+	path      ct.SHA256Hash
+	valueHash []byte
+}
+
+// maxCachedChangedDomains is the maximum number of results of GetChangedDomains kept by a DomainMap.
+const maxCachedChangedDomains = 64
+
+// GetChangedDomains returns the domains whose domain trees differ between the
+// maps with roots `first` and `second`, in the order of their sparse merkle tree paths.
+// The returned slice is shared, and must not be modified.
+//
+// The two sparse merkle trees are traversed simultaneously, skipping any subtree
+// that is identical in both maps, so the cost is proportional to the number of changes.
+// Since the trees with a given root never change, the results are cached, so that
+// clients can page through the changes (see get-changed-domains) without recomputing them.
+func (dm *DomainMap) GetChangedDomains(first, second []byte) ([]ChangedDomain, error) {
+	var key [2]ct.SHA256Hash
+	copy(key[0][:], first)
+	copy(key[1][:], second)
+	dm.mChangedDomains.Lock()
+	changes, ok := dm.changedDomains[key]
+	dm.mChangedDomains.Unlock()
+	if ok {
+		return changes, nil
+	}
+
+	dm.m.RLock()
+	err := dm.diffNodes(first, second, &changes)
+	dm.m.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	dm.mChangedDomains.Lock()
+	defer dm.mChangedDomains.Unlock()
+	if len(dm.changedDomains) >= maxCachedChangedDomains {
+		dm.changedDomains = make(map[[2]ct.SHA256Hash][]ChangedDomain)
+	}
+	dm.changedDomains[key] = changes
+	return changes, nil
+}
+
+// getNode returns the map store data for a sparse merkle tree node,
+// or nil if the node is the placeholder (empty subtree).
+func (dm *DomainMap) getNode(hash []byte) ([]byte, error) {
+	if bytes.Equal(hash, dm.sparseStore.Placeholder()) {
+		return nil, nil
+	}
+	data, err := dm.sparseStore.Get(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 1+2*sha256.Size || (data[0] != SMTLeafPrefix && data[0] != SMTNodePrefix) {
+		return nil, fmt.Errorf("invalid node data for hash %x", hash)
+	}
+	return data, nil
+}
+
+func (dm *DomainMap) diffNodes(first, second []byte, changes *[]ChangedDomain) error {
+	if bytes.Equal(first, second) {
+		return nil
+	}
+	firstData, err := dm.getNode(first)
+	if err != nil {
+		return err
+	}
+	secondData, err := dm.getNode(second)
+	if err != nil {
+		return err
+	}
+
+	if firstData != nil && secondData != nil && firstData[0] == SMTNodePrefix && secondData[0] == SMTNodePrefix {
+		if err := dm.diffNodes(firstData[1:1+sha256.Size], secondData[1:1+sha256.Size], changes); err != nil {
+			return err
+		}
+		return dm.diffNodes(firstData[1+sha256.Size:], secondData[1+sha256.Size:], changes)
+	}
+
+	// At least one of the subtrees is either empty or a single leaf,
+	// so all leaves of the other subtree are new, removed or moved.
+	var firstLeaves, secondLeaves []smtLeaf
+	if err := dm.collectLeaves(firstData, &firstLeaves); err != nil {
+		return err
+	}
+	if err := dm.collectLeaves(secondData, &secondLeaves); err != nil {
+		return err
+	}
+	oldValues := make(map[ct.SHA256Hash][]byte, len(firstLeaves))
+	for _, leaf := range firstLeaves {
+		oldValues[leaf.path] = leaf.valueHash
+	}
+	for _, leaf := range secondLeaves {
+		oldValue, ok := oldValues[leaf.path]
+		delete(oldValues, leaf.path)
+		if ok && bytes.Equal(oldValue, leaf.valueHash) {
+			continue
+		}
+		if err := dm.appendChange(leaf.path, oldValue, leaf.valueHash, changes); err != nil {
+			return err
+		}
+	}
+	for _, leaf := range firstLeaves {
+		if _, ok := oldValues[leaf.path]; ok {
+			if err := dm.appendChange(leaf.path, leaf.valueHash, nil, changes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectLeaves appends all leaves of the subtree with the specified node data, from left to right.
+func (dm *DomainMap) collectLeaves(data []byte, leaves *[]smtLeaf) error {
+	if data == nil {
+		return nil
+	}
+	if data[0] == SMTLeafPrefix {
+		var leaf smtLeaf
+		copy(leaf.path[:], data[1:1+sha256.Size])
+		leaf.valueHash = data[1+sha256.Size:]
+		*leaves = append(*leaves, leaf)
+		return nil
+	}
+	for _, child := range [][]byte{data[1 : 1+sha256.Size], data[1+sha256.Size:]} {
+		childData, err := dm.getNode(child)
+		if err != nil {
+			return err
+		}
+		if err := dm.collectLeaves(childData, leaves); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dm *DomainMap) appendChange(path ct.SHA256Hash, oldValueHash, newValueHash []byte, changes *[]ChangedDomain) error {
+	domain, ok := dm.domainsByPath[path]
+	if !ok {
+		return fmt.Errorf("unknown domain for path %x", path)
+	}
+	change := ChangedDomain{DomainName: domain}
+	var err error
+	if change.OldTreeSize, err = dm.getTreeSizeForValueHash(oldValueHash); err != nil {
+		return err
+	}
+	if change.NewTreeSize, err = dm.getTreeSizeForValueHash(newValueHash); err != nil {
+		return err
+	}
+	*changes = append(*changes, change)
+	return nil
+}
+
+// getTreeSizeForValueHash returns the domain tree size stored in the
+// sparse merkle tree value with the specified hash (0 if valueHash is nil).
+func (dm *DomainMap) getTreeSizeForValueHash(valueHash []byte) (uint64, error) {
+	if valueHash == nil {
+		return 0, nil
+	}
+	value, err := dm.sparseStore.Get(valueHash)
+	if err != nil {
+		return 0, err
+	}
+	var root DomainTreeRoot
+	if rest, err := tls.Unmarshal(value, &root); err != nil {
+		return 0, err
+	} else if len(rest) != 0 {
+		return 0, fmt.Errorf("invalid domain tree root: %d trailing bytes", len(rest))
+	}
+	return root.DomainTreeSize, nil
+}
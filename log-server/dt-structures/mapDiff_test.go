@@ -0,0 +1,58 @@
+package dt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+)
+
+func TestGetChangedDomains(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+
+	addTestCertificates(t, dm, c, 0, 0, 4, []string{"a.com", "b.com"})
+	first := dm.GetLatestSMH()
+	addTestCertificates(t, dm, c, 0, 4, 7, []string{"b.com", "c.com", "d.com"})
+	second := dm.GetLatestSMH()
+
+	tests := []struct {
+		name          string
+		first, second []byte
+		want          map[string][2]uint64
+	}{
+		{"from the empty map", make([]byte, sha256.Size), first.MapRootHash[:], map[string][2]uint64{"a.com": {0, 2}, "b.com": {0, 2}}},
+		{"between SMHs", first.MapRootHash[:], second.MapRootHash[:], map[string][2]uint64{"b.com": {2, 3}, "c.com": {0, 1}, "d.com": {0, 1}}},
+		{"same SMH", second.MapRootHash[:], second.MapRootHash[:], map[string][2]uint64{}},
+	}
+	for _, tt := range tests {
+		changes, err := dm.GetChangedDomains(tt.first, tt.second)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := make(map[string][2]uint64)
+		for _, c := range changes {
+			got[c.DomainName] = [2]uint64{c.OldTreeSize, c.NewTreeSize}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, expected %v", tt.name, got, tt.want)
+		}
+		// the cached result is the same
+		cached, err := dm.GetChangedDomains(tt.first, tt.second)
+		if err != nil || !reflect.DeepEqual(cached, changes) {
+			t.Errorf("%s (cached): got %v (%v), expected %v", tt.name, cached, err, changes)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package mapclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// GetChangedDomains executes `GET /dt/v1/get-changed-domains`
+func (mc *MapClient) GetChangedDomains(ctx context.Context, req *ds.GetChangedDomainsRequest) (*ds.GetChangedDomainsResponse, error) {
+	var resp ds.GetChangedDomainsResponse
+	err := mc.get(ctx, "dt/v1/get-changed-domains", &resp, req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAndVerifyChangedDomains executes `GET /dt/v1/get-changed-domains`, starting at the
+// `start`-th changed domain, and verifies the old and new domain tree roots of every
+// returned domain against the map roots of both SMHs.
+// A nil `first` SMH refers to the empty map.
+//
+// The proofs show that each returned domain did change, but not that the server
+// returned all changed domains.
+func (mc *MapClient) GetAndVerifyChangedDomains(ctx context.Context, first, second *ds.GetSMHResponse, start uint64) (*ds.GetChangedDomainsResponse, error) {
+	var firstSize uint64
+	firstRoot := make([]byte, sha256.Size)
+	if first != nil {
+		firstSize = first.MapSize
+		firstRoot = first.MapRootHash[:]
+	}
+	resp, err := mc.GetChangedDomains(ctx, &ds.GetChangedDomainsRequest{
+		First:  firstSize,
+		Second: second.MapSize,
+		Start:  start,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.First.Domains) != len(resp.Second.Domains) {
+		return nil, fmt.Errorf("invalid changed domains: got %d old roots and %d new roots", len(resp.First.Domains), len(resp.Second.Domains))
+	}
+	if start+uint64(len(resp.Second.Domains)) > resp.Total {
+		return nil, fmt.Errorf("invalid changed domains: got %d domains starting at %d, but only %d changed", len(resp.Second.Domains), start, resp.Total)
+	}
+	domains := make([]string, len(resp.Second.Domains))
+	for i := range resp.Second.Domains {
+		oldRoot, newRoot := &resp.First.Domains[i], &resp.Second.Domains[i]
+		if oldRoot.NormalizedDomainName != newRoot.NormalizedDomainName {
+			return nil, fmt.Errorf("invalid changed domains: domain %d is %q in the first map and %q in the second", i, oldRoot.NormalizedDomainName, newRoot.NormalizedDomainName)
+		}
+		if oldRoot.DomainTreeSize >= newRoot.DomainTreeSize {
+			return nil, fmt.Errorf("invalid changed domains: domain tree for %q did not grow (%d -> %d)", newRoot.NormalizedDomainName, oldRoot.DomainTreeSize, newRoot.DomainTreeSize)
+		}
+		domains[i] = newRoot.NormalizedDomainName
+	}
+
+	if err := VerifyDomainRootsAndMultiProof(domains, &resp.First, firstRoot); err != nil {
+		return nil, fmt.Errorf("error verifying old domain tree roots: %w", err)
+	}
+	if err := VerifyDomainRootsAndMultiProof(domains, &resp.Second, second.MapRootHash[:]); err != nil {
+		return nil, fmt.Errorf("error verifying new domain tree roots: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,53 @@
+package mapclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// GetAndVerifyCheckpoint executes `GET /dt/v1/checkpoint`
+// and verifies the checkpoint signature.
+// The returned SMH has no timestamp and no TLS signature (see ParseAndVerifySMH).
+func (mc *MapClient) GetAndVerifyCheckpoint(ctx context.Context) (*ds.GetSMHResponse, error) {
+	data, err := mc.GetCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mc.ParseAndVerifySMH(data)
+}
+
+// GetCheckpoint executes `GET /dt/v1/checkpoint`
+// and returns the signed note as it was received.
+func (mc *MapClient) GetCheckpoint(ctx context.Context) ([]byte, error) {
+	return mc.getRaw(ctx, "dt/v1/checkpoint", &ds.GetCheckpointRequest{})
+}
+
+// ParseAndVerifySMH parses and verifies a map head in either of the forms
+// published by the server: the JSON SMH returned by `get-smh` or the signed
+// note checkpoint returned by `checkpoint`.
+//
+// Checkpoints carry neither a timestamp nor a TLS signature, so the Timestamp
+// and MapHeadSignature of an SMH parsed from a checkpoint are always empty.
+func (mc *MapClient) ParseAndVerifySMH(data []byte) (*ds.GetSMHResponse, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var resp ds.GetSMHResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("invalid SMH: %w", err)
+		}
+		if err := mc.verifySMH(&resp.SignedMapHead); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}
+
+	_, head, err := dt.VerifyCheckpoint(data, mc.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ds.GetSMHResponse{SignedMapHead: dt.SignedMapHead{MapHead: head}}, nil
+}
@@ -0,0 +1,65 @@
+package mapclient
+
+import (
+	"context"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// Client is the interface of a domain map client, implemented by MapClient
+// over both the JSON HTTP API (New) and the gRPC API (NewGRPC).
+//
+// The GetAndVerify* methods verify the server's responses;
+// the other Get* methods return the responses as they were received.
+// Every method that contacts the map takes a context, which bounds all of its
+// calls and retries (see RetryPolicy).
+type Client interface {
+	URI() string
+
+	GetAndVerifySMH(ctx context.Context) (*ds.GetSMHResponse, error)
+	GetAndVerifySMHForSize(ctx context.Context, mapSize uint64) (*ds.GetSMHResponse, error)
+	GetAndVerifySMHRange(ctx context.Context, start, end uint64) (*ds.GetSMHRangeResponse, error)
+	GetAndVerifySMHAt(ctx context.Context, timestamp uint64) (*ds.GetSMHResponse, error)
+	GetCheckpoint(ctx context.Context) ([]byte, error)
+	GetAndVerifyCheckpoint(ctx context.Context) (*ds.GetSMHResponse, error)
+	VerifySMHConsistency(ctx context.Context, first, second *ds.GetSMHResponse) error
+
+	GetDomainRootAndProof(ctx context.Context, req *ds.GetDomainRootAndProofRequest) (*ds.GetDomainRootAndProofResponse, error)
+	GetAndVerifyDomainRootAndProof(ctx context.Context, domain string, smh *ds.GetSMHResponse) (*ds.GetDomainRootAndProofResponse, error)
+	GetDomainRootsAndMultiProof(ctx context.Context, req *ds.GetDomainRootsAndMultiProofRequest) (*ds.GetDomainRootsAndMultiProofResponse, error)
+	GetAndVerifyDomainRootsAndMultiProof(ctx context.Context, domains []string, smh *ds.GetSMHResponse) (*ds.GetDomainRootsAndMultiProofResponse, error)
+	GetChangedDomains(ctx context.Context, req *ds.GetChangedDomainsRequest) (*ds.GetChangedDomainsResponse, error)
+	GetAndVerifyChangedDomains(ctx context.Context, first, second *ds.GetSMHResponse, start uint64) (*ds.GetChangedDomainsResponse, error)
+
+	GetConsistencyProof(ctx context.Context, req *ds.GetConsistencyProofRequest) (*ds.GetConsistencyProofResponse, error)
+	GetAndVerifyConsistencyProof(ctx context.Context, domain string, firstSize uint64, firstRoot []byte, secondSize uint64, secondRoot []byte) error
+	GetEntries(ctx context.Context, req *ds.GetEntriesRequest) (*ds.GetEntriesResponse, error)
+	ForEachEntry(ctx context.Context, domain string, start, end uint64, fn func(index uint64, entry dt.DomainTreeEntry) error) error
+	GetCertificates(ctx context.Context, req *ds.GetCertificatesRequest) (*ds.GetCertificatesResponse, error)
+	GetEntryAndProof(ctx context.Context, req *ds.GetEntryAndProofRequest) (*ds.GetEntryAndProofResponse, error)
+	GetAndVerifyEntryAndProof(ctx context.Context, req *ds.GetEntryAndProofRequest, version ct.Version, rootHash []byte) (dt.DomainTreeEntry, error)
+	GetDomainTreeIndex(ctx context.Context, req *ds.GetDomainTreeIndexRequest) (*ds.GetDomainTreeIndexResponse, error)
+	GetDomainUpdates(ctx context.Context, req *ds.GetDomainUpdatesRequest) (*ds.GetDomainUpdatesResponse, error)
+	GetAndVerifyDomainUpdates(ctx context.Context, req *ds.GetDomainUpdatesRequest, fromRoot []byte) (*ds.GetDomainUpdatesResponse, []DomainUpdate, error)
+	GetCertificatePlacements(ctx context.Context, req *ds.GetCertificatePlacementsRequest) (*ds.GetCertificatePlacementsResponse, error)
+	GetAndVerifyCertificatePlacements(ctx context.Context, logIndex, certIndex uint64, smh *ds.GetSMHResponse, start uint64) (*ds.GetCertificatePlacementsResponse, error)
+
+	GetSourceLogs(ctx context.Context, req *ds.GetSourceLogsRequest) (*ds.GetSourceLogsResponse, error)
+	ForEachSourceLog(ctx context.Context, start, end uint64, fn func(index uint64, logID []byte) error) error
+	GetSourceLogAndProof(ctx context.Context, req *ds.GetSourceLogAndProofRequest) (*ds.GetSourceLogAndProofResponse, error)
+	GetAndVerifySourceLogAndProof(ctx context.Context, index uint64, smh *ds.GetSMHResponse) ([]byte, error)
+	GetSourceConsistencyProof(ctx context.Context, req *ds.GetSourceConsistencyProofRequest) (*ds.GetSourceConsistencyProofResponse, error)
+	GetAndVerifySourceConsistencyProof(ctx context.Context, first, second *ds.GetSMHResponse) error
+
+	GetAndVerifyInclusionPromise(ctx context.Context, req *ds.GetInclusionPromiseRequest) (*ds.GetInclusionPromiseResponse, error)
+	CheckInclusionPromise(ctx context.Context, promise *ds.GetInclusionPromiseResponse, smh *ds.GetSMHResponse, mmd time.Duration) (bool, error)
+
+	AddCosignature(ctx context.Context, req *ds.AddCosignatureRequest) error
+
+	Subscribe(ctx context.Context, domains []string, fn func(*SubscriptionEvent) error) error
+}
+
+var _ Client = (*MapClient)(nil)
@@ -0,0 +1,101 @@
+package mapclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// GetAndVerifyConsistencyProof verifies that the domain tree for the specified domain with
+// size secondSize and root secondRoot is an extension of the one with size firstSize and
+// root firstRoot, executing `GET /dt/v1/get-consistency-proof` if needed.
+// Both roots should have been verified against the map (e.g. with VerifyDomainRootAndProof).
+func (mc *MapClient) GetAndVerifyConsistencyProof(ctx context.Context, domain string, firstSize uint64, firstRoot []byte, secondSize uint64, secondRoot []byte) error {
+	if secondSize < firstSize {
+		return fmt.Errorf("domain tree for %q shrank: %d < %d", domain, secondSize, firstSize)
+	}
+	if firstSize == secondSize {
+		if !bytes.Equal(firstRoot, secondRoot) {
+			return fmt.Errorf("two different roots for the domain tree for %q with size %d", domain, firstSize)
+		}
+		return nil
+	}
+	if firstSize == 0 {
+		return nil
+	}
+	resp, err := mc.GetConsistencyProof(ctx, &ds.GetConsistencyProofRequest{
+		DomainName: domain,
+		First:      firstSize,
+		Second:     secondSize,
+	})
+	if err != nil {
+		return err
+	}
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	err = verifier.VerifyConsistencyProof(int64(firstSize), int64(secondSize), firstRoot, secondRoot, resp.Proof)
+	if err != nil {
+		return fmt.Errorf("error verifying domain tree consistency for %q: %w", domain, err)
+	}
+	return nil
+}
+
+// GetAndVerifySourceConsistencyProof verifies that the source tree of the (verified) SMH
+// `second` is an extension of the one of `first`, executing
+// `GET /dt/v1/get-source-consistency-proof` if needed.
+func (mc *MapClient) GetAndVerifySourceConsistencyProof(ctx context.Context, first, second *ds.GetSMHResponse) error {
+	firstSize := uint64(len(first.SourceLogRevisions))
+	secondSize := uint64(len(second.SourceLogRevisions))
+	if secondSize < firstSize {
+		return fmt.Errorf("source log count decreased: %d < %d", secondSize, firstSize)
+	}
+	if firstSize == secondSize {
+		if second.SourceTreeRootHash != first.SourceTreeRootHash {
+			return fmt.Errorf("two different source tree roots for source tree size %d", firstSize)
+		}
+		return nil
+	}
+	if firstSize == 0 {
+		return nil
+	}
+	resp, err := mc.GetSourceConsistencyProof(ctx, &ds.GetSourceConsistencyProofRequest{
+		First:  firstSize,
+		Second: secondSize,
+	})
+	if err != nil {
+		return err
+	}
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	err = verifier.VerifyConsistencyProof(int64(firstSize), int64(secondSize), first.SourceTreeRootHash[:], second.SourceTreeRootHash[:], resp.Proof)
+	if err != nil {
+		return fmt.Errorf("error verifying source tree consistency: %w", err)
+	}
+	return nil
+}
+
+// GetAndVerifySourceLogAndProof executes `GET /dt/v1/get-source-log-and-proof` and verifies
+// that the returned log ID is included, at the specified index, in the source tree of the
+// (verified) SMH. It returns the log ID.
+func (mc *MapClient) GetAndVerifySourceLogAndProof(ctx context.Context, index uint64, smh *ds.GetSMHResponse) ([]byte, error) {
+	sourceTreeSize := uint64(len(smh.SourceLogRevisions))
+	if index >= sourceTreeSize {
+		return nil, fmt.Errorf("source log %d is outside of the source tree (size %d)", index, sourceTreeSize)
+	}
+	resp, err := mc.GetSourceLogAndProof(ctx, &ds.GetSourceLogAndProofRequest{
+		Index:          index,
+		SourceTreeSize: sourceTreeSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	leafHash := rfc6962.DefaultHasher.HashLeaf(resp.LogID)
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	err = verifier.VerifyInclusionProof(int64(index), int64(sourceTreeSize), resp.AuditPath, smh.SourceTreeRootHash[:], leafHash)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying source log %d: %w", index, err)
+	}
+	return resp.LogID, nil
+}
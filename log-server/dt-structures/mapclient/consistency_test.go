@@ -0,0 +1,151 @@
+package mapclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// responseTransport returns the same response to every call to a command.
+type responseTransport struct {
+	responses map[string]interface{}
+}
+
+func (rt *responseTransport) Get(ctx context.Context, command string, output interface{}, params interface{}) error {
+	data, err := json.Marshal(rt.responses[command])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, output)
+}
+
+func (rt *responseTransport) GetRaw(ctx context.Context, command string, params interface{}) ([]byte, error) {
+	return json.Marshal(rt.responses[command])
+}
+
+func (rt *responseTransport) Post(ctx context.Context, command string, output interface{}, input interface{}) error {
+	return rt.Get(ctx, command, output, input)
+}
+
+func (rt *responseTransport) Subscribe(ctx context.Context, command string, params interface{}, fn func(event string, data []byte) error) error {
+	return nil
+}
+
+// newTestTree returns a tree whose leaves are the single bytes 0, ..., size-1.
+func newTestTree(size int) *merkle.InMemoryMerkleTree {
+	tree := merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher)
+	for i := 0; i < size; i++ {
+		tree.AddLeaf([]byte{byte(i)})
+	}
+	return tree
+}
+
+func proofHashes(path []merkle.TreeEntryDescriptor) [][]byte {
+	var proof [][]byte
+	for _, node := range path {
+		proof = append(proof, node.Value.Hash())
+	}
+	return proof
+}
+
+// tampered returns a copy of the proof with a flipped bit in its first hash.
+func tampered(proof [][]byte) [][]byte {
+	copied := make([][]byte, len(proof))
+	for i, h := range proof {
+		copied[i] = append([]byte(nil), h...)
+	}
+	copied[0][0] ^= 1
+	return copied
+}
+
+// smhWithSourceTree returns an SMH with the specified source tree, and no other contents.
+func smhWithSourceTree(size int, root []byte) *ds.GetSMHResponse {
+	var smh ds.GetSMHResponse
+	smh.SourceLogRevisions = make([]dt.LogRevision, size)
+	copy(smh.SourceTreeRootHash[:], root)
+	return &smh
+}
+
+func TestConsistencyVerifiers(t *testing.T) {
+	ctx := context.Background()
+	tree := newTestTree(8)
+	root4, root8 := tree.RootAtSnapshot(4).Hash(), tree.RootAtSnapshot(8).Hash()
+	proof := proofHashes(tree.SnapshotConsistency(4, 8))
+	newClient := func(command string, resp interface{}) *mapclient.MapClient {
+		mc := mapclient.NewWithTransport("test/", &responseTransport{map[string]interface{}{command: resp}}, nil)
+		mc.SetRetryPolicy(mapclient.RetryPolicy{})
+		return mc
+	}
+
+	domainTests := []struct {
+		name                  string
+		firstSize, secondSize uint64
+		firstRoot, secondRoot []byte
+		proof                 [][]byte
+		wantErr               bool
+	}{
+		{"valid", 4, 8, root4, root8, proof, false},
+		{"same tree", 8, 8, root8, root8, nil, false},
+		{"shrinking tree", 8, 4, root8, root4, proof, true},
+		{"same size, different root", 8, 8, root8, root4, nil, true},
+		{"bad proof", 4, 8, root4, root8, tampered(proof), true},
+		{"different second root", 4, 8, root4, tree.RootAtSnapshot(7).Hash(), proof, true},
+	}
+	for _, tt := range domainTests {
+		mc := newClient("dt/v1/get-consistency-proof", ds.GetConsistencyProofResponse{Proof: tt.proof})
+		err := mc.GetAndVerifyConsistencyProof(ctx, "a.com", tt.firstSize, tt.firstRoot, tt.secondSize, tt.secondRoot)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetAndVerifyConsistencyProof (%s): got error %v, expected error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	sourceTests := []struct {
+		name          string
+		first, second *ds.GetSMHResponse
+		proof         [][]byte
+		wantErr       bool
+	}{
+		{"valid", smhWithSourceTree(4, root4), smhWithSourceTree(8, root8), proof, false},
+		{"shrinking tree", smhWithSourceTree(8, root8), smhWithSourceTree(4, root4), proof, true},
+		{"same size, different root", smhWithSourceTree(8, root8), smhWithSourceTree(8, root4), nil, true},
+		{"bad proof", smhWithSourceTree(4, root4), smhWithSourceTree(8, root8), tampered(proof), true},
+	}
+	for _, tt := range sourceTests {
+		mc := newClient("dt/v1/get-source-consistency-proof", ds.GetSourceConsistencyProofResponse{Proof: tt.proof})
+		err := mc.GetAndVerifySourceConsistencyProof(ctx, tt.first, tt.second)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetAndVerifySourceConsistencyProof (%s): got error %v, expected error: %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// the leaves of the in-memory tree are numbered from 1
+	auditPath := proofHashes(tree.PathToRootAtSnapshot(3, 8))
+	smh := smhWithSourceTree(8, root8)
+	logTests := []struct {
+		name    string
+		index   uint64
+		resp    ds.GetSourceLogAndProofResponse
+		wantErr bool
+	}{
+		{"valid", 2, ds.GetSourceLogAndProofResponse{LogID: []byte{2}, AuditPath: auditPath}, false},
+		{"other log", 2, ds.GetSourceLogAndProofResponse{LogID: []byte{3}, AuditPath: auditPath}, true},
+		{"bad audit path", 2, ds.GetSourceLogAndProofResponse{LogID: []byte{2}, AuditPath: tampered(auditPath)}, true},
+		{"other index", 3, ds.GetSourceLogAndProofResponse{LogID: []byte{2}, AuditPath: auditPath}, true},
+		{"outside of the source tree", 8, ds.GetSourceLogAndProofResponse{LogID: []byte{2}, AuditPath: auditPath}, true},
+	}
+	for _, tt := range logTests {
+		mc := newClient("dt/v1/get-source-log-and-proof", tt.resp)
+		logID, err := mc.GetAndVerifySourceLogAndProof(ctx, tt.index, smh)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetAndVerifySourceLogAndProof (%s): got error %v, expected error: %v", tt.name, err, tt.wantErr)
+		} else if err == nil && string(logID) != string(tt.resp.LogID) {
+			t.Errorf("GetAndVerifySourceLogAndProof (%s): got log ID %x, expected %x", tt.name, logID, tt.resp.LogID)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package mapclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// GetAndVerifyDomainRootAndProof executes `GET /dt/v1/get-domain-root-and-proof`
+// and verifies the returned domain tree root against the map root of the specified SMH.
+func (mc *MapClient) GetAndVerifyDomainRootAndProof(ctx context.Context, domain string, smh *ds.GetSMHResponse) (*ds.GetDomainRootAndProofResponse, error) {
+	resp, err := mc.GetDomainRootAndProof(ctx, &ds.GetDomainRootAndProofRequest{
+		DomainName:    domain,
+		DomainMapSize: smh.MapSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyDomainRootAndProof(domain, resp, smh.MapRootHash[:]); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VerifyDomainRootAndProof verifies that the response refers to the specified domain
+// (after normalization) and that its audit path proves the returned domain tree root
+// against the specified map root. If the domain tree size is 0, the audit path
+// must prove that the domain is not in the map.
+//
+// The domain's leaf has the TLS-encoded DomainTreeRoot as its value
+// (see dt.DomainMap.UpdateDomainTreeRoot), and the audit path lists the side nodes
+// from the leaf up to the root (see dt.DomainMap.GetProofForDomain).
+func VerifyDomainRootAndProof(domain string, resp *ds.GetDomainRootAndProofResponse, mapRoot []byte) error {
+	normalizedDomain, err := util.NormalizeDomainName(domain)
+	if err != nil {
+		return fmt.Errorf("invalid domain name %q: %w", domain, err)
+	}
+	if resp.NormalizedDomainName != normalizedDomain {
+		return fmt.Errorf("invalid domain root: got domain %q, requested %q", resp.NormalizedDomainName, normalizedDomain)
+	}
+	if len(resp.AuditPath) > 8*sha256.Size {
+		return fmt.Errorf("invalid audit path for %q: length=%d", normalizedDomain, len(resp.AuditPath))
+	}
+	for _, sideNode := range resp.AuditPath {
+		if len(sideNode) != sha256.Size {
+			return fmt.Errorf("invalid audit path for %q: side node length=%d, expected %d", normalizedDomain, len(sideNode), sha256.Size)
+		}
+	}
+
+	path := util.HashBytes([]byte(normalizedDomain))
+	hash, err := smtLeafHashForDomain(path, &ds.DomainRootInMultiProof{
+		DomainTreeSize:        resp.DomainTreeSize,
+		DomainTreeRootHash:    resp.DomainTreeRootHash,
+		NormalizedDomainName:  normalizedDomain,
+		NonMembershipLeafData: resp.NonMembershipLeafData,
+	})
+	if err != nil {
+		return err
+	}
+	depth := len(resp.AuditPath)
+	for i, sideNode := range resp.AuditPath {
+		if dt.SMTPathBit(path, depth-1-i) {
+			hash = smtNodeHash(sideNode, hash)
+		} else {
+			hash = smtNodeHash(hash, sideNode)
+		}
+	}
+	if !bytes.Equal(hash, mapRoot) {
+		return fmt.Errorf("invalid audit path for %q: calculated root %x != map root %x", normalizedDomain, hash, mapRoot)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package mapclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/logid"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// newTestMap starts a map server whose latest SMH contains the specified domains,
+// each with a domain tree of the specified size.
+func newTestMap(t *testing.T, domains map[string]uint64) (*mapclient.MapClient, *ds.GetSMHResponse) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	svr, _ := ds.NewServer(dm, "localhost", 0)
+	hs := httptest.NewServer(svr.Handler)
+	t.Cleanup(hs.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	t.Cleanup(func() { cancel(); <-stopped })
+
+	tx := dt.WorkerTransaction{
+		LogID:                  logid.LogID{1},
+		NewCertificatesIndices: make(map[string][]uint64),
+		CertificateHashes:      make(map[uint64]dt.CertificateHashes),
+	}
+	var certIndex uint64
+	for d, size := range domains {
+		for i := uint64(0); i < size; i++ {
+			tx.NewCertificatesIndices[d] = append(tx.NewCertificatesIndices[d], certIndex)
+			tx.CertificateHashes[certIndex] = dt.CertificateHashes{LeafHash: ct.SHA256Hash{byte(certIndex), byte(certIndex >> 8), 1}}
+			certIndex++
+		}
+	}
+	tx.LogRevision = dt.LogRevision{TreeSize: certIndex}
+	c <- tx
+	for deadline := time.Now().Add(10 * time.Second); dm.GetLatestSMH().MapSize != certIndex; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for an SMH")
+		}
+	}
+
+	mc := mapclient.New(hs.URL+"/", hs.Client(), &key.PublicKey)
+	smh, err := mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mc, smh
+}
+
+func copyResponse(resp *ds.GetDomainRootAndProofResponse) *ds.GetDomainRootAndProofResponse {
+	cp := *resp
+	cp.DomainTreeRootHash = append([]byte(nil), resp.DomainTreeRootHash...)
+	if resp.NonMembershipLeafData != nil {
+		cp.NonMembershipLeafData = append([]byte(nil), resp.NonMembershipLeafData...)
+	}
+	cp.AuditPath = make([][]byte, len(resp.AuditPath))
+	for i, n := range resp.AuditPath {
+		cp.AuditPath[i] = append([]byte(nil), n...)
+	}
+	return &cp
+}
+
+func TestVerifyDomainRootAndProof(t *testing.T) {
+	domains := map[string]uint64{"a.com": 1, "b.com": 2, "c.com": 3, "d.com": 4}
+	for i := 0; i < 60; i++ {
+		domains[fmt.Sprintf("d%d.com", i)] = uint64(i%4 + 1)
+	}
+	mc, smh := newTestMap(t, domains)
+	ctx := context.Background()
+
+	for d, size := range domains {
+		resp, err := mc.GetAndVerifyDomainRootAndProof(ctx, d, smh)
+		if err != nil {
+			t.Fatalf("%s: %v", d, err)
+		}
+		if resp.DomainTreeSize != size {
+			t.Errorf("%s: got domain tree size %d, expected %d", d, resp.DomainTreeSize, size)
+		}
+	}
+
+	// Find missing domains whose paths end in an empty node and in the leaf of another domain
+	var emptyNode, otherLeaf *ds.GetDomainRootAndProofResponse
+	for i := 0; i < 1000 && (emptyNode == nil || otherLeaf == nil); i++ {
+		resp, err := mc.GetAndVerifyDomainRootAndProof(ctx, fmt.Sprintf("missing-%d.com", i), smh)
+		if err != nil {
+			t.Fatalf("missing-%d.com: %v", i, err)
+		}
+		if resp.DomainTreeSize != 0 {
+			t.Fatalf("missing-%d.com: got domain tree size %d", i, resp.DomainTreeSize)
+		}
+		if resp.NonMembershipLeafData == nil {
+			emptyNode = resp
+		} else {
+			otherLeaf = resp
+		}
+	}
+	if emptyNode == nil || otherLeaf == nil {
+		t.Fatalf("no non-membership proofs of both kinds")
+	}
+
+	present, err := mc.GetDomainRootAndProof(ctx, &ds.GetDomainRootAndProofRequest{DomainName: "c.com", DomainMapSize: smh.MapSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(present.AuditPath) == 0 {
+		t.Fatalf("c.com: empty audit path")
+	}
+	root := smh.MapRootHash[:]
+	tests := []struct {
+		name   string
+		domain string
+		resp   *ds.GetDomainRootAndProofResponse
+		tamper func(*ds.GetDomainRootAndProofResponse)
+	}{
+		{"smaller tree", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.DomainTreeSize-- }},
+		{"other root hash", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.DomainTreeRootHash[0] ^= 1 }},
+		{"hidden domain", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.DomainTreeSize = 0 }},
+		{"other domain", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.NormalizedDomainName = "d.com" }},
+		{"tampered side node", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.AuditPath[0][0] ^= 1 }},
+		{"tampered top side node", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.AuditPath[len(r.AuditPath)-1][0] ^= 1 }},
+		{"missing side node", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.AuditPath = r.AuditPath[1:] }},
+		{"extra side node", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) {
+			r.AuditPath = append([][]byte{make([]byte, 32)}, r.AuditPath...)
+		}},
+		{"short side node", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.AuditPath[0] = r.AuditPath[0][:31] }},
+		{"unexpected leaf data", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) { r.NonMembershipLeafData = otherLeaf.NonMembershipLeafData }},
+		{"fake domain in empty node", emptyNode.NormalizedDomainName, emptyNode, func(r *ds.GetDomainRootAndProofResponse) {
+			r.DomainTreeSize = 1
+			r.DomainTreeRootHash = make([]byte, 32)
+		}},
+		{"tampered leaf data", otherLeaf.NormalizedDomainName, otherLeaf, func(r *ds.GetDomainRootAndProofResponse) { r.NonMembershipLeafData[40] ^= 1 }},
+		{"dropped leaf data", otherLeaf.NormalizedDomainName, otherLeaf, func(r *ds.GetDomainRootAndProofResponse) { r.NonMembershipLeafData = nil }},
+		{"own leaf as other leaf", "c.com", present, func(r *ds.GetDomainRootAndProofResponse) {
+			r.DomainTreeSize = 0
+			r.NonMembershipLeafData = append([]byte{dt.SMTLeafPrefix}, make([]byte, 64)...)
+			copy(r.NonMembershipLeafData[1:], util.HashBytes([]byte("c.com")))
+		}},
+	}
+	for _, tt := range tests {
+		if err := mapclient.VerifyDomainRootAndProof(tt.domain, tt.resp, root); err != nil {
+			t.Fatalf("%s: the untampered response is invalid: %v", tt.name, err)
+		}
+		resp := copyResponse(tt.resp)
+		tt.tamper(resp)
+		if err := mapclient.VerifyDomainRootAndProof(tt.domain, resp, root); err == nil {
+			t.Errorf("%s: tampered response verified", tt.name)
+		}
+	}
+
+	otherRoot := append([]byte(nil), root...)
+	otherRoot[0] ^= 1
+	if err := mapclient.VerifyDomainRootAndProof("c.com", present, otherRoot); err == nil {
+		t.Errorf("proof verified against another map root")
+	}
+}
@@ -0,0 +1,161 @@
+package mapclient
+
+import (
+	"context"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// A DomainUpdate is a verified entry of a `get-domain-updates` bundle.
+type DomainUpdate struct {
+	Index    uint64 // the index in the domain tree
+	Entry    dt.DomainTreeEntry
+	LogID    []byte // the ID of the CT log with index Entry.LogIndex in the source tree
+	LogEntry *ct.LogEntry
+}
+
+// GetDomainUpdates executes `GET /dt/v1/get-domain-updates`
+func (mc *MapClient) GetDomainUpdates(ctx context.Context, req *ds.GetDomainUpdatesRequest) (*ds.GetDomainUpdatesResponse, error) {
+	var resp ds.GetDomainUpdatesResponse
+	err := mc.get(ctx, "dt/v1/get-domain-updates", &resp, req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAndVerifyDomainUpdates executes `GET /dt/v1/get-domain-updates`
+// and verifies the returned bundle with VerifyDomainUpdates.
+func (mc *MapClient) GetAndVerifyDomainUpdates(ctx context.Context, req *ds.GetDomainUpdatesRequest, fromRoot []byte) (*ds.GetDomainUpdatesResponse, []DomainUpdate, error) {
+	bundle, err := mc.GetDomainUpdates(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	updates, err := mc.VerifyDomainUpdates(req, fromRoot, bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bundle, updates, nil
+}
+
+// VerifyDomainUpdates verifies a `get-domain-updates` bundle for the specified
+// request, without contacting the server. It verifies:
+//   - the SMH's signature (and cosignatures, if a witness policy is set);
+//   - the domain tree root, against the SMH's map root;
+//   - the consistency between the domain tree with size req.From and root fromRoot,
+//     and the returned domain tree (fromRoot is ignored if req.From is 0);
+//   - the inclusion of each entry in the domain tree;
+//   - the inclusion of each entry's CT leaf in its CT log, at the source log revision in the SMH,
+//     and that the CT leaf matches the entry;
+//   - the inclusion of each CT log in the SMH's source tree.
+//
+// It returns the bundle's entries, along with their parsed CT log entries.
+func (mc *MapClient) VerifyDomainUpdates(req *ds.GetDomainUpdatesRequest, fromRoot []byte, bundle *ds.GetDomainUpdatesResponse) ([]DomainUpdate, error) {
+	smh := &bundle.SMH
+	if smh.MapSize != req.MapSize {
+		return nil, fmt.Errorf("invalid domain updates: got SMH with map size %d, expected %d", smh.MapSize, req.MapSize)
+	}
+	if err := mc.verifySMH(&smh.SignedMapHead); err != nil {
+		return nil, err
+	}
+	if mc.witnessPolicy != nil {
+		if err := mc.witnessPolicy.Verify(smh); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := VerifyDomainRootsAndMultiProof([]string{req.DomainName}, &bundle.DomainRoot, smh.MapRootHash[:]); err != nil {
+		return nil, fmt.Errorf("error verifying domain tree root: %w", err)
+	}
+	domainRoot := &bundle.DomainRoot.Domains[0]
+	treeSize := domainRoot.DomainTreeSize
+	if req.From > treeSize {
+		return nil, fmt.Errorf("invalid domain updates: domain tree size (%d) < from (%d)", treeSize, req.From)
+	}
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	if req.From > 0 {
+		err := verifier.VerifyConsistencyProof(int64(req.From), int64(treeSize), fromRoot, domainRoot.DomainTreeRootHash, bundle.ConsistencyProof)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying domain tree consistency proof: %w", err)
+		}
+	}
+
+	sourceLogs := make(map[uint64]bool)
+	for _, sl := range bundle.SourceLogs {
+		sourceLogs[sl.LogIndex] = false
+	}
+	start := req.From
+	if req.Start > start {
+		start = req.Start
+	}
+	updates := make([]DomainUpdate, len(bundle.Entries))
+	for i := range bundle.Entries {
+		e := &bundle.Entries[i]
+		if e.Index != start+uint64(i) {
+			return nil, fmt.Errorf("invalid domain updates: got entry %d at position %d, expected %d", e.Index, i, start+uint64(i))
+		}
+		if e.Index >= treeSize {
+			return nil, fmt.Errorf("invalid domain updates: entry %d is outside of the domain tree (size %d)", e.Index, treeSize)
+		}
+		entry, err := verifyEntryAndProof(&e.GetEntryAndProofResponse, e.Index, treeSize, smh.Version, domainRoot.DomainTreeRootHash)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying entry %d: %w", e.Index, err)
+		}
+		if entry.LogIndex >= uint64(len(smh.SourceLogRevisions)) {
+			return nil, fmt.Errorf("entry %d refers to an unknown log (%d)", e.Index, entry.LogIndex)
+		}
+		if _, ok := sourceLogs[entry.LogIndex]; !ok {
+			return nil, fmt.Errorf("invalid domain updates: missing source log %d", entry.LogIndex)
+		}
+		sourceLogs[entry.LogIndex] = true
+
+		logEntry, err := ct.LogEntryFromLeaf(int64(entry.CertificateIndex), &ct.LeafEntry{LeafInput: e.LeafInput, ExtraData: e.ExtraData})
+		if logEntry == nil {
+			return nil, fmt.Errorf("error parsing CT entry for entry %d: %w", e.Index, err)
+		}
+		hashes, err := ds.HashesForLogEntry(logEntry)
+		if err != nil {
+			return nil, fmt.Errorf("error hashing CT entry for entry %d: %w", e.Index, err)
+		}
+		rev := smh.SourceLogRevisions[entry.LogIndex]
+		err = verifier.VerifyInclusionProof(int64(entry.CertificateIndex), int64(rev.TreeSize), e.CTAuditPath, rev.RootHash[:], hashes.LeafHash[:])
+		if err != nil {
+			return nil, fmt.Errorf("error verifying CT audit proof for entry %d: %w", e.Index, err)
+		}
+		if smh.Version >= dt.VersionV2 {
+			if hashes.LeafHash != entry.LeafHash {
+				return nil, fmt.Errorf("CT leaf hash of entry %d does not match the domain tree: %x != %x", e.Index, hashes.LeafHash, entry.LeafHash)
+			}
+			if hashes.Fingerprint != entry.CertificateFingerprint {
+				return nil, fmt.Errorf("certificate fingerprint of entry %d does not match the domain tree: %x != %x", e.Index, hashes.Fingerprint, entry.CertificateFingerprint)
+			}
+		}
+		updates[i] = DomainUpdate{Index: e.Index, Entry: entry, LogEntry: logEntry}
+	}
+
+	sourceTreeSize := uint64(len(smh.SourceLogRevisions))
+	logIDs := make(map[uint64][]byte, len(sourceLogs))
+	for _, sl := range bundle.SourceLogs {
+		if !sourceLogs[sl.LogIndex] {
+			continue
+		}
+		if sl.LogIndex >= sourceTreeSize {
+			return nil, fmt.Errorf("invalid domain updates: source log %d is outside of the source tree (size %d)", sl.LogIndex, sourceTreeSize)
+		}
+		leafHash := rfc6962.DefaultHasher.HashLeaf(sl.LogID)
+		err := verifier.VerifyInclusionProof(int64(sl.LogIndex), int64(sourceTreeSize), sl.AuditPath, smh.SourceTreeRootHash[:], leafHash)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying source log %d: %w", sl.LogIndex, err)
+		}
+		logIDs[sl.LogIndex] = sl.LogID
+	}
+	for i := range updates {
+		updates[i].LogID = logIDs[updates[i].Entry.LogIndex]
+	}
+	return updates, nil
+}
@@ -0,0 +1,66 @@
+package mapclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// EntryFromResponse returns the domain tree entry contained in a `get-entry-and-proof` response.
+// The CT leaf hash and certificate fingerprint are required for version 2 (and later) maps,
+// and ignored for version 1 maps.
+func EntryFromResponse(resp *ds.GetEntryAndProofResponse, version ct.Version) (dt.DomainTreeEntry, error) {
+	entry := dt.DomainTreeEntry{
+		LogIndex:         resp.Entry[0],
+		CertificateIndex: resp.Entry[1],
+	}
+	if version == dt.VersionV1 {
+		return entry, nil
+	}
+	if len(resp.LeafHash) != sha256.Size {
+		return dt.DomainTreeEntry{}, fmt.Errorf("invalid entry leaf hash: length=%d, expected %d", len(resp.LeafHash), sha256.Size)
+	}
+	if len(resp.CertificateFingerprint) != sha256.Size {
+		return dt.DomainTreeEntry{}, fmt.Errorf("invalid entry certificate fingerprint: length=%d, expected %d", len(resp.CertificateFingerprint), sha256.Size)
+	}
+	copy(entry.LeafHash[:], resp.LeafHash)
+	copy(entry.CertificateFingerprint[:], resp.CertificateFingerprint)
+	return entry, nil
+}
+
+// GetAndVerifyEntryAndProof executes `GET /dt/v1/get-entry-and-proof` and verifies
+// that the returned entry is included in the domain tree with the specified root,
+// using the leaf format of the specified map version.
+func (mc *MapClient) GetAndVerifyEntryAndProof(ctx context.Context, req *ds.GetEntryAndProofRequest, version ct.Version, rootHash []byte) (dt.DomainTreeEntry, error) {
+	resp, err := mc.GetEntryAndProof(ctx, req)
+	if err != nil {
+		return dt.DomainTreeEntry{}, err
+	}
+	return verifyEntryAndProof(resp, req.Index, req.DomainTreeSize, version, rootHash)
+}
+
+// verifyEntryAndProof verifies that the entry in a `get-entry-and-proof` response is included,
+// at the specified index, in the domain tree with the specified size and root.
+func verifyEntryAndProof(resp *ds.GetEntryAndProofResponse, index, treeSize uint64, version ct.Version, rootHash []byte) (dt.DomainTreeEntry, error) {
+	entry, err := EntryFromResponse(resp, version)
+	if err != nil {
+		return dt.DomainTreeEntry{}, err
+	}
+	leafData, err := entry.LeafData(version)
+	if err != nil {
+		return dt.DomainTreeEntry{}, err
+	}
+	leafHash := rfc6962.DefaultHasher.HashLeaf(leafData)
+	verifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+	err = verifier.VerifyInclusionProof(int64(index), int64(treeSize), resp.AuditPath, rootHash, leafHash)
+	if err != nil {
+		return dt.DomainTreeEntry{}, fmt.Errorf("error verifying domain tree audit proof: %w", err)
+	}
+	return entry, nil
+}
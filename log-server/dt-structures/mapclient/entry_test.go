@@ -0,0 +1,102 @@
+package mapclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+func TestEntryFromResponse(t *testing.T) {
+	resp := &ds.GetEntryAndProofResponse{
+		Entry:                  [2]uint64{1, 2},
+		LeafHash:               make([]byte, 32),
+		CertificateFingerprint: make([]byte, 32),
+	}
+	resp.LeafHash[0], resp.CertificateFingerprint[0] = 3, 4
+
+	entry, err := mapclient.EntryFromResponse(resp, dt.VersionV2)
+	want := dt.DomainTreeEntry{LogIndex: 1, CertificateIndex: 2, LeafHash: ct.SHA256Hash{3}, CertificateFingerprint: ct.SHA256Hash{4}}
+	if err != nil || entry != want {
+		t.Errorf("EntryFromResponse (version 2): got %+v (%v), expected %+v", entry, err, want)
+	}
+	// Version 1 entries don't commit to the hashes
+	entry, err = mapclient.EntryFromResponse(resp, dt.VersionV1)
+	want = dt.DomainTreeEntry{LogIndex: 1, CertificateIndex: 2}
+	if err != nil || entry != want {
+		t.Errorf("EntryFromResponse (version 1): got %+v (%v), expected %+v", entry, err, want)
+	}
+
+	for _, tamper := range []func(*ds.GetEntryAndProofResponse){
+		func(r *ds.GetEntryAndProofResponse) { r.LeafHash = nil },
+		func(r *ds.GetEntryAndProofResponse) { r.LeafHash = r.LeafHash[:31] },
+		func(r *ds.GetEntryAndProofResponse) { r.CertificateFingerprint = nil },
+		func(r *ds.GetEntryAndProofResponse) { r.CertificateFingerprint = append(r.CertificateFingerprint, 0) },
+	} {
+		cp := *resp
+		tamper(&cp)
+		if _, err := mapclient.EntryFromResponse(&cp, dt.VersionV2); err == nil {
+			t.Errorf("EntryFromResponse (version 2): accepted %+v", cp)
+		}
+		if _, err := mapclient.EntryFromResponse(&cp, dt.VersionV1); err != nil {
+			t.Errorf("EntryFromResponse (version 1): %v", err)
+		}
+	}
+}
+
+func TestGetAndVerifyEntryAndProof(t *testing.T) {
+	mc, smh := newTestMap(t, map[string]uint64{"a.com": 3})
+	ctx := context.Background()
+	root, err := mc.GetAndVerifyDomainRootAndProof(ctx, "a.com", smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smh.Version != dt.VersionV2 {
+		t.Fatalf("got map version %d, expected %d", smh.Version, dt.VersionV2)
+	}
+
+	for i := uint64(0); i < root.DomainTreeSize; i++ {
+		req := &ds.GetEntryAndProofRequest{DomainName: "a.com", Index: i, DomainTreeSize: root.DomainTreeSize}
+		entry, err := mc.GetAndVerifyEntryAndProof(ctx, req, smh.Version, root.DomainTreeRootHash)
+		if err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+		if entry.CertificateIndex != i || entry.LeafHash != (ct.SHA256Hash{byte(i), 0, 1}) {
+			t.Errorf("entry %d: got %+v", i, entry)
+		}
+		// Entries of version 2 trees don't verify as version 1 (or unknown) leaves
+		for _, version := range []ct.Version{dt.VersionV1, dt.VersionV2 + 1} {
+			if _, err := mc.GetAndVerifyEntryAndProof(ctx, req, version, root.DomainTreeRootHash); err == nil {
+				t.Errorf("entry %d: verified as a version %d leaf", i, version)
+			}
+		}
+	}
+
+	// A version 1 domain tree with a single entry, whose root is the hash of its leaf
+	resp := ds.GetEntryAndProofResponse{Entry: [2]uint64{0, 5}, AuditPath: [][]byte{}}
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer hs.Close()
+	v1 := mapclient.New(hs.URL+"/", hs.Client(), nil)
+	leafData, err := dt.DomainTreeEntry{LogIndex: 0, CertificateIndex: 5}.LeafData(dt.VersionV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1Root := rfc6962.DefaultHasher.HashLeaf(leafData)
+	req := &ds.GetEntryAndProofRequest{DomainName: "a.com", Index: 0, DomainTreeSize: 1}
+	if entry, err := v1.GetAndVerifyEntryAndProof(ctx, req, dt.VersionV1, v1Root); err != nil || entry.CertificateIndex != 5 {
+		t.Errorf("version 1 entry: got %+v (%v)", entry, err)
+	}
+	resp.LeafHash, resp.CertificateFingerprint = make([]byte, 32), make([]byte, 32)
+	if _, err := v1.GetAndVerifyEntryAndProof(ctx, req, dt.VersionV2, v1Root); err == nil {
+		t.Errorf("version 1 entry: verified as a version 2 leaf")
+	}
+}
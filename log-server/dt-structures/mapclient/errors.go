@@ -0,0 +1,119 @@
+package mapclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// An APIError is an error response of the map server.
+//
+// Callers can check its code with errors.Is and the Err* values, e.g.
+// errors.Is(err, ErrNotFound) tells that the requested domain or entry is not in the map.
+type APIError struct {
+	StatusCode int // the HTTP status code, or 0 for the gRPC API
+	Code       ds.ErrorCode
+	Message    string
+	RetryAfter time.Duration // how long to wait before retrying, from the Retry-After header (if any)
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("map server error (%s): %s", e.Code, e.Message)
+}
+
+// Is reports whether target is an *APIError with the same code.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code == e.Code
+}
+
+var (
+	ErrInvalidArgument   = &APIError{Code: ds.CodeInvalidArgument, Message: "invalid argument"}
+	ErrNotFound          = &APIError{Code: ds.CodeNotFound, Message: "not found"}
+	ErrOutOfRange        = &APIError{Code: ds.CodeOutOfRange, Message: "out of range"}
+	ErrUnavailable       = &APIError{Code: ds.CodeUnavailable, Message: "unavailable"}
+	ErrResourceExhausted = &APIError{Code: ds.CodeResourceExhausted, Message: "rate limit exceeded"}
+	ErrInternal          = &APIError{Code: ds.CodeInternal, Message: "internal error"}
+)
+
+// apiErrorFromHTTP returns the APIError for an HTTP error response with the specified body.
+// If the body isn't a ds.ErrorResponse (e.g. it was sent by a proxy), the code is derived
+// from the status code.
+func apiErrorFromHTTP(res *http.Response, body []byte) *APIError {
+	var resp ds.ErrorResponse
+	retryAfter := parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Code != "" {
+		return &APIError{StatusCode: res.StatusCode, Code: resp.Code, Message: resp.Message, RetryAfter: retryAfter}
+	}
+
+	e := &APIError{StatusCode: res.StatusCode, Message: res.Status, RetryAfter: retryAfter}
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		e.Message += ": " + msg
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		e.Code = ds.CodeNotFound
+	case res.StatusCode == http.StatusTooManyRequests:
+		e.Code = ds.CodeResourceExhausted
+	case res.StatusCode == http.StatusBadGateway, res.StatusCode == http.StatusServiceUnavailable,
+		res.StatusCode == http.StatusGatewayTimeout:
+		e.Code = ds.CodeUnavailable
+	case res.StatusCode >= 400 && res.StatusCode < 500:
+		e.Code = ds.CodeInvalidArgument
+	default:
+		e.Code = ds.CodeInternal
+	}
+	return e
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of
+// seconds or an HTTP date. It returns 0 if the value is empty or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// apiErrorFromGRPC converts a gRPC status error into an APIError.
+// Errors that don't come from the server (e.g. connection errors) are returned as is.
+func apiErrorFromGRPC(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	e := &APIError{Message: s.Message()}
+	switch s.Code() {
+	case codes.InvalidArgument:
+		e.Code = ds.CodeInvalidArgument
+	case codes.NotFound:
+		e.Code = ds.CodeNotFound
+	case codes.OutOfRange:
+		e.Code = ds.CodeOutOfRange
+	case codes.Unavailable:
+		e.Code = ds.CodeUnavailable
+	case codes.ResourceExhausted:
+		e.Code = ds.CodeResourceExhausted
+	case codes.Internal:
+		e.Code = ds.CodeInternal
+	default:
+		return err
+	}
+	return e
+}
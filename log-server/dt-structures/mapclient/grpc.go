@@ -0,0 +1,452 @@
+package mapclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server/dtpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcTransport executes calls through the gRPC API (see ds.NewGRPCServer),
+// with the stubs generated from dt.proto.
+type grpcTransport struct {
+	client dtpb.DomainTransparencyClient
+}
+
+// NewGRPC creates a new MapClient that uses the gRPC API through the specified connection.
+func NewGRPC(conn *grpc.ClientConn, publicKey *ecdsa.PublicKey) *MapClient {
+	return NewWithTransport(conn.Target(), &grpcTransport{dtpb.NewDomainTransparencyClient(conn)}, publicKey)
+}
+
+// A grpcCall calls the gRPC method of a command: it converts `params`, a pointer to
+// the ds request type of the command, into the protobuf request, and decodes the
+// protobuf response into `output`, a pointer to the ds response type of the command.
+type grpcCall func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error
+
+var grpcCalls = map[string]grpcCall{
+	"dt/v1/get-smh": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetSMHRequest)
+		resp, err := c.GetSMH(ctx, &dtpb.GetSMHRequest{MapSize: req.MapSize, Cosignatures: req.Cosignatures}, opts...)
+		if err != nil {
+			return err
+		}
+		return smhResponseFromProto(resp, output.(*ds.GetSMHResponse))
+	},
+	"dt/v1/get-smh-range": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetSMHRangeRequest)
+		resp, err := c.GetSMHRange(ctx, &dtpb.GetSMHRangeRequest{Start: req.Start, End: req.End}, opts...)
+		if err != nil {
+			return err
+		}
+		out := output.(*ds.GetSMHRangeResponse)
+		out.SMHs = make([]dt.SignedMapHead, len(resp.Smhs))
+		for i, smh := range resp.Smhs {
+			if out.SMHs[i], err = smhFromProto(smh); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"dt/v1/get-smh-at": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetSMHAtRequest)
+		resp, err := c.GetSMHAt(ctx, &dtpb.GetSMHAtRequest{Timestamp: req.Timestamp}, opts...)
+		if err != nil {
+			return err
+		}
+		return smhResponseFromProto(resp, output.(*ds.GetSMHResponse))
+	},
+	"dt/v1/get-domain-root-and-proof": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetDomainRootAndProofRequest)
+		resp, err := c.GetDomainRootAndProof(ctx, &dtpb.GetDomainRootAndProofRequest{DomainName: req.DomainName, DomainMapSize: req.DomainMapSize}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetDomainRootAndProofResponse) = ds.GetDomainRootAndProofResponse{
+			DomainTreeSize:        resp.DomainTreeSize,
+			DomainTreeRootHash:    resp.DomainTreeRootHash,
+			NormalizedDomainName:  resp.NormalizedDomainName,
+			AuditPath:             resp.AuditPath,
+			NonMembershipLeafData: resp.NonMembershipLeafData,
+		}
+		return nil
+	},
+	"dt/v1/get-domain-roots-and-multiproof": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetDomainRootsAndMultiProofRequest)
+		resp, err := c.GetDomainRootsAndMultiProof(ctx, &dtpb.GetDomainRootsAndMultiProofRequest{DomainName: req.DomainNames, DomainMapSize: req.DomainMapSize}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetDomainRootsAndMultiProofResponse) = multiProofFromProto(resp)
+		return nil
+	},
+	"dt/v1/get-changed-domains": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetChangedDomainsRequest)
+		resp, err := c.GetChangedDomains(ctx, &dtpb.GetChangedDomainsRequest{First: req.First, Second: req.Second, Start: req.Start}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetChangedDomainsResponse) = ds.GetChangedDomainsResponse{
+			Total:  resp.Total,
+			First:  multiProofFromProto(resp.First),
+			Second: multiProofFromProto(resp.Second),
+		}
+		return nil
+	},
+	"dt/v1/get-domain-updates": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetDomainUpdatesRequest)
+		resp, err := c.GetDomainUpdates(ctx, &dtpb.GetDomainUpdatesRequest{DomainName: req.DomainName, From: req.From, MapSize: req.MapSize, Start: req.Start}, opts...)
+		if err != nil {
+			return err
+		}
+		out := output.(*ds.GetDomainUpdatesResponse)
+		if err := smhResponseFromProto(resp.Smh, &out.SMH); err != nil {
+			return err
+		}
+		out.DomainRoot = multiProofFromProto(resp.DomainRoot)
+		out.ConsistencyProof = resp.ConsistencyProof
+		out.Entries = make([]ds.DomainUpdateEntry, len(resp.Entries))
+		for i, e := range resp.Entries {
+			out.Entries[i] = ds.DomainUpdateEntry{
+				Index: e.GetIndex(),
+				GetEntryAndProofResponse: ds.GetEntryAndProofResponse{
+					Entry:                  entryFromProto(e.GetEntry()),
+					LeafHash:               e.GetLeafHash(),
+					CertificateFingerprint: e.GetCertificateFingerprint(),
+					AuditPath:              e.GetAuditPath(),
+				},
+				LeafInput:   e.GetLeafInput(),
+				ExtraData:   e.GetExtraData(),
+				CTAuditPath: e.GetCtAuditPath(),
+			}
+		}
+		out.SourceLogs = make([]ds.SourceLogInBundle, len(resp.SourceLogs))
+		for i, l := range resp.SourceLogs {
+			out.SourceLogs[i] = ds.SourceLogInBundle{
+				LogIndex:                     l.GetLogIndex(),
+				GetSourceLogAndProofResponse: ds.GetSourceLogAndProofResponse{LogID: l.GetLogId(), AuditPath: l.GetAuditPath()},
+			}
+		}
+		return nil
+	},
+	"dt/v1/get-certificate-placements": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetCertificatePlacementsRequest)
+		resp, err := c.GetCertificatePlacements(ctx, &dtpb.GetCertificatePlacementsRequest{
+			LogIndex:         req.LogIndex,
+			CertificateIndex: req.CertificateIndex,
+			MapSize:          req.MapSize,
+			Start:            req.Start,
+		}, opts...)
+		if err != nil {
+			return err
+		}
+		out := output.(*ds.GetCertificatePlacementsResponse)
+		out.Total = resp.Total
+		out.Placements = make([]ds.GetCertificatePlacementsResponsePlacement, len(resp.Placements))
+		for i, p := range resp.Placements {
+			out.Placements[i] = ds.GetCertificatePlacementsResponsePlacement{
+				NormalizedDomainName: p.GetNormalizedDomainName(),
+				Index:                p.GetIndex(),
+				GetEntryAndProofResponse: ds.GetEntryAndProofResponse{
+					Entry:                  entryFromProto(p.GetEntry()),
+					LeafHash:               p.GetLeafHash(),
+					CertificateFingerprint: p.GetCertificateFingerprint(),
+					AuditPath:              p.GetAuditPath(),
+				},
+			}
+		}
+		out.DomainRoots = multiProofFromProto(resp.DomainRoots)
+		return nil
+	},
+	"dt/v1/get-consistency-proof": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetConsistencyProofRequest)
+		resp, err := c.GetConsistencyProof(ctx, &dtpb.GetConsistencyProofRequest{DomainName: req.DomainName, First: req.First, Second: req.Second}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetConsistencyProofResponse) = ds.GetConsistencyProofResponse{Proof: resp.Proof}
+		return nil
+	},
+	"dt/v1/get-entries": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetEntriesRequest)
+		resp, err := c.GetEntries(ctx, &dtpb.GetEntriesRequest{DomainName: req.DomainName, Start: req.Start, End: req.End}, opts...)
+		if err != nil {
+			return err
+		}
+		out := output.(*ds.GetEntriesResponse)
+		out.Entries = make([][2]uint64, len(resp.Entries))
+		for i, e := range resp.Entries {
+			out.Entries[i] = entryFromProto(e)
+		}
+		out.LeafHashes = resp.LeafHashes
+		out.CertificateFingerprints = resp.CertificateFingerprints
+		return nil
+	},
+	"dt/v1/get-certificates": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetCertificatesRequest)
+		resp, err := c.GetCertificates(ctx, &dtpb.GetCertificatesRequest{DomainName: req.DomainName, Start: req.Start, End: req.End}, opts...)
+		if err != nil {
+			return err
+		}
+		out := output.(*ds.GetCertificatesResponse)
+		out.MapSize = resp.MapSize
+		out.Certificates = make([]ds.CertificateEntry, len(resp.Certificates))
+		for i, cert := range resp.Certificates {
+			out.Certificates[i] = ds.CertificateEntry{
+				Entry:       entryFromProto(cert.GetEntry()),
+				LeafInput:   cert.GetLeafInput(),
+				ExtraData:   cert.GetExtraData(),
+				CTAuditPath: cert.GetCtAuditPath(),
+			}
+		}
+		return nil
+	},
+	"dt/v1/get-entry-and-proof": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetEntryAndProofRequest)
+		resp, err := c.GetEntryAndProof(ctx, &dtpb.GetEntryAndProofRequest{DomainName: req.DomainName, Index: req.Index, DomainTreeSize: req.DomainTreeSize}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetEntryAndProofResponse) = ds.GetEntryAndProofResponse{
+			Entry:                  entryFromProto(resp.Entry),
+			LeafHash:               resp.LeafHash,
+			CertificateFingerprint: resp.CertificateFingerprint,
+			AuditPath:              resp.AuditPath,
+		}
+		return nil
+	},
+	"dt/v1/get-domain-tree-index": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetDomainTreeIndexRequest)
+		resp, err := c.GetDomainTreeIndex(ctx, &dtpb.GetDomainTreeIndexRequest{DomainName: req.DomainName, LogIndex: req.LogIndex, CertificateIndex: req.CertificateIndex}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetDomainTreeIndexResponse) = ds.GetDomainTreeIndexResponse{DomainTreeIndex: resp.DomainTreeIndex}
+		return nil
+	},
+	"dt/v1/get-source-logs": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetSourceLogsRequest)
+		resp, err := c.GetSourceLogs(ctx, &dtpb.GetSourceLogsRequest{Start: req.Start, End: req.End}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetSourceLogsResponse) = ds.GetSourceLogsResponse{LogIDs: resp.LogIds}
+		return nil
+	},
+	"dt/v1/get-source-log-and-proof": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetSourceLogAndProofRequest)
+		resp, err := c.GetSourceLogAndProof(ctx, &dtpb.GetSourceLogAndProofRequest{Index: req.Index, SourceTreeSize: req.SourceTreeSize}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetSourceLogAndProofResponse) = ds.GetSourceLogAndProofResponse{LogID: resp.LogId, AuditPath: resp.AuditPath}
+		return nil
+	},
+	"dt/v1/get-source-consistency-proof": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetSourceConsistencyProofRequest)
+		resp, err := c.GetSourceConsistencyProof(ctx, &dtpb.GetSourceConsistencyProofRequest{First: req.First, Second: req.Second}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetSourceConsistencyProofResponse) = ds.GetSourceConsistencyProofResponse{Proof: resp.Proof}
+		return nil
+	},
+	"dt/v1/get-inclusion-promise": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.GetInclusionPromiseRequest)
+		resp, err := c.GetInclusionPromise(ctx, &dtpb.GetInclusionPromiseRequest{DomainName: req.DomainName, LogIndex: req.LogIndex, CertificateIndex: req.CertificateIndex}, opts...)
+		if err != nil {
+			return err
+		}
+		*output.(*ds.GetInclusionPromiseResponse) = ds.GetInclusionPromiseResponse{
+			Timestamp:            resp.Timestamp,
+			LogIndex:             resp.LogIndex,
+			CertificateIndex:     resp.CertificateIndex,
+			NormalizedDomainName: resp.NormalizedDomainName,
+			PromiseSignature:     resp.PromiseSignature,
+		}
+		return nil
+	},
+	"dt/v1/add-cosignature": func(ctx context.Context, c dtpb.DomainTransparencyClient, output, params interface{}, opts ...grpc.CallOption) error {
+		req := params.(*ds.AddCosignatureRequest)
+		_, err := c.AddCosignature(ctx, &dtpb.AddCosignatureRequest{
+			MapSize:        req.MapSize,
+			WitnessKeyHash: req.WitnessKeyHash,
+			Timestamp:      req.Timestamp,
+			Signature:      req.Signature,
+		}, opts...)
+		return err
+	},
+}
+
+func (gt *grpcTransport) invoke(ctx context.Context, command string, output interface{}, params interface{}) error {
+	call, ok := grpcCalls[command]
+	if !ok {
+		return fmt.Errorf("%s is not supported by the gRPC API", command)
+	}
+	var header metadata.MD
+	if err := call(ctx, gt.client, output, params, grpc.Header(&header)); err != nil {
+		return callError(err, header)
+	}
+	return nil
+}
+
+// callError converts the error of a unary call into an APIError (see apiErrorFromGRPC),
+// with the wait requested by the "retry-after" header of the response, if any.
+func callError(err error, header metadata.MD) error {
+	err = apiErrorFromGRPC(err)
+	var apiErr *APIError
+	if values := header.Get("retry-after"); len(values) > 0 && errors.As(err, &apiErr) {
+		apiErr.RetryAfter = parseRetryAfter(values[0], time.Now())
+	}
+	return err
+}
+
+func (gt *grpcTransport) Get(ctx context.Context, command string, output interface{}, params interface{}) error {
+	return gt.invoke(ctx, command, output, params)
+}
+
+// GetRaw returns the checkpoint itself for `dt/v1/checkpoint`, the only command with a raw response.
+func (gt *grpcTransport) GetRaw(ctx context.Context, command string, params interface{}) ([]byte, error) {
+	if command != "dt/v1/checkpoint" {
+		return nil, fmt.Errorf("%s has no raw response in the gRPC API", command)
+	}
+	var header metadata.MD
+	resp, err := gt.client.GetCheckpoint(ctx, &dtpb.GetCheckpointRequest{}, grpc.Header(&header))
+	if err != nil {
+		return nil, callError(err, header)
+	}
+	return resp.Checkpoint, nil
+}
+
+func (gt *grpcTransport) Post(ctx context.Context, command string, output interface{}, input interface{}) error {
+	return gt.invoke(ctx, command, output, input)
+}
+
+func (gt *grpcTransport) Subscribe(ctx context.Context, command string, params interface{}, fn func(event string, data []byte) error) error {
+	return fmt.Errorf("%s is not supported by the gRPC API", command)
+}
+
+// StreamSMHRange calls the StreamSMHRange gRPC method and verifies the
+// signature of each SMH before passing it to `fn`, in increasing order of map size.
+// Unlike GetAndVerifySMHRange, all SMHs in the range are returned.
+// It is only available for clients created with NewGRPC.
+func (mc *MapClient) StreamSMHRange(ctx context.Context, start, end uint64, fn func(*dt.SignedMapHead) error) error {
+	gt, ok := mc.t.(*grpcTransport)
+	if !ok {
+		return fmt.Errorf("StreamSMHRange requires a gRPC client")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := gt.client.StreamSMHRange(ctx, &dtpb.GetSMHRangeRequest{Start: start, End: end})
+	if err != nil {
+		return apiErrorFromGRPC(err)
+	}
+
+	var last *dt.SignedMapHead
+	for {
+		pb, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return apiErrorFromGRPC(err)
+		}
+		smh, err := smhFromProto(pb)
+		if err != nil {
+			return err
+		}
+		if smh.MapSize < start || smh.MapSize > end {
+			return fmt.Errorf("got SMH with map size %d, outside of the requested range [%d,%d]", smh.MapSize, start, end)
+		}
+		if last != nil && smh.MapSize <= last.MapSize {
+			return fmt.Errorf("SMHs are not in increasing order of map size: %d after %d", smh.MapSize, last.MapSize)
+		}
+		if err := mc.verifySMH(&smh); err != nil {
+			return fmt.Errorf("error verifying SMH with map size %d: %w", smh.MapSize, err)
+		}
+		if err := fn(&smh); err != nil {
+			return err
+		}
+		last = &smh
+	}
+}
+
+// hashFromProto converts a hash of a protobuf message, which must have the length of a SHA-256 hash.
+func hashFromProto(name string, b []byte) (ct.SHA256Hash, error) {
+	var hash ct.SHA256Hash
+	if len(b) != len(hash) {
+		return hash, fmt.Errorf("invalid %s: length=%d, expected %d", name, len(b), len(hash))
+	}
+	copy(hash[:], b)
+	return hash, nil
+}
+
+func smhFromProto(pb *dtpb.SignedMapHead) (smh dt.SignedMapHead, err error) {
+	if pb == nil {
+		return smh, fmt.Errorf("missing SMH")
+	}
+	if pb.Version > 255 {
+		return smh, fmt.Errorf("invalid SMH version %d", pb.Version)
+	}
+	smh.Version = ct.Version(pb.Version)
+	smh.Timestamp = pb.Timestamp
+	smh.MapSize = pb.MapSize
+	if smh.MapRootHash, err = hashFromProto("map root hash", pb.MapRootHash); err != nil {
+		return smh, err
+	}
+	if smh.SourceTreeRootHash, err = hashFromProto("source tree root hash", pb.SourceTreeRootHash); err != nil {
+		return smh, err
+	}
+	smh.SourceLogRevisions = make([]dt.LogRevision, len(pb.SourceLogRevisions))
+	for i, rev := range pb.SourceLogRevisions {
+		smh.SourceLogRevisions[i].TreeSize = rev.GetTreeSize()
+		if smh.SourceLogRevisions[i].RootHash, err = hashFromProto("source log root hash", rev.GetRootHash()); err != nil {
+			return smh, err
+		}
+	}
+	smh.MapHeadSignature = pb.MapHeadSignature
+	return smh, nil
+}
+
+func smhResponseFromProto(pb *dtpb.GetSMHResponse, out *ds.GetSMHResponse) (err error) {
+	if out.SignedMapHead, err = smhFromProto(pb.GetSmh()); err != nil {
+		return err
+	}
+	out.Cosignatures = nil
+	for _, c := range pb.GetCosignatures() {
+		cosig := dt.MapHeadCosignature{Timestamp: c.GetTimestamp(), Signature: c.GetSignature()}
+		if cosig.WitnessKeyHash, err = hashFromProto("witness key hash", c.GetWitnessKeyHash()); err != nil {
+			return err
+		}
+		out.Cosignatures = append(out.Cosignatures, cosig)
+	}
+	return nil
+}
+
+func multiProofFromProto(pb *dtpb.GetDomainRootsAndMultiProofResponse) ds.GetDomainRootsAndMultiProofResponse {
+	resp := ds.GetDomainRootsAndMultiProofResponse{
+		Domains:   make([]ds.DomainRootInMultiProof, len(pb.GetDomains())),
+		SideNodes: pb.GetSideNodes(),
+	}
+	for i, d := range pb.GetDomains() {
+		resp.Domains[i] = ds.DomainRootInMultiProof{
+			DomainTreeSize:        d.GetDomainTreeSize(),
+			DomainTreeRootHash:    d.GetDomainTreeRootHash(),
+			NormalizedDomainName:  d.GetNormalizedDomainName(),
+			ProofDepth:            int(d.GetProofDepth()),
+			NonMembershipLeafData: d.GetNonMembershipLeafData(),
+		}
+	}
+	return resp
+}
+
+func entryFromProto(pb *dtpb.DomainTreeEntry) [2]uint64 {
+	return [2]uint64{pb.GetLogIndex(), pb.GetCertificateIndex()}
+}
@@ -0,0 +1,72 @@
+package mapclient
+
+import (
+	"context"
+	"fmt"
+
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// GetAndVerifySMHForSize executes `GET /dt/v1/get-smh?map_size=`
+// and verifies the SMH signature.
+// If a witness policy is set, the SMH's cosignatures are also verified.
+func (mc *MapClient) GetAndVerifySMHForSize(ctx context.Context, mapSize uint64) (*ds.GetSMHResponse, error) {
+	var resp ds.GetSMHResponse
+	err := mc.get(ctx, "dt/v1/get-smh", &resp, &ds.GetSMHRequest{MapSize: &mapSize, Cosignatures: mc.witnessPolicy != nil})
+	if err != nil {
+		return nil, err
+	}
+	if resp.MapSize != mapSize {
+		return nil, fmt.Errorf("got SMH with map size %d, requested %d", resp.MapSize, mapSize)
+	}
+	if err := mc.verifySMH(&resp.SignedMapHead); err != nil {
+		return nil, err
+	}
+	if mc.witnessPolicy != nil {
+		if err := mc.witnessPolicy.Verify(&resp); err != nil {
+			return nil, err
+		}
+	}
+	return &resp, nil
+}
+
+// GetAndVerifySMHRange executes `GET /dt/v1/get-smh-range`
+// and verifies the signatures of all returned SMHs.
+// The server may return fewer SMHs than requested.
+func (mc *MapClient) GetAndVerifySMHRange(ctx context.Context, start, end uint64) (*ds.GetSMHRangeResponse, error) {
+	var resp ds.GetSMHRangeResponse
+	err := mc.get(ctx, "dt/v1/get-smh-range", &resp, &ds.GetSMHRangeRequest{Start: start, End: end})
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.SMHs {
+		smh := &resp.SMHs[i]
+		if smh.MapSize < start || smh.MapSize > end {
+			return nil, fmt.Errorf("got SMH with map size %d, outside of the requested range [%d,%d]", smh.MapSize, start, end)
+		}
+		if i > 0 && smh.MapSize <= resp.SMHs[i-1].MapSize {
+			return nil, fmt.Errorf("SMHs are not in increasing order of map size: %d after %d", smh.MapSize, resp.SMHs[i-1].MapSize)
+		}
+		if err := mc.verifySMH(smh); err != nil {
+			return nil, fmt.Errorf("error verifying SMH with map size %d: %w", smh.MapSize, err)
+		}
+	}
+	return &resp, nil
+}
+
+// GetAndVerifySMHAt executes `GET /dt/v1/get-smh-at`
+// and verifies the SMH signature.
+func (mc *MapClient) GetAndVerifySMHAt(ctx context.Context, timestamp uint64) (*ds.GetSMHResponse, error) {
+	var resp ds.GetSMHResponse
+	err := mc.get(ctx, "dt/v1/get-smh-at", &resp, &ds.GetSMHAtRequest{Timestamp: timestamp})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Timestamp > timestamp {
+		return nil, fmt.Errorf("got SMH with timestamp %d, after the requested timestamp %d", resp.Timestamp, timestamp)
+	}
+	if err := mc.verifySMH(&resp.SignedMapHead); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
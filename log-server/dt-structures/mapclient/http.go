@@ -0,0 +1,178 @@
+package mapclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/schema"
+)
+
+var encoder = schema.NewEncoder()
+
+// maxResponseSize is the maximum size of a response body read by httpTransport.
+const maxResponseSize = 1 << 26
+
+// ErrResponseTooLarge is returned when the body of a response is larger than maxResponseSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// httpTransport executes calls through the JSON HTTP API.
+type httpTransport struct {
+	uri    string
+	client *http.Client
+}
+
+// Get encodes `params` using schema, executes the `command`, and returns the JSON-decoded `output`.
+func (ht *httpTransport) Get(ctx context.Context, command string, output interface{}, params interface{}) error {
+	data, err := ht.GetRaw(ctx, command, params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, output)
+}
+
+// GetRaw encodes `params` using schema, executes the `command`, and returns the response body.
+func (ht *httpTransport) GetRaw(ctx context.Context, command string, params interface{}) ([]byte, error) {
+	path := ht.uri + command
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	if err := encoder.Encode(params, q); err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := ht.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			fmt.Printf("Error closing request body: %v\n", err)
+		}
+	}()
+
+	data, err := readBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, apiErrorFromHTTP(res, data)
+	}
+	return data, nil
+}
+
+// Post JSON-encodes `input`, sends it to the `command`, and returns the JSON-decoded `output`.
+func (ht *httpTransport) Post(ctx context.Context, command string, output interface{}, input interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ht.uri+command, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := ht.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			fmt.Printf("Error closing request body: %v\n", err)
+		}
+	}()
+
+	data, err := readBody(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return apiErrorFromHTTP(res, data)
+	}
+	return json.Unmarshal(data, output)
+}
+
+// Subscribe encodes `params` using schema, executes the `command`, and parses
+// the response as a stream of Server-Sent Events, calling fn for each event.
+// It returns ErrSubscriptionClosed if the server closes the stream.
+func (ht *httpTransport) Subscribe(ctx context.Context, command string, params interface{}, fn func(event string, data []byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ht.uri+command, nil)
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	if err := encoder.Encode(params, q); err != nil {
+		return err
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := ht.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			fmt.Printf("Error closing request body: %v\n", err)
+		}
+	}()
+	if res.StatusCode != http.StatusOK {
+		data, err := io.ReadAll(io.LimitReader(res.Body, maxEventSize))
+		if err != nil {
+			return err
+		}
+		return apiErrorFromHTTP(res, data)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(nil, maxEventSize)
+	var event string
+	var data []byte
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event != "" || data != nil {
+				if err := fn(event, data); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// comment (keep-alive)
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data != nil {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return ErrSubscriptionClosed
+}
+
+// readBody reads a response body, failing with ErrResponseTooLarge if it is larger than maxResponseSize.
+func readBody(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxResponseSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, maxResponseSize)
+	}
+	return data, nil
+}
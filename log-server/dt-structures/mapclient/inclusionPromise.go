@@ -0,0 +1,138 @@
+package mapclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// GetAndVerifyInclusionPromise executes `GET /dt/v1/get-inclusion-promise`
+// and verifies the promise signature.
+func (mc *MapClient) GetAndVerifyInclusionPromise(ctx context.Context, req *ds.GetInclusionPromiseRequest) (*ds.GetInclusionPromiseResponse, error) {
+	var resp ds.GetInclusionPromiseResponse
+	err := mc.get(ctx, "dt/v1/get-inclusion-promise", &resp, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.LogIndex != req.LogIndex || resp.CertificateIndex != req.CertificateIndex {
+		return nil, fmt.Errorf("got promise for entry (%d,%d), requested (%d,%d)", resp.LogIndex, resp.CertificateIndex, req.LogIndex, req.CertificateIndex)
+	}
+	if err := mc.verifySignatureTLS(dt.InclusionPromiseSignatureType, promiseFromResponse(&resp), resp.PromiseSignature); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func promiseFromResponse(resp *ds.GetInclusionPromiseResponse) dt.MapInclusionPromise {
+	return dt.MapInclusionPromise{
+		Version:          dt.Version,
+		Timestamp:        resp.Timestamp,
+		LogIndex:         resp.LogIndex,
+		CertificateIndex: resp.CertificateIndex,
+		DomainName:       []byte(resp.NormalizedDomainName),
+	}
+}
+
+// CheckInclusionPromise checks a (verified) inclusion promise against the map.
+//
+// The SMH should be the latest (verified) SMH seen by the client. The promise is due once an
+// SMH newer than the promise is published: CheckInclusionPromise then fetches the first SMH
+// published after the promise (see promisedSMH), even if the client didn't see it, and checks
+// that it was published within the MMD and includes the promised entry. If the SMH is not
+// newer than the promise, it returns (false, nil). Otherwise, it returns (true, nil) if the
+// promise was fulfilled, or a non-nil error if it was broken.
+func (mc *MapClient) CheckInclusionPromise(ctx context.Context, promise *ds.GetInclusionPromiseResponse, smh *ds.GetSMHResponse, mmd time.Duration) (bool, error) {
+	if smh.Timestamp <= promise.Timestamp {
+		return false, nil
+	}
+	head, err := mc.promisedSMH(ctx, promise, smh)
+	if err != nil {
+		return false, err
+	}
+	if deadline := promise.Timestamp + uint64(mmd/time.Second); head.Timestamp > deadline {
+		return false, fmt.Errorf("the first SMH after the promise (timestamp=%d) was published after the promised deadline (%d)", head.Timestamp, deadline)
+	}
+	included, err := mc.isPromiseIncluded(ctx, promise, head)
+	if err != nil {
+		return false, err
+	}
+	if !included {
+		return false, fmt.Errorf("entry (%d,%d) is not included in the domain tree for %q in the first SMH after the promise (map size %d)",
+			promise.LogIndex, promise.CertificateIndex, promise.NormalizedDomainName, head.MapSize)
+	}
+	return true, nil
+}
+
+// promisedSMH returns the first SMH published after the promise, i.e. whose timestamp is at
+// least promise.Timestamp+1 (timestamps are in seconds, so an SMH with the same timestamp as
+// the promise may be older than it), and checks that it is consistent with the latest SMH.
+//
+// It fetches the last SMH published at or before the promise with GetAndVerifySMHAt; the
+// promised SMH is the next larger SMH, or the latest SMH if the map didn't grow since then.
+func (mc *MapClient) promisedSMH(ctx context.Context, promise *ds.GetInclusionPromiseResponse, latest *ds.GetSMHResponse) (*ds.GetSMHResponse, error) {
+	var start uint64
+	before, err := mc.GetAndVerifySMHAt(ctx, promise.Timestamp)
+	if err == nil {
+		start = before.MapSize + 1
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("error fetching the SMH at the promise's timestamp (%d): %w", promise.Timestamp, err)
+	}
+	if start > latest.MapSize {
+		// the map was republished without growing
+		return latest, nil
+	}
+	resp, err := mc.GetAndVerifySMHRange(ctx, start, latest.MapSize)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the first SMH after the promise: %w", err)
+	}
+	if len(resp.SMHs) == 0 {
+		return nil, fmt.Errorf("error fetching the first SMH after the promise: no SMHs in [%d,%d]", start, latest.MapSize)
+	}
+	head := &ds.GetSMHResponse{SignedMapHead: resp.SMHs[0]}
+	if head.Timestamp <= promise.Timestamp {
+		return nil, fmt.Errorf("SMH with map size %d (timestamp=%d) is not newer than the promise (%d), but was published after the SMH at that time",
+			head.MapSize, head.Timestamp, promise.Timestamp)
+	}
+	if err := mc.VerifySMHConsistency(ctx, head, latest); err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
+// isPromiseIncluded returns whether the entry of a promise is included in the domain tree in the SMH.
+func (mc *MapClient) isPromiseIncluded(ctx context.Context, promise *ds.GetInclusionPromiseResponse, smh *ds.GetSMHResponse) (bool, error) {
+	domainRoot, err := mc.GetAndVerifyDomainRootAndProof(ctx, promise.NormalizedDomainName, smh)
+	if err != nil {
+		return false, err
+	}
+	index, err := mc.GetDomainTreeIndex(ctx, &ds.GetDomainTreeIndexRequest{
+		DomainName:       promise.NormalizedDomainName,
+		LogIndex:         promise.LogIndex,
+		CertificateIndex: promise.CertificateIndex,
+	})
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if index.DomainTreeIndex >= domainRoot.DomainTreeSize {
+		return false, nil
+	}
+
+	entry, err := mc.GetAndVerifyEntryAndProof(ctx, &ds.GetEntryAndProofRequest{
+		DomainName:     promise.NormalizedDomainName,
+		Index:          index.DomainTreeIndex,
+		DomainTreeSize: domainRoot.DomainTreeSize,
+	}, smh.Version, domainRoot.DomainTreeRootHash)
+	if err != nil {
+		return false, err
+	}
+	if entry.LogIndex != promise.LogIndex || entry.CertificateIndex != promise.CertificateIndex {
+		return false, fmt.Errorf("got entry (%d,%d) at index %d, expected (%d,%d)", entry.LogIndex, entry.CertificateIndex, index.DomainTreeIndex, promise.LogIndex, promise.CertificateIndex)
+	}
+	return true, nil
+}
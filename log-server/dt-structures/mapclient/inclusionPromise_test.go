@@ -0,0 +1,115 @@
+package mapclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/logid"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// addCertificate adds a certificate of the domain to log 0, and waits for the SMH that includes it.
+func addCertificate(t *testing.T, dm *dt.DomainMap, c chan<- dt.WorkerTransaction, index uint64, domain string) {
+	c <- dt.WorkerTransaction{
+		LogID:                  logid.LogID{1},
+		LogRevision:            dt.LogRevision{TreeSize: index + 1},
+		NewCertificatesIndices: map[string][]uint64{domain: {index}},
+		CertificateHashes:      map[uint64]dt.CertificateHashes{index: {LeafHash: ct.SHA256Hash{byte(index), 1}}},
+	}
+	for deadline := time.Now().Add(10 * time.Second); dm.GetLatestSMH().MapSize != index+1; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for an SMH")
+		}
+	}
+}
+
+// waitAfter waits until the current timestamp (in seconds) is greater than timestamp.
+func waitAfter(timestamp uint64) {
+	for uint64(time.Now().Unix()) <= timestamp {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInclusionPromise(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	svr, _ := ds.NewServer(dm, "localhost", 0)
+	hs := httptest.NewServer(svr.Handler)
+	defer hs.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+	mc := mapclient.New(hs.URL+"/", hs.Client(), &key.PublicKey)
+
+	addCertificate(t, dm, c, 0, "a.com")
+	addCertificate(t, dm, c, 1, "b.com")
+	before, err := mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitAfter(before.Timestamp)
+
+	req := &ds.GetInclusionPromiseRequest{DomainName: "B.com", LogIndex: 0, CertificateIndex: 1}
+	promise, err := mc.GetAndVerifyInclusionPromise(ctx, req)
+	if err != nil {
+		t.Fatalf("GetAndVerifyInclusionPromise: %v", err)
+	}
+	if promise.NormalizedDomainName != "b.com" || promise.Timestamp <= before.Timestamp {
+		t.Errorf("got promise %+v", promise)
+	}
+	if _, err := mc.GetAndVerifyInclusionPromise(ctx, &ds.GetInclusionPromiseRequest{DomainName: "b.com", LogIndex: 0, CertificateIndex: 2}); err == nil {
+		t.Errorf("GetAndVerifyInclusionPromise: got a promise for an entry the map hasn't observed")
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mapclient.New(hs.URL+"/", hs.Client(), &otherKey.PublicKey).GetAndVerifyInclusionPromise(ctx, req); err == nil {
+		t.Errorf("GetAndVerifyInclusionPromise: verified a promise with the wrong key")
+	}
+
+	// The promise isn't due until an SMH newer than the promise is published
+	if ok, err := mc.CheckInclusionPromise(ctx, promise, before, time.Hour); ok || err != nil {
+		t.Errorf("CheckInclusionPromise before the promised SMH: got (%v, %v), expected (false, nil)", ok, err)
+	}
+
+	// The promised SMH is the first SMH newer than the promise, even if the client only sees a later one
+	waitAfter(promise.Timestamp)
+	addCertificate(t, dm, c, 2, "c.com")
+	promised, err := mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitAfter(promised.Timestamp)
+	addCertificate(t, dm, c, 3, "b.com")
+	latest, err := mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, smh := range []*ds.GetSMHResponse{promised, latest} {
+		if ok, err := mc.CheckInclusionPromise(ctx, promise, smh, time.Hour); !ok || err != nil {
+			t.Errorf("CheckInclusionPromise (map size %d): got (%v, %v), expected (true, nil)", smh.MapSize, ok, err)
+		}
+	}
+
+	// A promise for an entry added after the promised SMH is broken, even though the latest SMH includes it
+	broken := *promise
+	broken.CertificateIndex = 3
+	if _, err := mc.CheckInclusionPromise(ctx, &broken, latest, time.Hour); err == nil {
+		t.Errorf("CheckInclusionPromise: an entry missing from the promised SMH was accepted")
+	}
+	if _, err := mc.CheckInclusionPromise(ctx, promise, latest, 0); err == nil {
+		t.Errorf("CheckInclusionPromise: an SMH published after the MMD was accepted")
+	}
+}
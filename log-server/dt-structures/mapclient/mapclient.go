@@ -1,32 +1,65 @@
 package mapclient
 
 import (
+	"context"
 	"crypto/ecdsa"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 
-	"github.com/google/certificate-transparency-go/tls"
-	"github.com/gorilla/schema"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
 	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
 	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
 )
 
-var encoder = schema.NewEncoder()
-
 // A MapClient represents a client for a domain map.
 type MapClient struct {
 	uri       string
-	client    *http.Client
+	t         Transport
 	publicKey *ecdsa.PublicKey
+
+	retryPolicy   RetryPolicy
+	witnessPolicy *WitnessPolicy
 }
 
-// New creates a new MapClient.
+// A Transport executes the calls of the map API, e.g. over the JSON HTTP API (see New)
+// or the gRPC API (see NewGRPC). Tests and offline clients may provide their own
+// Transport (see NewWithTransport).
+// Commands are named after their HTTP paths (e.g. "dt/v1/get-smh"), and errors returned
+// by the server should be *APIError values, so that transient errors are retried.
+type Transport interface {
+	// Get executes the `command` with the specified `params` and decodes the response into `output`.
+	Get(ctx context.Context, command string, output interface{}, params interface{}) error
+	// GetRaw executes the `command` with the specified `params` and returns the raw response.
+	GetRaw(ctx context.Context, command string, params interface{}) ([]byte, error)
+	// Post sends `input` to the `command` and decodes the response into `output`.
+	Post(ctx context.Context, command string, output interface{}, input interface{}) error
+	// Subscribe executes a streaming `command`, calling fn for each received event.
+	Subscribe(ctx context.Context, command string, params interface{}, fn func(event string, data []byte) error) error
+}
+
+// New creates a new MapClient that uses the JSON HTTP API.
 func New(uri string, client *http.Client, publicKey *ecdsa.PublicKey) *MapClient {
 	uri = strings.TrimRight(uri, "/") + "/"
-	return &MapClient{uri, client, publicKey}
+	return NewWithTransport(uri, &httpTransport{uri, client}, publicKey)
+}
+
+// NewWithTransport creates a new MapClient that executes its calls with the specified Transport.
+// The uri only identifies the map (see URI).
+func NewWithTransport(uri string, t Transport, publicKey *ecdsa.PublicKey) *MapClient {
+	return &MapClient{uri: uri, t: t, publicKey: publicKey, retryPolicy: DefaultRetryPolicy}
+}
+
+// RequireWitnesses makes GetAndVerifySMH reject any SMH that is not cosigned
+// according to the specified policy. A nil policy disables the requirement.
+func (mc *MapClient) RequireWitnesses(policy *WitnessPolicy) {
+	mc.witnessPolicy = policy
+}
+
+// SetRetryPolicy sets how failed calls are retried (see RetryPolicy).
+// By default, clients use DefaultRetryPolicy.
+func (mc *MapClient) SetRetryPolicy(policy RetryPolicy) {
+	mc.retryPolicy = policy
 }
 
 // URI returns the uri of this map. This uri always has a trailing slash.
@@ -34,46 +67,36 @@ func (mc *MapClient) URI() string {
 	return mc.uri
 }
 
-// get encodes `params` using schema, executes the `command`, and returns the JSON-decoded `output`.
-func (mc *MapClient) get(command string, output interface{}, params interface{}) error {
-	path := mc.uri + command
-	req, err := http.NewRequest(http.MethodGet, path, nil)
-	if err != nil {
-		return err
-	}
-
-	q := req.URL.Query()
-	if err := encoder.Encode(params, q); err != nil {
-		return err
-	}
-	req.URL.RawQuery = q.Encode()
+// get executes the `command` with the specified `params` and decodes the response into `output`.
+// Failed calls are retried according to the retry policy.
+func (mc *MapClient) get(ctx context.Context, command string, output interface{}, params interface{}) error {
+	return mc.retry(ctx, func() error {
+		return mc.t.Get(ctx, command, output, params)
+	})
+}
 
-	res, err := mc.client.Do(req)
-	if err != nil {
+// getRaw executes the `command` with the specified `params` and returns the raw response.
+// Failed calls are retried according to the retry policy.
+func (mc *MapClient) getRaw(ctx context.Context, command string, params interface{}) ([]byte, error) {
+	var data []byte
+	err := mc.retry(ctx, func() (err error) {
+		data, err = mc.t.GetRaw(ctx, command, params)
 		return err
-	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			fmt.Printf("Error closing request body: %v\n", err)
-		}
-	}()
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("got http response " + res.Status)
-	}
+	})
+	return data, err
+}
 
-	data, err := io.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(data, output)
+// post sends `input` to the `command` and decodes the response into `output`.
+// Since posts may not be idempotent, they are never retried.
+func (mc *MapClient) post(ctx context.Context, command string, output interface{}, input interface{}) error {
+	return mc.t.Post(ctx, command, output, input)
 }
 
-// verifySignatureTLS verifies a signature after TLS-encoding the data
-func (mc *MapClient) verifySignatureTLS(data interface{}, signature []byte) error {
-	bytes, err := tls.Marshal(data)
+// verifySignatureTLS verifies a signature after TLS-encoding the data (see dt.SignedData)
+func (mc *MapClient) verifySignatureTLS(sigType dt.SignatureType, data interface{}, signature []byte) error {
+	bytes, err := dt.SignedData(sigType, data)
 	if err != nil {
-		return fmt.Errorf("signature verification error: couldn't TLS-encode the MapHead: %w", err)
+		return fmt.Errorf("signature verification error: %w", err)
 	}
 
 	if !ecdsa.VerifyASN1(mc.publicKey, util.HashBytes(bytes), signature) {
@@ -82,35 +105,51 @@ func (mc *MapClient) verifySignatureTLS(data interface{}, signature []byte) erro
 	return nil
 }
 
+// verifySMH verifies the signature of a JSON-decoded SMH.
+func (mc *MapClient) verifySMH(smh *dt.SignedMapHead) error {
+	if smh.Version == 0 {
+		// Version 1 servers do not include the version in the SMH
+		smh.Version = dt.VersionV1
+	}
+	return mc.verifySignatureTLS(dt.MapHeadSignatureType, smh.MapHead, smh.MapHeadSignature)
+}
+
 // GetAndVerifySMH executes `GET /dt/v1/get-smh`
 // and verifies the SMH signature, if a public key is available.
-func (mc *MapClient) GetAndVerifySMH() (*ds.GetSMHResponse, error) {
+// If a witness policy is set, the SMH's cosignatures are also verified.
+func (mc *MapClient) GetAndVerifySMH(ctx context.Context) (*ds.GetSMHResponse, error) {
 	var resp ds.GetSMHResponse
-	err := mc.get("dt/v1/get-smh", &resp, &ds.GetSMHRequest{})
+	err := mc.get(ctx, "dt/v1/get-smh", &resp, &ds.GetSMHRequest{Cosignatures: mc.witnessPolicy != nil})
 	if err != nil {
 		return nil, err
 	}
-	resp.Version = 1
-	if err := mc.verifySignatureTLS(resp.MapHead, resp.MapHeadSignature); err != nil {
+	if err := mc.verifySMH(&resp.SignedMapHead); err != nil {
 		return nil, err
 	}
+	if mc.witnessPolicy != nil {
+		if err := mc.witnessPolicy.Verify(&resp); err != nil {
+			return nil, err
+		}
+	}
 	return &resp, nil
 }
 
-// GetDomainRootAndProof executes `GET /dt/v1/get-domain-root-and-proof`
-func (mc *MapClient) GetDomainRootAndProof(req *ds.GetDomainRootAndProofRequest) (*ds.GetDomainRootAndProofResponse, error) {
+// GetDomainRootAndProof executes `GET /dt/v1/get-domain-root-and-proof`,
+// without verifying the response (see GetAndVerifyDomainRootAndProof).
+func (mc *MapClient) GetDomainRootAndProof(ctx context.Context, req *ds.GetDomainRootAndProofRequest) (*ds.GetDomainRootAndProofResponse, error) {
 	var resp ds.GetDomainRootAndProofResponse
-	err := mc.get("dt/v1/get-domain-root-and-proof", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-domain-root-and-proof", &resp, req)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// GetConsistencyProof executes `GET /dt/v1/get-consistency-proof`
-func (mc *MapClient) GetConsistencyProof(req *ds.GetConsistencyProofRequest) (*ds.GetConsistencyProofResponse, error) {
+// GetConsistencyProof executes `GET /dt/v1/get-consistency-proof`,
+// without verifying the response (see GetAndVerifyConsistencyProof).
+func (mc *MapClient) GetConsistencyProof(ctx context.Context, req *ds.GetConsistencyProofRequest) (*ds.GetConsistencyProofResponse, error) {
 	var resp ds.GetConsistencyProofResponse
-	err := mc.get("dt/v1/get-consistency-proof", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-consistency-proof", &resp, req)
 	if err != nil {
 		return nil, err
 	}
@@ -118,19 +157,30 @@ func (mc *MapClient) GetConsistencyProof(req *ds.GetConsistencyProofRequest) (*d
 }
 
 // GetEntries executes `GET /dt/v1/get-entries`
-func (mc *MapClient) GetEntries(req *ds.GetEntriesRequest) (*ds.GetEntriesResponse, error) {
+func (mc *MapClient) GetEntries(ctx context.Context, req *ds.GetEntriesRequest) (*ds.GetEntriesResponse, error) {
 	var resp ds.GetEntriesResponse
-	err := mc.get("dt/v1/get-entries", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-entries", &resp, req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCertificates executes `GET /dt/v1/get-certificates`
+func (mc *MapClient) GetCertificates(ctx context.Context, req *ds.GetCertificatesRequest) (*ds.GetCertificatesResponse, error) {
+	var resp ds.GetCertificatesResponse
+	err := mc.get(ctx, "dt/v1/get-certificates", &resp, req)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// GetEntryAndProof executes `GET /dt/v1/get-entry-and-proof`
-func (mc *MapClient) GetEntryAndProof(req *ds.GetEntryAndProofRequest) (*ds.GetEntryAndProofResponse, error) {
+// GetEntryAndProof executes `GET /dt/v1/get-entry-and-proof`,
+// without verifying the response (see GetAndVerifyEntryAndProof).
+func (mc *MapClient) GetEntryAndProof(ctx context.Context, req *ds.GetEntryAndProofRequest) (*ds.GetEntryAndProofResponse, error) {
 	var resp ds.GetEntryAndProofResponse
-	err := mc.get("dt/v1/get-entry-and-proof", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-entry-and-proof", &resp, req)
 	if err != nil {
 		return nil, err
 	}
@@ -138,9 +188,9 @@ func (mc *MapClient) GetEntryAndProof(req *ds.GetEntryAndProofRequest) (*ds.GetE
 }
 
 // GetDomainTreeIndex executes `GET /dt/v1/get-domain-tree-index`
-func (mc *MapClient) GetDomainTreeIndex(req *ds.GetDomainTreeIndexRequest) (*ds.GetDomainTreeIndexResponse, error) {
+func (mc *MapClient) GetDomainTreeIndex(ctx context.Context, req *ds.GetDomainTreeIndexRequest) (*ds.GetDomainTreeIndexResponse, error) {
 	var resp ds.GetDomainTreeIndexResponse
-	err := mc.get("dt/v1/get-domain-tree-index", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-domain-tree-index", &resp, req)
 	if err != nil {
 		return nil, err
 	}
@@ -148,29 +198,31 @@ func (mc *MapClient) GetDomainTreeIndex(req *ds.GetDomainTreeIndexRequest) (*ds.
 }
 
 // GetSourceLogs executes `GET /dt/v1/get-source-logs`
-func (mc *MapClient) GetSourceLogs(req *ds.GetSourceLogsRequest) (*ds.GetSourceLogsResponse, error) {
+func (mc *MapClient) GetSourceLogs(ctx context.Context, req *ds.GetSourceLogsRequest) (*ds.GetSourceLogsResponse, error) {
 	var resp ds.GetSourceLogsResponse
-	err := mc.get("dt/v1/get-source-logs", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-source-logs", &resp, req)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// GetSourceLogAndProof executes `GET /dt/v1/get-source-log-and-proof`
-func (mc *MapClient) GetSourceLogAndProof(req *ds.GetSourceLogAndProofRequest) (*ds.GetSourceLogAndProofResponse, error) {
+// GetSourceLogAndProof executes `GET /dt/v1/get-source-log-and-proof`,
+// without verifying the response (see GetAndVerifySourceLogAndProof).
+func (mc *MapClient) GetSourceLogAndProof(ctx context.Context, req *ds.GetSourceLogAndProofRequest) (*ds.GetSourceLogAndProofResponse, error) {
 	var resp ds.GetSourceLogAndProofResponse
-	err := mc.get("dt/v1/get-source-log-and-proof", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-source-log-and-proof", &resp, req)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// GetSourceConsistencyProof executes `GET /dt/v1/get-source-consistency-proof`
-func (mc *MapClient) GetSourceConsistencyProof(req *ds.GetSourceConsistencyProofRequest) (*ds.GetSourceConsistencyProofResponse, error) {
+// GetSourceConsistencyProof executes `GET /dt/v1/get-source-consistency-proof`,
+// without verifying the response (see GetAndVerifySourceConsistencyProof).
+func (mc *MapClient) GetSourceConsistencyProof(ctx context.Context, req *ds.GetSourceConsistencyProofRequest) (*ds.GetSourceConsistencyProofResponse, error) {
 	var resp ds.GetSourceConsistencyProofResponse
-	err := mc.get("dt/v1/get-source-consistency-proof", &resp, req)
+	err := mc.get(ctx, "dt/v1/get-source-consistency-proof", &resp, req)
 	if err != nil {
 		return nil, err
 	}
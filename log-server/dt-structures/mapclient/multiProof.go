@@ -0,0 +1,219 @@
+package mapclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/google/certificate-transparency-go/tls"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// GetDomainRootsAndMultiProof executes `GET /dt/v1/get-domain-roots-and-multiproof`
+func (mc *MapClient) GetDomainRootsAndMultiProof(ctx context.Context, req *ds.GetDomainRootsAndMultiProofRequest) (*ds.GetDomainRootsAndMultiProofResponse, error) {
+	var resp ds.GetDomainRootsAndMultiProofResponse
+	err := mc.get(ctx, "dt/v1/get-domain-roots-and-multiproof", &resp, req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAndVerifyDomainRootsAndMultiProof executes `GET /dt/v1/get-domain-roots-and-multiproof`
+// and verifies the returned domain tree roots against the map root of the specified SMH.
+func (mc *MapClient) GetAndVerifyDomainRootsAndMultiProof(ctx context.Context, domains []string, smh *ds.GetSMHResponse) (*ds.GetDomainRootsAndMultiProofResponse, error) {
+	resp, err := mc.GetDomainRootsAndMultiProof(ctx, &ds.GetDomainRootsAndMultiProofRequest{
+		DomainNames:   domains,
+		DomainMapSize: smh.MapSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyDomainRootsAndMultiProof(domains, resp, smh.MapRootHash[:]); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VerifyDomainRootsAndMultiProof verifies that the response contains exactly the
+// requested domains (after normalization) and that the multiproof proves
+// all of the returned domain tree roots against the specified map root.
+func VerifyDomainRootsAndMultiProof(domains []string, resp *ds.GetDomainRootsAndMultiProofResponse, mapRoot []byte) error {
+	returned := make(map[string]struct{}, len(resp.Domains))
+	for _, d := range resp.Domains {
+		if _, ok := returned[d.NormalizedDomainName]; ok {
+			return fmt.Errorf("invalid multiproof: duplicate domain %q", d.NormalizedDomainName)
+		}
+		returned[d.NormalizedDomainName] = struct{}{}
+	}
+	requested := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		normalizedDomain, err := util.NormalizeDomainName(d)
+		if err != nil {
+			return fmt.Errorf("invalid domain name %q: %w", d, err)
+		}
+		if _, ok := returned[normalizedDomain]; !ok {
+			return fmt.Errorf("invalid multiproof: missing domain %q", normalizedDomain)
+		}
+		requested[normalizedDomain] = struct{}{}
+	}
+	if len(requested) != len(returned) {
+		return fmt.Errorf("invalid multiproof: got %d domains, requested %d", len(returned), len(requested))
+	}
+
+	v := multiProofVerifier{
+		domains:   resp.Domains,
+		sideNodes: resp.SideNodes,
+		paths:     make([][]byte, len(resp.Domains)),
+	}
+	keys := make([]int, len(resp.Domains))
+	for i, d := range resp.Domains {
+		if d.ProofDepth < 0 || d.ProofDepth > 8*sha256.Size {
+			return fmt.Errorf("invalid multiproof: invalid depth %d for %q", d.ProofDepth, d.NormalizedDomainName)
+		}
+		v.paths[i] = util.HashBytes([]byte(d.NormalizedDomainName))
+		keys[i] = i
+	}
+	for _, sideNode := range resp.SideNodes {
+		if len(sideNode) != sha256.Size {
+			return fmt.Errorf("invalid multiproof: side node length=%d, expected %d", len(sideNode), sha256.Size)
+		}
+	}
+
+	root, err := v.computeRoot(0, keys)
+	if err != nil {
+		return err
+	}
+	if v.next != len(v.sideNodes) {
+		return fmt.Errorf("invalid multiproof: %d unused side nodes", len(v.sideNodes)-v.next)
+	}
+	if !bytes.Equal(root, mapRoot) {
+		return fmt.Errorf("invalid multiproof: calculated root %x != map root %x", root, mapRoot)
+	}
+	return nil
+}
+
+// multiProofVerifier recomputes the map root from a multiproof,
+// consuming the side nodes in the same order as dt.DomainMap.GetMultiProofForDomains.
+type multiProofVerifier struct {
+	domains   []ds.DomainRootInMultiProof
+	sideNodes [][]byte
+	paths     [][]byte
+	next      int
+}
+
+func (v *multiProofVerifier) computeRoot(depth int, keys []int) ([]byte, error) {
+	terminals := 0
+	for _, k := range keys {
+		if v.domains[k].ProofDepth == depth {
+			terminals++
+		}
+	}
+	if terminals == len(keys) {
+		return v.terminalHash(keys)
+	} else if terminals > 0 {
+		return nil, fmt.Errorf("invalid multiproof: domains with different paths end at the same node (depth %d)", depth)
+	}
+
+	var leftKeys, rightKeys []int
+	for _, k := range keys {
+		if dt.SMTPathBit(v.paths[k], depth) {
+			rightKeys = append(rightKeys, k)
+		} else {
+			leftKeys = append(leftKeys, k)
+		}
+	}
+
+	if len(leftKeys) > 0 && len(rightKeys) > 0 {
+		left, err := v.computeRoot(depth+1, leftKeys)
+		if err != nil {
+			return nil, err
+		}
+		right, err := v.computeRoot(depth+1, rightKeys)
+		if err != nil {
+			return nil, err
+		}
+		return smtNodeHash(left, right), nil
+	}
+
+	if v.next >= len(v.sideNodes) {
+		return nil, fmt.Errorf("invalid multiproof: not enough side nodes")
+	}
+	sideNode := v.sideNodes[v.next]
+	v.next++
+	if len(rightKeys) == 0 {
+		left, err := v.computeRoot(depth+1, leftKeys)
+		if err != nil {
+			return nil, err
+		}
+		return smtNodeHash(left, sideNode), nil
+	}
+	right, err := v.computeRoot(depth+1, rightKeys)
+	if err != nil {
+		return nil, err
+	}
+	return smtNodeHash(sideNode, right), nil
+}
+
+// terminalHash returns the hash of the node at which the paths of all specified domains end.
+func (v *multiProofVerifier) terminalHash(keys []int) ([]byte, error) {
+	var hash []byte
+	for _, k := range keys {
+		h, err := smtLeafHashForDomain(v.paths[k], &v.domains[k])
+		if err != nil {
+			return nil, err
+		}
+		if hash != nil && !bytes.Equal(hash, h) {
+			return nil, fmt.Errorf("invalid multiproof: conflicting leaves for %q", v.domains[k].NormalizedDomainName)
+		}
+		hash = h
+	}
+	return hash, nil
+}
+
+// smtLeafHashForDomain returns the hash of the leaf at which the path of the domain ends:
+// the domain's own leaf if the domain tree is not empty,
+// or either a placeholder or an unrelated leaf otherwise.
+func smtLeafHashForDomain(path []byte, d *ds.DomainRootInMultiProof) ([]byte, error) {
+	if d.DomainTreeSize == 0 {
+		if d.NonMembershipLeafData == nil {
+			return make([]byte, sha256.Size), nil
+		}
+		data := d.NonMembershipLeafData
+		if len(data) != 1+2*sha256.Size || data[0] != dt.SMTLeafPrefix {
+			return nil, fmt.Errorf("invalid non-membership leaf data for %q", d.NormalizedDomainName)
+		}
+		if bytes.Equal(data[1:1+sha256.Size], path) {
+			return nil, fmt.Errorf("invalid non-membership proof for %q: the leaf belongs to the domain", d.NormalizedDomainName)
+		}
+		return util.HashBytes(data), nil
+	}
+
+	if d.NonMembershipLeafData != nil {
+		return nil, fmt.Errorf("invalid multiproof: non-membership leaf data for non-empty domain %q", d.NormalizedDomainName)
+	}
+	var root dt.DomainTreeRoot
+	root.DomainTreeSize = d.DomainTreeSize
+	if len(d.DomainTreeRootHash) != len(root.DomainTreeRootHash) {
+		return nil, fmt.Errorf("invalid domain tree root hash for %q: length=%d", d.NormalizedDomainName, len(d.DomainTreeRootHash))
+	}
+	copy(root.DomainTreeRootHash[:], d.DomainTreeRootHash)
+	value, err := tls.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	return smtLeafHash(path, value), nil
+}
+
+// smtLeafHash returns the hash of a sparse merkle tree leaf with the specified path and value.
+func smtLeafHash(path, value []byte) []byte {
+	return util.HashBytes([]byte{dt.SMTLeafPrefix}, path, util.HashBytes(value))
+}
+
+// smtNodeHash returns the hash of a sparse merkle tree inner node with the specified children.
+func smtNodeHash(left, right []byte) []byte {
+	return util.HashBytes([]byte{dt.SMTNodePrefix}, left, right)
+}
@@ -0,0 +1,163 @@
+package mapclient_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+func copyMultiProof(resp *ds.GetDomainRootsAndMultiProofResponse) *ds.GetDomainRootsAndMultiProofResponse {
+	cp := &ds.GetDomainRootsAndMultiProofResponse{
+		Domains:   make([]ds.DomainRootInMultiProof, len(resp.Domains)),
+		SideNodes: make([][]byte, len(resp.SideNodes)),
+	}
+	for i, d := range resp.Domains {
+		cp.Domains[i] = d
+		cp.Domains[i].DomainTreeRootHash = append([]byte(nil), d.DomainTreeRootHash...)
+		if d.NonMembershipLeafData != nil {
+			cp.Domains[i].NonMembershipLeafData = append([]byte(nil), d.NonMembershipLeafData...)
+		}
+	}
+	for i, n := range resp.SideNodes {
+		cp.SideNodes[i] = append([]byte(nil), n...)
+	}
+	return cp
+}
+
+func TestVerifyDomainRootsAndMultiProof(t *testing.T) {
+	domains := map[string]uint64{"a.com": 1, "b.com": 2, "c.com": 3, "d.com": 4}
+	for i := 0; i < 60; i++ {
+		domains[fmt.Sprintf("d%d.com", i)] = uint64(i%4 + 1)
+	}
+	mc, smh := newTestMap(t, domains)
+	ctx := context.Background()
+
+	requested := []string{"a.com", "C.com", "d17.com", "d42.com"}
+	for i := 0; i < 20; i++ {
+		requested = append(requested, fmt.Sprintf("missing-%d.com", i))
+	}
+	resp, err := mc.GetAndVerifyDomainRootsAndMultiProof(ctx, requested, smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Find the indices of present and missing domains in the response
+	index := make(map[string]int)
+	emptyNode, otherLeaf := -1, -1
+	for i, d := range resp.Domains {
+		index[d.NormalizedDomainName] = i
+		if size, ok := domains[d.NormalizedDomainName]; ok && d.DomainTreeSize != size {
+			t.Errorf("%s: got domain tree size %d, expected %d", d.NormalizedDomainName, d.DomainTreeSize, size)
+		} else if !ok && d.DomainTreeSize != 0 {
+			t.Errorf("%s: got domain tree size %d, expected 0", d.NormalizedDomainName, d.DomainTreeSize)
+		} else if !ok && d.NonMembershipLeafData == nil {
+			emptyNode = i
+		} else if !ok {
+			otherLeaf = i
+		}
+	}
+	if emptyNode < 0 || otherLeaf < 0 {
+		t.Fatalf("no non-membership proofs of both kinds")
+	}
+	if len(resp.SideNodes) < 2 {
+		t.Fatalf("got %d side nodes", len(resp.SideNodes))
+	}
+	a, c := index["a.com"], index["c.com"]
+	if bytes.Equal(resp.SideNodes[0], resp.SideNodes[1]) || resp.Domains[a].ProofDepth == resp.Domains[emptyNode].ProofDepth {
+		t.Fatalf("swapping the side nodes or the proof depths would not change the response")
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(*ds.GetDomainRootsAndMultiProofResponse)
+	}{
+		{"tampered side node", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.SideNodes[0][0] ^= 1 }},
+		{"tampered last side node", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.SideNodes[len(r.SideNodes)-1][0] ^= 1 }},
+		{"swapped side nodes", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.SideNodes[0], r.SideNodes[1] = r.SideNodes[1], r.SideNodes[0]
+		}},
+		{"missing side node", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.SideNodes = r.SideNodes[1:] }},
+		{"missing last side node", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.SideNodes = r.SideNodes[:len(r.SideNodes)-1] }},
+		{"extra side node", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.SideNodes = append(r.SideNodes, make([]byte, 32)) }},
+		{"extra first side node", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.SideNodes = append([][]byte{make([]byte, 32)}, r.SideNodes...)
+		}},
+		{"short side node", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.SideNodes[0] = r.SideNodes[0][:31] }},
+		{"left out domain", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains = append(r.Domains[:c], r.Domains[c+1:]...)
+		}},
+		{"left out missing domain", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains = append(r.Domains[:emptyNode], r.Domains[emptyNode+1:]...)
+		}},
+		{"duplicate domain", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains = append(r.Domains, r.Domains[a]) }},
+		{"unrequested domain", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains = append(r.Domains, ds.DomainRootInMultiProof{NormalizedDomainName: "b.com"})
+		}},
+		{"renamed domain", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].NormalizedDomainName = "b.com" }},
+		{"smaller tree", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].DomainTreeSize-- }},
+		{"other root hash", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].DomainTreeRootHash[0] ^= 1 }},
+		{"swapped roots", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains[a].DomainTreeRootHash, r.Domains[c].DomainTreeRootHash = r.Domains[c].DomainTreeRootHash, r.Domains[a].DomainTreeRootHash
+			r.Domains[a].DomainTreeSize, r.Domains[c].DomainTreeSize = r.Domains[c].DomainTreeSize, r.Domains[a].DomainTreeSize
+		}},
+		{"hidden domain", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].DomainTreeSize = 0 }},
+		{"leaf data for an existing domain", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains[c].NonMembershipLeafData = r.Domains[otherLeaf].NonMembershipLeafData
+		}},
+		{"hidden domain with leaf data", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains[c].DomainTreeSize = 0
+			r.Domains[c].NonMembershipLeafData = r.Domains[otherLeaf].NonMembershipLeafData
+		}},
+		{"own leaf as other leaf", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains[c].DomainTreeSize = 0
+			r.Domains[c].NonMembershipLeafData = append([]byte{dt.SMTLeafPrefix}, make([]byte, 64)...)
+			copy(r.Domains[c].NonMembershipLeafData[1:], util.HashBytes([]byte("c.com")))
+		}},
+		{"fake domain in empty node", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains[emptyNode].DomainTreeSize = 1
+			r.Domains[emptyNode].DomainTreeRootHash = make([]byte, 32)
+		}},
+		{"tampered leaf data", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[otherLeaf].NonMembershipLeafData[40] ^= 1 }},
+		{"dropped leaf data", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[otherLeaf].NonMembershipLeafData = nil }},
+		{"deeper proof", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].ProofDepth++ }},
+		{"shallower proof", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].ProofDepth-- }},
+		{"shallower missing domain proof", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[emptyNode].ProofDepth-- }},
+		{"negative depth", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].ProofDepth = -1 }},
+		{"depth beyond the tree", func(r *ds.GetDomainRootsAndMultiProofResponse) { r.Domains[c].ProofDepth = 257 }},
+		{"swapped depths", func(r *ds.GetDomainRootsAndMultiProofResponse) {
+			r.Domains[a].ProofDepth, r.Domains[emptyNode].ProofDepth = r.Domains[emptyNode].ProofDepth, r.Domains[a].ProofDepth
+		}},
+	}
+	root := smh.MapRootHash[:]
+	for _, tt := range tests {
+		tampered := copyMultiProof(resp)
+		tt.tamper(tampered)
+		if err := mapclient.VerifyDomainRootsAndMultiProof(requested, tampered, root); err == nil {
+			t.Errorf("%s: tampered response verified", tt.name)
+		}
+	}
+
+	// The order of the domains doesn't matter
+	reordered := copyMultiProof(resp)
+	for i, j := 0, len(reordered.Domains)-1; i < j; i, j = i+1, j-1 {
+		reordered.Domains[i], reordered.Domains[j] = reordered.Domains[j], reordered.Domains[i]
+	}
+	if err := mapclient.VerifyDomainRootsAndMultiProof(requested, reordered, root); err != nil {
+		t.Errorf("reordered domains: %v", err)
+	}
+
+	otherRoot := append([]byte(nil), root...)
+	otherRoot[0] ^= 1
+	if err := mapclient.VerifyDomainRootsAndMultiProof(requested, resp, otherRoot); err == nil {
+		t.Errorf("multiproof verified against another map root")
+	}
+	if err := mapclient.VerifyDomainRootsAndMultiProof(requested[1:], resp, root); err == nil {
+		t.Errorf("multiproof verified for fewer domains than returned")
+	}
+}
@@ -0,0 +1,100 @@
+package mapclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// ForEachEntry calls fn for each entry of the domain tree in [start, end], in order,
+// executing as many `GET /dt/v1/get-entries` requests as needed, since the server
+// may truncate each response. end must be less than the size of the domain tree.
+//
+// The entries are not verified; LeafHash and CertificateFingerprint are only set if
+// the server returned them (i.e. for version 2 maps).
+func (mc *MapClient) ForEachEntry(ctx context.Context, domain string, start, end uint64, fn func(index uint64, entry dt.DomainTreeEntry) error) error {
+	if start > end {
+		return fmt.Errorf("invalid range: [%d,%d]", start, end)
+	}
+	for index := start; ; {
+		resp, err := mc.GetEntries(ctx, &ds.GetEntriesRequest{DomainName: domain, Start: index, End: end})
+		if err != nil {
+			return err
+		}
+		n := uint64(len(resp.Entries))
+		if n == 0 {
+			return fmt.Errorf("invalid get-entries response: no entries starting at %d", index)
+		}
+		if n > end-index+1 {
+			return fmt.Errorf("invalid get-entries response: got %d entries, requested %d", n, end-index+1)
+		}
+		for i := range resp.Entries {
+			entry, err := entryFromEntries(resp, i)
+			if err != nil {
+				return err
+			}
+			if err := fn(index, entry); err != nil {
+				return err
+			}
+			index++
+		}
+		if index > end {
+			return nil
+		}
+	}
+}
+
+// entryFromEntries returns the i-th entry in a `get-entries` response.
+func entryFromEntries(resp *ds.GetEntriesResponse, i int) (dt.DomainTreeEntry, error) {
+	entry := dt.DomainTreeEntry{
+		LogIndex:         resp.Entries[i][0],
+		CertificateIndex: resp.Entries[i][1],
+	}
+	if i < len(resp.LeafHashes) {
+		if len(resp.LeafHashes[i]) != sha256.Size {
+			return dt.DomainTreeEntry{}, fmt.Errorf("invalid entry leaf hash: length=%d, expected %d", len(resp.LeafHashes[i]), sha256.Size)
+		}
+		copy(entry.LeafHash[:], resp.LeafHashes[i])
+	}
+	if i < len(resp.CertificateFingerprints) {
+		if len(resp.CertificateFingerprints[i]) != sha256.Size {
+			return dt.DomainTreeEntry{}, fmt.Errorf("invalid entry certificate fingerprint: length=%d, expected %d", len(resp.CertificateFingerprints[i]), sha256.Size)
+		}
+		copy(entry.CertificateFingerprint[:], resp.CertificateFingerprints[i])
+	}
+	return entry, nil
+}
+
+// ForEachSourceLog calls fn for each log ID of the source tree in [start, end], in order,
+// executing as many `GET /dt/v1/get-source-logs` requests as needed, since the server
+// may truncate each response. end must be less than the size of the source tree.
+func (mc *MapClient) ForEachSourceLog(ctx context.Context, start, end uint64, fn func(index uint64, logID []byte) error) error {
+	if start > end {
+		return fmt.Errorf("invalid range: [%d,%d]", start, end)
+	}
+	for index := start; ; {
+		resp, err := mc.GetSourceLogs(ctx, &ds.GetSourceLogsRequest{Start: index, End: end})
+		if err != nil {
+			return err
+		}
+		n := uint64(len(resp.LogIDs))
+		if n == 0 {
+			return fmt.Errorf("invalid get-source-logs response: no log IDs starting at %d", index)
+		}
+		if n > end-index+1 {
+			return fmt.Errorf("invalid get-source-logs response: got %d log IDs, requested %d", n, end-index+1)
+		}
+		for _, logID := range resp.LogIDs {
+			if err := fn(index, logID); err != nil {
+				return err
+			}
+			index++
+		}
+		if index > end {
+			return nil
+		}
+	}
+}
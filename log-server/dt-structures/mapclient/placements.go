@@ -0,0 +1,113 @@
+package mapclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/certificate-transparency-go/x509"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// GetCertificatePlacements executes `GET /dt/v1/get-certificate-placements`
+func (mc *MapClient) GetCertificatePlacements(ctx context.Context, req *ds.GetCertificatePlacementsRequest) (*ds.GetCertificatePlacementsResponse, error) {
+	var resp ds.GetCertificatePlacementsResponse
+	err := mc.get(ctx, "dt/v1/get-certificate-placements", &resp, req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAndVerifyCertificatePlacements executes `GET /dt/v1/get-certificate-placements`
+// for the map size of the specified SMH and verifies the response with VerifyCertificatePlacements.
+func (mc *MapClient) GetAndVerifyCertificatePlacements(ctx context.Context, logIndex, certIndex uint64, smh *ds.GetSMHResponse, start uint64) (*ds.GetCertificatePlacementsResponse, error) {
+	resp, err := mc.GetCertificatePlacements(ctx, &ds.GetCertificatePlacementsRequest{
+		LogIndex:         logIndex,
+		CertificateIndex: certIndex,
+		MapSize:          smh.MapSize,
+		Start:            start,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyCertificatePlacements(logIndex, certIndex, smh, start, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VerifyCertificatePlacements verifies that every placement in the response refers to the
+// certificate with the specified index of a source log, that the placements are sorted,
+// and that each of them is included in its domain tree, whose root is proven against
+// the map root of the specified SMH.
+//
+// Since the server only lists the placements it knows of, this proves that the listed
+// placements exist, but not that there are no others.
+func VerifyCertificatePlacements(logIndex, certIndex uint64, smh *ds.GetSMHResponse, start uint64, resp *ds.GetCertificatePlacementsResponse) error {
+	if start+uint64(len(resp.Placements)) > resp.Total {
+		return fmt.Errorf("invalid placements: got %d placements starting at %d, but total is %d", len(resp.Placements), start, resp.Total)
+	}
+	if len(resp.Placements) == 0 && start < resp.Total {
+		return fmt.Errorf("invalid placements: got no placements starting at %d, but total is %d", start, resp.Total)
+	}
+
+	var domains []string
+	for i, p := range resp.Placements {
+		if i > 0 {
+			prev := &resp.Placements[i-1]
+			if p.NormalizedDomainName < prev.NormalizedDomainName ||
+				(p.NormalizedDomainName == prev.NormalizedDomainName && p.Index <= prev.Index) {
+				return fmt.Errorf("invalid placements: placement %d is out of order", i)
+			}
+		}
+		if len(domains) == 0 || domains[len(domains)-1] != p.NormalizedDomainName {
+			domains = append(domains, p.NormalizedDomainName)
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+	if err := VerifyDomainRootsAndMultiProof(domains, &resp.DomainRoots, smh.MapRootHash[:]); err != nil {
+		return fmt.Errorf("error verifying domain tree roots: %w", err)
+	}
+	roots := make(map[string]*ds.DomainRootInMultiProof, len(resp.DomainRoots.Domains))
+	for i := range resp.DomainRoots.Domains {
+		d := &resp.DomainRoots.Domains[i]
+		roots[d.NormalizedDomainName] = d
+	}
+
+	for i := range resp.Placements {
+		p := &resp.Placements[i]
+		root := roots[p.NormalizedDomainName]
+		if p.Index >= root.DomainTreeSize {
+			return fmt.Errorf("invalid placements: entry %d is outside of the domain tree for %q (size %d)", p.Index, p.NormalizedDomainName, root.DomainTreeSize)
+		}
+		entry, err := verifyEntryAndProof(&p.GetEntryAndProofResponse, p.Index, root.DomainTreeSize, smh.Version, root.DomainTreeRootHash)
+		if err != nil {
+			return fmt.Errorf("error verifying entry %d of %q: %w", p.Index, p.NormalizedDomainName, err)
+		}
+		if entry.LogIndex != logIndex || entry.CertificateIndex != certIndex {
+			return fmt.Errorf("invalid placements: entry %d of %q is (%d, %d), expected (%d, %d)",
+				p.Index, p.NormalizedDomainName, entry.LogIndex, entry.CertificateIndex, logIndex, certIndex)
+		}
+	}
+	return nil
+}
+
+// UnplacedDomains returns the domain names of the certificate (as indexed by the map,
+// see ds.CertificateDomainNames) that have no placement in the specified list.
+// The list must contain all placements of the certificate, from all pages of
+// `get-certificate-placements`.
+func UnplacedDomains(cert *x509.Certificate, placements []ds.GetCertificatePlacementsResponsePlacement) []string {
+	placed := make(map[string]struct{}, len(placements))
+	for _, p := range placements {
+		placed[p.NormalizedDomainName] = struct{}{}
+	}
+	var unplaced []string
+	for _, d := range ds.CertificateDomainNames(cert) {
+		if _, ok := placed[d]; !ok {
+			unplaced = append(unplaced, d)
+		}
+	}
+	return unplaced
+}
@@ -0,0 +1,96 @@
+package mapclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// A RetryPolicy tells how a MapClient retries the calls that fail with a transient error,
+// i.e. a network error or an ErrUnavailable or ErrResourceExhausted response.
+// Only idempotent calls are retried: AddCosignature and Subscribe are never retried.
+//
+// Before each retry, the client waits for a random duration between 0 and the current
+// backoff, which starts at InitialBackoff and doubles after each attempt, up to MaxBackoff.
+// If the server asked the client to wait longer (see APIError.RetryAfter), the client waits
+// for that long instead, up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int // the maximum number of attempts of each call; 0 or 1 disables retries
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy of new clients.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+// retry calls fn until it succeeds, it fails with an error that isn't transient,
+// the retry policy's attempts are exhausted or ctx is done.
+// It returns the error of the last attempt, wrapped with ctx.Err() if ctx is done.
+func (mc *MapClient) retry(ctx context.Context, fn func() error) error {
+	backoff := mc.retryPolicy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= mc.retryPolicy.MaxAttempts || !isTransient(err) {
+			return err
+		}
+
+		var wait time.Duration
+		if backoff > 0 {
+			wait = time.Duration(rand.Int63n(int64(backoff) + 1))
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+			if wait > mc.retryPolicy.MaxBackoff {
+				wait = mc.retryPolicy.MaxBackoff
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &retryCanceledError{ctxErr: ctx.Err(), last: err}
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > mc.retryPolicy.MaxBackoff {
+			backoff = mc.retryPolicy.MaxBackoff
+		}
+	}
+}
+
+// retryCanceledError is returned by retry when ctx is done before a retry.
+// It matches both the context's error and the error of the last attempt (with errors.Is and errors.As).
+type retryCanceledError struct {
+	ctxErr, last error
+}
+
+func (e *retryCanceledError) Error() string {
+	return fmt.Sprintf("%v (last error: %v)", e.ctxErr, e.last)
+}
+
+func (e *retryCanceledError) Unwrap() error { return e.ctxErr }
+
+func (e *retryCanceledError) Is(target error) bool { return errors.Is(e.last, target) }
+
+func (e *retryCanceledError) As(target interface{}) bool { return errors.As(e.last, target) }
+
+// isTransient tells whether a call that failed with err may succeed if retried.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return errors.Is(apiErr, ErrUnavailable) || errors.Is(apiErr, ErrResourceExhausted)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
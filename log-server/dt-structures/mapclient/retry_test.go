@@ -0,0 +1,114 @@
+package mapclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// failingTransport fails the first `failures` calls with `err`, and then returns empty responses.
+type failingTransport struct {
+	err      error
+	failures int
+	calls    int
+}
+
+func (ft *failingTransport) call() error {
+	ft.calls++
+	if ft.calls <= ft.failures {
+		return ft.err
+	}
+	return nil
+}
+
+func (ft *failingTransport) Get(ctx context.Context, command string, output interface{}, params interface{}) error {
+	return ft.call()
+}
+
+func (ft *failingTransport) GetRaw(ctx context.Context, command string, params interface{}) ([]byte, error) {
+	return nil, ft.call()
+}
+
+func (ft *failingTransport) Post(ctx context.Context, command string, output interface{}, input interface{}) error {
+	return ft.call()
+}
+
+func (ft *failingTransport) Subscribe(ctx context.Context, command string, params interface{}, fn func(event string, data []byte) error) error {
+	return ft.call()
+}
+
+func TestRetry(t *testing.T) {
+	policy := mapclient.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	tests := []struct {
+		name      string
+		err       error
+		failures  int
+		wantErr   error
+		wantCalls int
+	}{
+		{"transient", mapclient.ErrUnavailable, 2, nil, 3},
+		{"rate limited", mapclient.ErrResourceExhausted, 1, nil, 2},
+		{"attempts exhausted", mapclient.ErrUnavailable, 5, mapclient.ErrUnavailable, 3},
+		{"not transient", mapclient.ErrNotFound, 5, mapclient.ErrNotFound, 1},
+	}
+	for _, tt := range tests {
+		ft := &failingTransport{err: tt.err, failures: tt.failures}
+		mc := mapclient.NewWithTransport("test/", ft, nil)
+		mc.SetRetryPolicy(policy)
+		_, err := mc.GetEntries(context.Background(), &ds.GetEntriesRequest{DomainName: "a.com"})
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("%s: got error %v, expected %v", tt.name, err, tt.wantErr)
+		}
+		if ft.calls != tt.wantCalls {
+			t.Errorf("%s: got %d calls, expected %d", tt.name, ft.calls, tt.wantCalls)
+		}
+	}
+
+	// posts are not retried
+	ft := &failingTransport{err: mapclient.ErrUnavailable, failures: 1}
+	mc := mapclient.NewWithTransport("test/", ft, nil)
+	mc.SetRetryPolicy(policy)
+	if err := mc.AddCosignature(context.Background(), &ds.AddCosignatureRequest{}); !errors.Is(err, mapclient.ErrUnavailable) || ft.calls != 1 {
+		t.Errorf("AddCosignature: got error %v after %d calls, expected a single failed call", err, ft.calls)
+	}
+
+	// retries stop when the context is done
+	ft = &failingTransport{err: mapclient.ErrUnavailable, failures: 5}
+	mc = mapclient.NewWithTransport("test/", ft, nil)
+	mc.SetRetryPolicy(mapclient.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := mc.GetEntries(ctx, &ds.GetEntriesRequest{DomainName: "a.com"})
+	if !errors.Is(err, mapclient.ErrUnavailable) || !errors.Is(err, context.DeadlineExceeded) || ft.calls != 1 {
+		t.Errorf("cancelled: got error %v after %d calls, expected a single failed call", err, ft.calls)
+	}
+
+	// the client waits as long as the server asks, up to MaxBackoff
+	var calls int
+	var last time.Time
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			last = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if waited := time.Since(last); waited < 200*time.Millisecond {
+			t.Errorf("Retry-After: retried after %s, expected at least MaxBackoff", waited)
+		}
+		w.Write([]byte(`{"entries":[]}`))
+	}))
+	defer hs.Close()
+	mc = mapclient.New(hs.URL+"/", hs.Client(), nil)
+	mc.SetRetryPolicy(mapclient.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 200 * time.Millisecond})
+	if _, err := mc.GetEntries(context.Background(), &ds.GetEntriesRequest{DomainName: "a.com"}); err != nil || calls != 2 {
+		t.Errorf("Retry-After: got error %v after %d calls, expected a successful retry", err, calls)
+	}
+}
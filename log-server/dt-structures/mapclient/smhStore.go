@@ -0,0 +1,245 @@
+package mapclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// ErrRollback is returned by SMHStore.Add for an SMH with a smaller map size than the latest
+// SMH in the store, i.e., if the server shows an older version of the map.
+var ErrRollback = errors.New("map rollback")
+
+// ErrStaleSMH is returned by SMHStore.Add for an older copy of the latest SMH in the store,
+// i.e., an SMH with the same map size and an older timestamp (e.g. a response from a cache).
+var ErrStaleSMH = errors.New("stale SMH")
+
+// ForkEvidence is a pair of validly signed SMHs that cannot both be heads of the same
+// append-only map, e.g. two different map roots for the same map size. It can be exported
+// as JSON and checked by anyone with the map's public key (see Verify).
+type ForkEvidence struct {
+	First  dt.SignedMapHead `json:"first"`
+	Second dt.SignedMapHead `json:"second"`
+	Reason string           `json:"reason"`
+}
+
+func (e *ForkEvidence) Error() string {
+	return fmt.Sprintf("map fork detected: %s", e.Reason)
+}
+
+// Verify checks that both SMHs are signed with the specified public key and that they conflict.
+func (e *ForkEvidence) Verify(publicKey *ecdsa.PublicKey) error {
+	if err := dt.VerifySMH(publicKey, &e.First); err != nil {
+		return fmt.Errorf("first SMH: %w", err)
+	}
+	if err := dt.VerifySMH(publicKey, &e.Second); err != nil {
+		return fmt.Errorf("second SMH: %w", err)
+	}
+	if smhConflict(&e.First.MapHead, &e.Second.MapHead) == "" {
+		return fmt.Errorf("the SMHs do not conflict")
+	}
+	return nil
+}
+
+// smhConflict returns why two map heads cannot belong to the same map, or "" if they can.
+// Only conflicts that can be checked without contacting the map are considered.
+func smhConflict(a, b *dt.MapHead) string {
+	if a.MapSize > b.MapSize {
+		a, b = b, a
+	}
+	if a.MapSize == b.MapSize {
+		if a.MapRootHash != b.MapRootHash {
+			return fmt.Sprintf("two different map roots for map size %d", a.MapSize)
+		}
+	} else if b.Timestamp < a.Timestamp {
+		return fmt.Sprintf("the map grew from size %d to %d, but its timestamp decreased from %d to %d", a.MapSize, b.MapSize, a.Timestamp, b.Timestamp)
+	}
+	if len(b.SourceLogRevisions) < len(a.SourceLogRevisions) {
+		return fmt.Sprintf("the source log count decreased from %d to %d", len(a.SourceLogRevisions), len(b.SourceLogRevisions))
+	}
+	if len(a.SourceLogRevisions) == len(b.SourceLogRevisions) && a.SourceTreeRootHash != b.SourceTreeRootHash {
+		return fmt.Sprintf("two different source tree roots for source tree size %d", len(a.SourceLogRevisions))
+	}
+	for i, rev := range a.SourceLogRevisions {
+		newRev := b.SourceLogRevisions[i]
+		if newRev.TreeSize < rev.TreeSize {
+			return fmt.Sprintf("the map grew, but source log %d shrank from %d to %d", i, rev.TreeSize, newRev.TreeSize)
+		} else if newRev.TreeSize == rev.TreeSize && newRev.RootHash != rev.RootHash {
+			return fmt.Sprintf("two different roots for source log %d with size %d", i, rev.TreeSize)
+		}
+	}
+	return ""
+}
+
+// An SMHStore records the verified SMHs seen by a client in a file, and checks that each new
+// SMH is consistent with them: the map size and the timestamp never decrease, and the source
+// tree only grows. Conflicting SMHs are kept as ForkEvidence.
+//
+// Only the latest head of each map size is kept. Since the recorded heads are consistent with
+// each other, a new head only needs to be checked against the heads with the same map size and
+// the closest smaller and larger map sizes.
+type SMHStore struct {
+	mc   *MapClient
+	path string
+
+	m     sync.Mutex
+	state smhStoreState
+}
+
+// smhStoreState is the content of the SMHStore file.
+type smhStoreState struct {
+	Heads    []*ds.GetSMHResponse `json:"heads"` // sorted by map size, one per map size
+	Evidence []*ForkEvidence      `json:"evidence,omitempty"`
+}
+
+// OpenSMHStore loads the SMHStore in the specified file, if it exists, and uses the map client
+// to verify the signatures and the source tree consistency of new SMHs.
+// If path is empty, the SMHs are only kept in memory.
+func OpenSMHStore(mc *MapClient, path string) (*SMHStore, error) {
+	s := &SMHStore{mc: mc, path: path}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading SMH store %q: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, fmt.Errorf("error parsing SMH store %q: %w", path, err)
+	}
+	return s, nil
+}
+
+// save writes the store to its file.
+func (s *SMHStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(&s.state)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing SMH store %q: %w", tmp, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// search returns the index of the first head with a map size of at least mapSize.
+func (s *SMHStore) search(mapSize uint64) int {
+	return sort.Search(len(s.state.Heads), func(i int) bool { return s.state.Heads[i].MapSize >= mapSize })
+}
+
+// insert records a head, replacing the head with the same map size if it is older.
+// It returns whether the head was recorded.
+func (s *SMHStore) insert(smh *ds.GetSMHResponse) bool {
+	i := s.search(smh.MapSize)
+	if i < len(s.state.Heads) && s.state.Heads[i].MapSize == smh.MapSize {
+		if smh.Timestamp <= s.state.Heads[i].Timestamp {
+			return false
+		}
+		s.state.Heads[i] = smh
+		return true
+	}
+	s.state.Heads = append(s.state.Heads, nil)
+	copy(s.state.Heads[i+1:], s.state.Heads[i:])
+	s.state.Heads[i] = smh
+	return true
+}
+
+// Latest returns the SMH with the largest map size (and, among those, the latest timestamp)
+// in the store, or nil if the store is empty.
+func (s *SMHStore) Latest() *ds.GetSMHResponse {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.latest()
+}
+
+func (s *SMHStore) latest() *ds.GetSMHResponse {
+	if len(s.state.Heads) == 0 {
+		return nil
+	}
+	return s.state.Heads[len(s.state.Heads)-1]
+}
+
+// Evidence returns the evidence of the forks detected by the store.
+func (s *SMHStore) Evidence() []*ForkEvidence {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]*ForkEvidence(nil), s.state.Evidence...)
+}
+
+// addEvidence records the evidence, unless the same pair of SMHs was already recorded.
+func (s *SMHStore) addEvidence(evidence *ForkEvidence) error {
+	first, second := string(evidence.First.MapHeadSignature), string(evidence.Second.MapHeadSignature)
+	for _, e := range s.state.Evidence {
+		a, b := string(e.First.MapHeadSignature), string(e.Second.MapHeadSignature)
+		if (a == first && b == second) || (a == second && b == first) {
+			return nil
+		}
+	}
+	s.state.Evidence = append(s.state.Evidence, evidence)
+	return s.save()
+}
+
+// Add verifies an SMH returned by the map as its latest SMH, and records it.
+//
+// Returns a *ForkEvidence error if the SMH conflicts with an SMH in the store, an error wrapping
+// ErrRollback if its map size is smaller than the one of the latest SMH in the store, an error
+// wrapping ErrStaleSMH if it has the same map size and an older timestamp, or another error if
+// its signature is invalid or its source tree is not consistent with the latest SMH.
+// Newer heads with the same map size as the latest SMH are accepted, as long as they have the
+// same contents (i.e., the map republished its head).
+func (s *SMHStore) Add(ctx context.Context, smh *ds.GetSMHResponse) error {
+	if err := s.mc.verifySMH(&smh.SignedMapHead); err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	i := s.search(smh.MapSize)
+	for j := i - 1; j <= i+1; j++ {
+		if j < 0 || j >= len(s.state.Heads) {
+			continue
+		}
+		h := s.state.Heads[j]
+		if reason := smhConflict(&h.MapHead, &smh.MapHead); reason != "" {
+			evidence := &ForkEvidence{First: h.SignedMapHead, Second: smh.SignedMapHead, Reason: reason}
+			if err := s.addEvidence(evidence); err != nil {
+				return fmt.Errorf("%w (%v)", evidence, err)
+			}
+			return evidence
+		}
+	}
+
+	latest := s.latest()
+	if latest != nil {
+		if smh.MapSize < latest.MapSize {
+			return fmt.Errorf("%w: got map size %d, after %d", ErrRollback, smh.MapSize, latest.MapSize)
+		}
+		if string(smh.MapHeadSignature) == string(latest.MapHeadSignature) {
+			return nil
+		}
+		if smh.MapSize == latest.MapSize && smh.Timestamp < latest.Timestamp {
+			return fmt.Errorf("%w: got timestamp %d for map size %d, after %d", ErrStaleSMH, smh.Timestamp, smh.MapSize, latest.Timestamp)
+		}
+		if err := s.mc.VerifySMHConsistency(ctx, latest, smh); err != nil {
+			return err
+		}
+	}
+	if !s.insert(smh) {
+		return nil
+	}
+	return s.save()
+}
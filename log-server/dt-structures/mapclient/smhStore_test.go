@@ -0,0 +1,128 @@
+package mapclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// signedSMH returns an SMH with a single source log, signed with the specified key.
+func signedSMH(t *testing.T, key *ecdsa.PrivateKey, timestamp, mapSize uint64, root byte) *ds.GetSMHResponse {
+	head := dt.MapHead{
+		Version:            dt.VersionV1,
+		Timestamp:          timestamp,
+		MapSize:            mapSize,
+		MapRootHash:        ct.SHA256Hash{root},
+		SourceTreeRootHash: ct.SHA256Hash{1},
+		SourceLogRevisions: []dt.LogRevision{{TreeSize: mapSize, RootHash: ct.SHA256Hash{byte(mapSize)}}},
+	}
+	data, err := dt.SignedData(dt.MapHeadSignatureType, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := ecdsa.SignASN1(rand.Reader, key, util.HashBytes(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ds.GetSMHResponse{SignedMapHead: dt.SignedMapHead{MapHead: head, MapHeadSignature: sig}}
+}
+
+func TestSMHStore(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the store doesn't contact the map unless the map grows
+	mc := mapclient.New("http://127.0.0.1:0/", http.DefaultClient, &key.PublicKey)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "smhs.json")
+	store, err := mapclient.OpenSMHStore(mc, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	smh := signedSMH(t, key, 2, 10, 1)
+	if err := store.Add(ctx, smh); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := store.Add(ctx, signedSMH(t, key, 3, 10, 1)); err != nil {
+		t.Fatalf("Add (republished SMH): %v", err)
+	}
+	if err := store.Add(ctx, smh); !errors.Is(err, mapclient.ErrStaleSMH) {
+		t.Errorf("Add (older copy of the latest SMH): expected ErrStaleSMH, got %v", err)
+	}
+	if latest := store.Latest(); latest.Timestamp != 3 {
+		t.Errorf("Latest after an older copy: got timestamp %d, expected 3", latest.Timestamp)
+	}
+
+	if err := store.Add(ctx, signedSMH(t, key, 1, 5, 1)); !errors.Is(err, mapclient.ErrRollback) {
+		t.Errorf("Add (smaller map): expected ErrRollback, got %v", err)
+	}
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(ctx, signedSMH(t, otherKey, 4, 10, 2)); err == nil {
+		t.Errorf("Add (invalid signature): expected an error")
+	}
+
+	var evidence *mapclient.ForkEvidence
+	forked := signedSMH(t, key, 4, 10, 2)
+	if err := store.Add(ctx, forked); !errors.As(err, &evidence) {
+		t.Fatalf("Add (different root): expected ForkEvidence, got %v", err)
+	}
+	// the same fork is only recorded once
+	if err := store.Add(ctx, forked); !errors.As(err, new(*mapclient.ForkEvidence)) {
+		t.Fatalf("Add (same fork): expected ForkEvidence, got %v", err)
+	}
+	if err := evidence.Verify(&key.PublicKey); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := evidence.Verify(&otherKey.PublicKey); err == nil {
+		t.Errorf("Verify (wrong key): expected an error")
+	}
+
+	// the evidence is exportable and the store survives restarts
+	data, err := json.Marshal(evidence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported mapclient.ForkEvidence
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if err := exported.Verify(&key.PublicKey); err != nil {
+		t.Errorf("Verify (exported): %v", err)
+	}
+	exported.Second = exported.First
+	if err := exported.Verify(&key.PublicKey); err == nil {
+		t.Errorf("Verify (no conflict): expected an error")
+	}
+
+	store, err = mapclient.OpenSMHStore(mc, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest := store.Latest(); latest == nil || latest.MapSize != 10 || latest.Timestamp != 3 {
+		t.Errorf("Latest after reopening: got %+v", latest)
+	}
+	if n := len(store.Evidence()); n != 1 {
+		t.Errorf("Evidence after reopening: got %d, expected 1", n)
+	}
+	if err := store.Add(ctx, signedSMH(t, key, 1, 5, 1)); !errors.Is(err, mapclient.ErrRollback) {
+		t.Errorf("Add (smaller map) after reopening: expected ErrRollback, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package mapclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+// maxEventSize is the maximum size of a line received from `GET /dt/v1/subscribe`.
+const maxEventSize = 1 << 24
+
+// ErrSubscriptionClosed is returned by Subscribe when the server closes the subscription,
+// e.g. because the client fell behind. The client may subscribe again.
+var ErrSubscriptionClosed = errors.New("subscription closed by the server")
+
+// A SubscriptionEvent is an event received from `GET /dt/v1/subscribe`.
+// Exactly one of SMH and Domain is set.
+type SubscriptionEvent struct {
+	SMH    *ds.GetSMHResponse
+	Domain *ds.DomainUpdateEvent
+}
+
+// Subscribe executes `GET /dt/v1/subscribe` for the specified domains (which may be empty),
+// and calls fn for each received event, until ctx is cancelled, fn returns an error,
+// or the server closes the subscription.
+//
+// The signature of each SMH is verified, but not its cosignatures, since it has just
+// been published. The domain events are not verified: they only tell the client
+// which domain trees to fetch (e.g. with GetAndVerifyDomainRootsAndMultiProof).
+func (mc *MapClient) Subscribe(ctx context.Context, domains []string, fn func(*SubscriptionEvent) error) error {
+	return mc.t.Subscribe(ctx, "dt/v1/subscribe", &ds.SubscribeRequest{DomainNames: domains}, func(event string, data []byte) error {
+		switch event {
+		case "smh":
+			var smh ds.GetSMHResponse
+			if err := json.Unmarshal(data, &smh); err != nil {
+				return err
+			}
+			if err := mc.verifySMH(&smh.SignedMapHead); err != nil {
+				return err
+			}
+			return fn(&SubscriptionEvent{SMH: &smh})
+		case "domain":
+			var update ds.DomainUpdateEvent
+			if err := json.Unmarshal(data, &update); err != nil {
+				return err
+			}
+			return fn(&SubscriptionEvent{Domain: &update})
+		default:
+			return fmt.Errorf("unknown subscription event %q", event)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package mapclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// A WitnessPolicy requires SMHs to be cosigned by at least
+// RequiredCosignatures of the listed witnesses.
+type WitnessPolicy struct {
+	RequiredCosignatures int
+
+	witnesses map[ct.SHA256Hash]*ecdsa.PublicKey
+}
+
+// NewWitnessPolicy creates a policy requiring k of the specified witnesses (k of n).
+func NewWitnessPolicy(k int, witnesses []*ecdsa.PublicKey) (*WitnessPolicy, error) {
+	if k <= 0 || k > len(witnesses) {
+		return nil, fmt.Errorf("invalid witness policy: cannot require %d of %d witnesses", k, len(witnesses))
+	}
+	p := &WitnessPolicy{
+		RequiredCosignatures: k,
+		witnesses:            make(map[ct.SHA256Hash]*ecdsa.PublicKey),
+	}
+	for _, w := range witnesses {
+		keyHash, err := dt.WitnessKeyHash(w)
+		if err != nil {
+			return nil, err
+		}
+		p.witnesses[keyHash] = w
+	}
+	if len(p.witnesses) < k {
+		return nil, fmt.Errorf("invalid witness policy: cannot require %d of %d distinct witnesses", k, len(p.witnesses))
+	}
+	return p, nil
+}
+
+// Verify checks that the SMH has valid cosignatures from at least RequiredCosignatures
+// distinct known witnesses. Cosignatures from unknown witnesses, invalid cosignatures and
+// additional cosignatures from the same witness are ignored.
+func (p *WitnessPolicy) Verify(smh *ds.GetSMHResponse) error {
+	valid := make(map[ct.SHA256Hash]struct{})
+	var invalid int
+	for _, cosig := range smh.Cosignatures {
+		publicKey, ok := p.witnesses[cosig.WitnessKeyHash]
+		if !ok {
+			continue
+		}
+		if _, ok := valid[cosig.WitnessKeyHash]; ok {
+			continue
+		}
+		if err := dt.VerifyCosignature(publicKey, smh.MapHead, cosig); err != nil {
+			invalid++
+			continue
+		}
+		valid[cosig.WitnessKeyHash] = struct{}{}
+	}
+	if len(valid) < p.RequiredCosignatures {
+		return fmt.Errorf("insufficient cosignatures: got %d valid cosignatures (and %d invalid ones) from known witnesses, need %d",
+			len(valid), invalid, p.RequiredCosignatures)
+	}
+	return nil
+}
+
+// CosignSMH produces a witness cosignature for the specified SMH.
+// The caller is responsible for checking the SMH before cosigning it.
+func CosignSMH(signer crypto.Signer, publicKey *ecdsa.PublicKey, smh *ds.GetSMHResponse) (*ds.AddCosignatureRequest, error) {
+	keyHash, err := dt.WitnessKeyHash(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := uint64(time.Now().UTC().Unix())
+	data, err := tls.Marshal(dt.CosignedMapHead{Timestamp: timestamp, MapHead: smh.MapHead})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling CosignedMapHead: %w", err)
+	}
+	sig, err := signer.Sign(rand.Reader, util.HashBytes(data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error signing CosignedMapHead: %w", err)
+	}
+	return &ds.AddCosignatureRequest{
+		MapSize:        smh.MapSize,
+		WitnessKeyHash: keyHash[:],
+		Timestamp:      timestamp,
+		Signature:      sig,
+	}, nil
+}
+
+// AddCosignature executes `POST /dt/v1/add-cosignature`
+func (mc *MapClient) AddCosignature(ctx context.Context, req *ds.AddCosignatureRequest) error {
+	var resp ds.AddCosignatureResponse
+	return mc.post(ctx, "dt/v1/add-cosignature", &resp, req)
+}
+
+// VerifySMHConsistency checks that the (verified) SMH `second` is a valid
+// successor of the (verified) SMH `first`:
+// the map size and timestamp never decrease, heads with the same map size
+// have the same contents, each source log only grows, the map size matches
+// the source logs and the source tree of `second` is consistent with the one of `first`.
+//
+// It does not check the consistency of the CT logs themselves.
+func (mc *MapClient) VerifySMHConsistency(ctx context.Context, first, second *ds.GetSMHResponse) error {
+	if second.Timestamp < first.Timestamp {
+		return fmt.Errorf("SMH timestamp went back in time: %d < %d", second.Timestamp, first.Timestamp)
+	}
+	if second.MapSize < first.MapSize {
+		return fmt.Errorf("map size decreased: %d < %d", second.MapSize, first.MapSize)
+	}
+	if len(second.SourceLogRevisions) < len(first.SourceLogRevisions) {
+		return fmt.Errorf("source log count decreased: %d < %d", len(second.SourceLogRevisions), len(first.SourceLogRevisions))
+	}
+
+	var mapSize uint64
+	for _, rev := range second.SourceLogRevisions {
+		mapSize += rev.TreeSize
+	}
+	if mapSize != second.MapSize {
+		return fmt.Errorf("map size (%d) does not match the sum of the source log sizes (%d)", second.MapSize, mapSize)
+	}
+	for i, rev := range first.SourceLogRevisions {
+		newRev := second.SourceLogRevisions[i]
+		if newRev.TreeSize < rev.TreeSize {
+			return fmt.Errorf("source log %d shrank: %d < %d", i, newRev.TreeSize, rev.TreeSize)
+		} else if newRev.TreeSize == rev.TreeSize && newRev.RootHash != rev.RootHash {
+			return fmt.Errorf("source log %d has two different roots for size %d", i, rev.TreeSize)
+		}
+	}
+
+	if second.MapSize == first.MapSize {
+		if second.MapRootHash != first.MapRootHash {
+			return fmt.Errorf("two different map roots for map size %d", first.MapSize)
+		}
+		if second.SourceTreeRootHash != first.SourceTreeRootHash || len(second.SourceLogRevisions) != len(first.SourceLogRevisions) {
+			return fmt.Errorf("two different source trees for map size %d", first.MapSize)
+		}
+		return nil
+	}
+	return mc.GetAndVerifySourceConsistencyProof(ctx, first, second)
+}
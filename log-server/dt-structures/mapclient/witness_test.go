@@ -0,0 +1,208 @@
+package mapclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+func newWitnessKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// cosign returns the cosignature of the SMH by the witness with the specified key.
+func cosign(t *testing.T, key *ecdsa.PrivateKey, smh *ds.GetSMHResponse) dt.MapHeadCosignature {
+	req, err := mapclient.CosignSMH(key, &key.PublicKey, smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cosig := dt.MapHeadCosignature{Timestamp: req.Timestamp, Signature: req.Signature}
+	copy(cosig.WitnessKeyHash[:], req.WitnessKeyHash)
+	return cosig
+}
+
+func TestNewWitnessPolicy(t *testing.T) {
+	keys := newWitnessKeys(t, 2)
+	a, b := &keys[0].PublicKey, &keys[1].PublicKey
+	tests := []struct {
+		k         int
+		witnesses []*ecdsa.PublicKey
+		valid     bool
+	}{
+		{1, []*ecdsa.PublicKey{a}, true},
+		{2, []*ecdsa.PublicKey{a, b}, true},
+		{0, []*ecdsa.PublicKey{a, b}, false},
+		{3, []*ecdsa.PublicKey{a, b}, false},
+		{2, []*ecdsa.PublicKey{a, a}, false}, // duplicate witnesses
+		{1, nil, false},
+	}
+	for _, tt := range tests {
+		if _, err := mapclient.NewWitnessPolicy(tt.k, tt.witnesses); (err == nil) != tt.valid {
+			t.Errorf("NewWitnessPolicy(%d of %d): got %v, expected valid=%v", tt.k, len(tt.witnesses), err, tt.valid)
+		}
+	}
+}
+
+func TestWitnessPolicy(t *testing.T) {
+	mapKey := newWitnessKeys(t, 1)[0]
+	keys := newWitnessKeys(t, 4)
+	smh := signedSMH(t, mapKey, 2, 10, 1)
+	other := signedSMH(t, mapKey, 3, 10, 1)
+
+	cosigs := make([]dt.MapHeadCosignature, len(keys))
+	for i, key := range keys {
+		cosigs[i] = cosign(t, key, smh)
+		if err := dt.VerifyCosignature(&key.PublicKey, smh.MapHead, cosigs[i]); err != nil {
+			t.Fatalf("VerifyCosignature: %v", err)
+		}
+		if err := dt.VerifyCosignature(&key.PublicKey, other.MapHead, cosigs[i]); err == nil {
+			t.Fatalf("VerifyCosignature: a cosignature verified for another head")
+		}
+	}
+	invalid := cosigs[1]
+	invalid.Signature = cosign(t, keys[1], other).Signature
+	unknown := cosigs[3] // keys[3] is not part of the policy
+
+	// 2 of keys[0:3]
+	policy, err := mapclient.NewWitnessPolicy(2, []*ecdsa.PublicKey{&keys[0].PublicKey, &keys[1].PublicKey, &keys[2].PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		cosigs []dt.MapHeadCosignature
+		valid  bool
+	}{
+		{"no cosignatures", nil, false},
+		{"one cosignature", []dt.MapHeadCosignature{cosigs[0]}, false},
+		{"two cosignatures", []dt.MapHeadCosignature{cosigs[0], cosigs[2]}, true},
+		{"all cosignatures", cosigs[:3], true},
+		{"duplicate cosignature", []dt.MapHeadCosignature{cosigs[0], cosigs[0]}, false},
+		{"unknown witness", []dt.MapHeadCosignature{cosigs[0], unknown}, false},
+		{"invalid cosignature", []dt.MapHeadCosignature{cosigs[0], invalid}, false},
+		{"invalid cosignature first", []dt.MapHeadCosignature{invalid, cosigs[0], cosigs[2]}, true},
+		{"invalid and valid cosignature of a witness", []dt.MapHeadCosignature{invalid, cosigs[0], cosigs[1]}, true},
+		{"unknown witness and two cosignatures", []dt.MapHeadCosignature{unknown, cosigs[1], cosigs[2]}, true},
+	}
+	for _, tt := range tests {
+		resp := *smh
+		resp.Cosignatures = tt.cosigs
+		if err := policy.Verify(&resp); (err == nil) != tt.valid {
+			t.Errorf("%s: got %v, expected valid=%v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestAddCosignature(t *testing.T) {
+	mapKey := newWitnessKeys(t, 1)[0]
+	keys := newWitnessKeys(t, 3)
+	dm := dt.NewDomainMap(mapKey)
+	for _, key := range keys[:2] {
+		if _, err := dm.AddWitness(&key.PublicKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svr, _ := ds.NewServer(dm, "localhost", 0)
+	hs := httptest.NewServer(svr.Handler)
+	defer hs.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+	addCertificate(t, dm, c, 0, "a.com")
+
+	mc := mapclient.New(hs.URL+"/", hs.Client(), &mapKey.PublicKey)
+	smh, err := mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys[:2] {
+		req, err := mapclient.CosignSMH(key, &key.PublicKey, smh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := mc.AddCosignature(ctx, req); err != nil {
+			t.Fatalf("AddCosignature: %v", err)
+		}
+		// the same cosignature is only stored once
+		if err := mc.AddCosignature(ctx, req); err != nil {
+			t.Fatalf("AddCosignature (again): %v", err)
+		}
+	}
+
+	unknown, err := mapclient.CosignSMH(keys[2], &keys[2].PublicKey, smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.AddCosignature(ctx, unknown); err == nil {
+		t.Errorf("AddCosignature: accepted a cosignature from an unknown witness")
+	}
+	invalid, err := mapclient.CosignSMH(keys[0], &keys[0].PublicKey, smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid.Timestamp++
+	if err := mc.AddCosignature(ctx, invalid); err == nil {
+		t.Errorf("AddCosignature: accepted an invalid cosignature")
+	}
+	missing, err := mapclient.CosignSMH(keys[0], &keys[0].PublicKey, smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing.MapSize++
+	if err := mc.AddCosignature(ctx, missing); err == nil {
+		t.Errorf("AddCosignature: accepted a cosignature for a missing SMH")
+	}
+
+	if _, cosigs := dm.GetSMHAndCosignatures(smh.MapSize); len(cosigs) != 2 {
+		t.Errorf("got %d cosignatures, expected 2", len(cosigs))
+	}
+
+	for _, tt := range []struct {
+		k     int
+		keys  []*ecdsa.PrivateKey
+		valid bool
+	}{
+		{2, keys[:2], true},
+		{1, keys[1:], true},
+		{3, keys, false},
+		{1, keys[2:], false},
+	} {
+		var witnesses []*ecdsa.PublicKey
+		for _, key := range tt.keys {
+			witnesses = append(witnesses, &key.PublicKey)
+		}
+		policy, err := mapclient.NewWitnessPolicy(tt.k, witnesses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mc.RequireWitnesses(policy)
+		resp, err := mc.GetAndVerifySMH(ctx)
+		if (err == nil) != tt.valid {
+			t.Errorf("GetAndVerifySMH (%d of %d witnesses): got %v, expected valid=%v", tt.k, len(tt.keys), err, tt.valid)
+		} else if err == nil && len(resp.Cosignatures) != 2 {
+			t.Errorf("GetAndVerifySMH (%d of %d witnesses): got %d cosignatures, expected 2", tt.k, len(tt.keys), len(resp.Cosignatures))
+		}
+	}
+	mc.RequireWitnesses(nil)
+	if resp, err := mc.GetAndVerifySMH(ctx); err != nil {
+		t.Errorf("GetAndVerifySMH without a policy: %v", err)
+	} else if len(resp.Cosignatures) != 0 {
+		t.Errorf("GetAndVerifySMH without a policy: got %d cosignatures, expected none", len(resp.Cosignatures))
+	}
+}
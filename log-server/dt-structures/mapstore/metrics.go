@@ -0,0 +1,18 @@
+package mapstore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics of the map stores created by Wrap.
+var (
+	storedNodes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_mapstore_nodes",
+		Help: "Number of sparse Merkle tree nodes in the map store, as of the last saved root.",
+	})
+	prunedNodes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dt_mapstore_pruned_nodes_total",
+		Help: "Number of sparse Merkle tree nodes pruned from the map store.",
+	})
+)
@@ -94,7 +94,11 @@ func (ms *wrapper) SaveNodesForRoot(root []byte) error {
 	if err := ms.markToSave(root); err != nil {
 		return err
 	}
-	return ms.pruneUntil(root)
+	if err := ms.pruneUntil(root); err != nil {
+		return err
+	}
+	storedNodes.Set(float64(ms.base.Size()))
+	return nil
 }
 
 // TraverseNodes traverses the nodes starting from the root in DFS order.
@@ -217,6 +221,11 @@ func (ms *wrapper) pruneUntil(root []byte) error {
 		return fmt.Errorf("ms.pruneUntil: %w", err)
 	}
 
+	for _, ki := range toProcess {
+		if !ki.ShouldSave {
+			prunedNodes.Inc()
+		}
+	}
 	ms.newEntries = newEntries
 	// reindex
 	for i, entry := range ms.newEntries {
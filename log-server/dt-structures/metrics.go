@@ -0,0 +1,43 @@
+package dt
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics of the domain map and its worker, exposed by the server's /metrics endpoint.
+var (
+	smhsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dt_smh_published_total",
+		Help: "Number of SMHs published, by whether the map head was republished without changes.",
+	}, []string{"republished"})
+	smhPublishLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dt_smh_publish_latency_seconds",
+		Help:    "Time between the first change to the map after an SMH and the publication of the next SMH.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+	})
+	smhMapSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_smh_map_size",
+		Help: "Map size of the latest SMH.",
+	})
+	smhTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_smh_timestamp_seconds",
+		Help: "Timestamp of the latest SMH.",
+	})
+	domainTrees = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_domain_trees",
+		Help: "Number of domain trees in the map.",
+	})
+	workerQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_worker_queue_length",
+		Help: "Number of transactions queued by the worker, waiting for earlier source logs.",
+	})
+	workerChannelLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_worker_channel_length",
+		Help: "Number of transactions waiting in the worker's channel.",
+	})
+	workerChannelCapacity = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dt_worker_channel_capacity",
+		Help: "Capacity of the worker's channel.",
+	})
+)
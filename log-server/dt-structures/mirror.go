@@ -0,0 +1,149 @@
+package dt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/google/certificate-transparency-go/logid"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// ErrReadOnly is returned by the operations of a mirror that require the map's private key,
+// such as issuing inclusion promises.
+var ErrReadOnly = errors.New("read-only mirror: the map's private key is not available")
+
+// NewMirrorDomainMap creates a read-only DomainMap that replicates the map with the specified public key.
+// A mirror is updated by a Mirror, which publishes the upstream SMHs unchanged,
+// and it serves the upstream checkpoints added with AddUpstreamCheckpoint.
+func NewMirrorDomainMap(publicKey *ecdsa.PublicKey) *DomainMap {
+	return newDomainMap(publicKey)
+}
+
+// VerifySMH verifies the signature of an SMH.
+func VerifySMH(publicKey *ecdsa.PublicKey, smh *SignedMapHead) error {
+	data, err := SignedData(MapHeadSignatureType, smh.MapHead)
+	if err != nil {
+		return err
+	}
+	if !ecdsa.VerifyASN1(publicKey, util.HashBytes(data), smh.MapHeadSignature) {
+		return fmt.Errorf("invalid SMH signature")
+	}
+	return nil
+}
+
+// A Mirror rebuilds the domain trees and the sparse merkle tree of an upstream map in a
+// mirror (see NewMirrorDomainMap), and publishes each upstream SMH once the recomputed
+// map has the same root. Like a worker, there may only be one Mirror per map, and the map
+// should not be modified otherwise.
+type Mirror struct {
+	dm      *DomainMap
+	mapRoot []byte
+}
+
+// NewMirror creates a Mirror that continues from the latest SMH of the map.
+func NewMirror(dm *DomainMap) *Mirror {
+	smh := dm.GetLatestSMH()
+	return &Mirror{dm: dm, mapRoot: smh.MapRootHash[:]}
+}
+
+// AddSourceLog adds a source log to the source tree.
+func (m *Mirror) AddSourceLog(logID logid.LogID) {
+	m.dm.GetSourceTree().AddEntry(logID)
+}
+
+// AddEntries appends entries to the domain tree of the specified (normalized) domain,
+// updates the map root and returns the new root of the domain tree.
+func (m *Mirror) AddEntries(domain string, entries []DomainTreeEntry) (*DomainTreeRoot, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no entries to add to the domain tree for %q", domain)
+	}
+	dtree, err := m.dm.getOrAddDomainTree(domain)
+	if err != nil {
+		return nil, err
+	}
+	if dtree.DomainName != domain {
+		return nil, fmt.Errorf("domain name %q is not normalized", domain)
+	}
+	var treeSize uint64
+	placements := make([]placedCertificate, 0, len(entries))
+	for _, entry := range entries {
+		treeSize = dtree.AddEntry(entry)
+		placements = append(placements, placedCertificate{entry.LogIndex, entry.CertificateIndex, CertificatePlacement{domain, treeSize - 1}})
+	}
+	m.dm.addCertificatePlacements(placements)
+	if m.mapRoot, err = m.dm.UpdateDomainTreeRoot(m.mapRoot, domain, treeSize); err != nil {
+		return nil, fmt.Errorf("error propagating tree root update for %q to the domain tree: %w", domain, err)
+	}
+	return dtree.GetRoot(treeSize)
+}
+
+// Publish verifies an upstream SMH against the recomputed map and publishes it unchanged.
+// An SMH with the same map size as the latest SMH is republished.
+func (m *Mirror) Publish(smh *SignedMapHead) error {
+	dm := m.dm
+	publicKey, ok := dm.publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("mirrors require an ECDSA key")
+	}
+	if err := VerifySMH(publicKey, smh); err != nil {
+		return err
+	}
+	if smh.Version != Version {
+		return fmt.Errorf("unsupported SMH version %d", smh.Version)
+	}
+	if !bytes.Equal(m.mapRoot, smh.MapRootHash[:]) {
+		return fmt.Errorf("recomputed map root %x differs from the root of the upstream SMH %x (map size %d)", m.mapRoot, smh.MapRootHash, smh.MapSize)
+	}
+	var mapSize uint64
+	for _, rev := range smh.SourceLogRevisions {
+		mapSize += rev.TreeSize
+	}
+	if mapSize != smh.MapSize {
+		return fmt.Errorf("invalid map size %d: the source logs have %d entries", smh.MapSize, mapSize)
+	}
+	sourceRoot, err := dm.sourceTree.GetRoot(uint64(len(smh.SourceLogRevisions)))
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sourceRoot, smh.SourceTreeRootHash[:]) {
+		return fmt.Errorf("recomputed source tree root %x differs from the upstream SMH %x", sourceRoot, smh.SourceTreeRootHash)
+	}
+
+	dm.mPublishSMH.Lock()
+	defer dm.mPublishSMH.Unlock()
+	currentSMH := dm.GetLatestSMH()
+	isRepublish := dm.HasPublishedSMH() && smh.MapSize == currentSMH.MapSize
+	if !isRepublish && smh.MapSize <= currentSMH.MapSize {
+		return fmt.Errorf("invalid map size: new map size (%d) <= current map size (%d)", smh.MapSize, currentSMH.MapSize)
+	}
+	if smh.Timestamp < currentSMH.Timestamp {
+		return fmt.Errorf("invalid timestamp: new timestamp (%d) < current timestamp (%d)", smh.Timestamp, currentSMH.Timestamp)
+	}
+	return dm.publish(smh, isRepublish)
+}
+
+// AddUpstreamCheckpoint verifies a checkpoint of the upstream map and serves it from
+// GetLatestCheckpoint, as long as it matches the latest SMH.
+func (dm *DomainMap) AddUpstreamCheckpoint(note []byte) error {
+	publicKey, ok := dm.publicKey.(*ecdsa.PublicKey)
+	if dm.signer != nil || !ok {
+		return fmt.Errorf("only mirrors of maps with ECDSA keys serve upstream checkpoints")
+	}
+	origin, head, err := VerifyCheckpoint(note, publicKey)
+	if err != nil {
+		return err
+	}
+	smh := dm.GetLatestSMH()
+	if !bytes.Equal(FormatCheckpoint(origin, head), FormatCheckpoint(origin, smh.MapHead)) {
+		return fmt.Errorf("upstream checkpoint (map size %d) does not match the latest SMH (map size %d)", head.MapSize, smh.MapSize)
+	}
+
+	dm.mCheckpoint.Lock()
+	defer dm.mCheckpoint.Unlock()
+	dm.checkpoint = note
+	dm.checkpointMapSize = head.MapSize
+	dm.checkpointOrigin = origin
+	return nil
+}
@@ -0,0 +1,147 @@
+package dt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/logid"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// addTestCertificates adds treeSize certificates of the log with the specified index to the
+// map, each for one of the domains in turn, and waits for the next SMH.
+func addTestCertificates(t *testing.T, dm *dt.DomainMap, c chan<- dt.WorkerTransaction, logIndex, firstIndex, treeSize uint64, domains []string) {
+	tx := dt.WorkerTransaction{
+		LogIndex:               logIndex,
+		LogID:                  logid.LogID{byte(logIndex + 1)},
+		LogRevision:            dt.LogRevision{TreeSize: treeSize},
+		NewCertificatesIndices: make(map[string][]uint64),
+		CertificateHashes:      make(map[uint64]dt.CertificateHashes),
+	}
+	for i := firstIndex; i < treeSize; i++ {
+		d := domains[int(i)%len(domains)]
+		tx.NewCertificatesIndices[d] = append(tx.NewCertificatesIndices[d], i)
+		tx.CertificateHashes[i] = dt.CertificateHashes{LeafHash: ct.SHA256Hash{byte(logIndex), byte(i), 1}, Fingerprint: ct.SHA256Hash{byte(i), 2}}
+	}
+	mapSize := dm.GetLatestSMH().MapSize
+	c <- tx
+	for deadline := time.Now().Add(10 * time.Second); dm.GetLatestSMH().MapSize == mapSize; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for an SMH")
+		}
+	}
+}
+
+// resigned returns a copy of the SMH with the specified map size, signed with key.
+func resigned(t *testing.T, smh *dt.SignedMapHead, mapSize uint64, key *ecdsa.PrivateKey) *dt.SignedMapHead {
+	head := smh.MapHead
+	head.MapSize = mapSize
+	data, err := dt.SignedData(dt.MapHeadSignatureType, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := ecdsa.SignASN1(rand.Reader, key, util.HashBytes(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dt.SignedMapHead{MapHead: head, MapHeadSignature: sig}
+}
+
+func TestMirror(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := dt.NewDomainMap(key)
+	ctx, cancel := context.WithCancel(context.Background())
+	c, stopped := dt.StartWorker(ctx, upstream, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+	domains := []string{"a.com", "b.com"}
+	addTestCertificates(t, upstream, c, 0, 0, 5, domains)
+	smh := upstream.GetLatestSMH()
+
+	mdm := dt.NewMirrorDomainMap(&key.PublicKey)
+	m := dt.NewMirror(mdm)
+	if err := m.Publish(smh); err == nil {
+		t.Errorf("Publish (empty mirror): expected an error")
+	}
+	m.AddSourceLog(logid.LogID{1})
+	for _, d := range domains {
+		tree, err := upstream.GetDomainTree(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := upstream.GetDomainTreeRoot(smh.MapRootHash[:], d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := tree.GetEntries(0, root.DomainTreeSize-1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.AddEntries(d, entries); err != nil {
+			t.Fatalf("AddEntries(%q): %v", d, err)
+		}
+	}
+	if _, err := m.AddEntries("c.com", nil); err == nil {
+		t.Errorf("AddEntries (no entries): expected an error")
+	}
+
+	if err := m.Publish(resigned(t, smh, smh.MapSize+1, key)); err == nil {
+		t.Errorf("Publish (invalid map size): expected an error")
+	}
+	if err := m.Publish(resigned(t, smh, smh.MapSize, otherKey)); err == nil {
+		t.Errorf("Publish (invalid signature): expected an error")
+	}
+	if err := m.Publish(smh); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if latest := mdm.GetLatestSMH(); latest.MapSize != smh.MapSize || latest.MapRootHash != smh.MapRootHash {
+		t.Errorf("GetLatestSMH: got map size %d, expected %d", latest.MapSize, smh.MapSize)
+	}
+	if _, err := mdm.GetLatestCheckpoint(""); err == nil {
+		t.Errorf("GetLatestCheckpoint (no upstream checkpoint): expected an error")
+	}
+
+	note, err := upstream.GetLatestCheckpoint("upstream.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mdm.AddUpstreamCheckpoint(note); err != nil {
+		t.Fatalf("AddUpstreamCheckpoint: %v", err)
+	}
+	if got, err := mdm.GetLatestCheckpoint(""); err != nil || string(got) != string(note) {
+		t.Errorf("GetLatestCheckpoint: got %q (%v), expected the upstream checkpoint", got, err)
+	}
+
+	// checkpoints of SMHs that the mirror hasn't published are rejected
+	addTestCertificates(t, upstream, c, 0, 5, 7, domains)
+	note, err = upstream.GetLatestCheckpoint("upstream.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mdm.AddUpstreamCheckpoint(note); err == nil {
+		t.Errorf("AddUpstreamCheckpoint (newer SMH): expected an error")
+	}
+	other := dt.NewDomainMap(otherKey)
+	otherC, otherStopped := dt.StartWorker(ctx, other, dt.WorkerConfig{BufferSize: 1, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-otherStopped }()
+	addTestCertificates(t, other, otherC, 0, 0, 5, domains)
+	note, err = other.GetLatestCheckpoint("upstream.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mdm.AddUpstreamCheckpoint(note); err == nil {
+		t.Errorf("AddUpstreamCheckpoint (other map): expected an error")
+	}
+}
@@ -0,0 +1,113 @@
+package dt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// A DomainMultiProof proves the (non-)containment of several domains at once.
+//
+// The proof is built by traversing the sparse merkle tree from the root,
+// following the paths of all domains simultaneously. Whenever all remaining
+// paths go to the same child, the other child is added to SideNodes;
+// when the paths split, both children are traversed (left first) and no side
+// node is needed. Thus side nodes shared by several paths appear only once,
+// and nodes that can be computed from other proven domains are omitted.
+//
+// Depths[i] is the depth at which the path of the i-th domain ends, i.e.,
+// the length of its individual (DomainProof) audit path.
+// NonMembershipLeafData[i] is set only if the path of the i-th domain ends in
+// a leaf belonging to another domain.
+type DomainMultiProof struct {
+	SideNodes             [][]byte
+	Depths                []int
+	NonMembershipLeafData [][]byte
+}
+
+// GetMultiProofForDomains returns a multiproof for the specified domains,
+// which must already be normalized and unique.
+func (dm *DomainMap) GetMultiProofForDomains(root []byte, normalizedDomains []string) (DomainMultiProof, error) {
+	proof := DomainMultiProof{
+		Depths:                make([]int, len(normalizedDomains)),
+		NonMembershipLeafData: make([][]byte, len(normalizedDomains)),
+	}
+	paths := make([][]byte, len(normalizedDomains))
+	keys := make([]int, len(normalizedDomains))
+	for i, d := range normalizedDomains {
+		paths[i] = util.HashBytes([]byte(d))
+		keys[i] = i
+	}
+
+	dm.m.RLock()
+	defer dm.m.RUnlock()
+	if err := dm.buildMultiProof(root, 0, keys, paths, &proof); err != nil {
+		return DomainMultiProof{}, err
+	}
+	return proof, nil
+}
+
+func (dm *DomainMap) buildMultiProof(hash []byte, depth int, keys []int, paths [][]byte, proof *DomainMultiProof) error {
+	if bytes.Equal(hash, dm.sparseStore.Placeholder()) {
+		for _, k := range keys {
+			proof.Depths[k] = depth
+		}
+		return nil
+	}
+
+	data, err := dm.sparseStore.Get(hash)
+	if err != nil {
+		return err
+	}
+	if len(data) != 1+2*sha256.Size {
+		return fmt.Errorf("invalid node data for hash %x", hash)
+	}
+	if data[0] == SMTLeafPrefix {
+		for _, k := range keys {
+			proof.Depths[k] = depth
+			if !bytes.Equal(data[1:1+sha256.Size], paths[k]) {
+				proof.NonMembershipLeafData[k] = data
+			}
+		}
+		return nil
+	}
+	if depth >= 8*sha256.Size {
+		return fmt.Errorf("invalid sparse merkle tree: inner node at maximum depth")
+	}
+
+	left, right := data[1:1+sha256.Size], data[1+sha256.Size:]
+	var leftKeys, rightKeys []int
+	for _, k := range keys {
+		if SMTPathBit(paths[k], depth) {
+			rightKeys = append(rightKeys, k)
+		} else {
+			leftKeys = append(leftKeys, k)
+		}
+	}
+
+	if len(rightKeys) == 0 {
+		proof.SideNodes = append(proof.SideNodes, right)
+		return dm.buildMultiProof(left, depth+1, leftKeys, paths, proof)
+	} else if len(leftKeys) == 0 {
+		proof.SideNodes = append(proof.SideNodes, left)
+		return dm.buildMultiProof(right, depth+1, rightKeys, paths, proof)
+	}
+	if err := dm.buildMultiProof(left, depth+1, leftKeys, paths, proof); err != nil {
+		return err
+	}
+	return dm.buildMultiProof(right, depth+1, rightKeys, paths, proof)
+}
+
+// Prefixes used by the sparse merkle tree (lazyledger/smt) to hash leaves and inner nodes.
+const (
+	SMTLeafPrefix byte = 0
+	SMTNodePrefix byte = 1
+)
+
+// SMTPathBit returns whether the path goes to the right child at the specified depth,
+// following the bit order used by the sparse merkle tree (lazyledger/smt).
+func SMTPathBit(path []byte, depth int) bool {
+	return path[depth/8]&(1<<(uint(depth)%8)) != 0
+}
@@ -0,0 +1,52 @@
+package dt
+
+import "sort"
+
+// A CertificatePlacement is the position of a certificate in a domain tree.
+type CertificatePlacement struct {
+	DomainName string // normalized
+	Index      uint64
+}
+
+// A placedCertificate is a CertificatePlacement of the certificate at CertificateIndex of the source log at LogIndex.
+type placedCertificate struct {
+	LogIndex, CertificateIndex uint64
+	CertificatePlacement
+}
+
+// addCertificatePlacements records the positions in which certificates of the
+// source logs were added to the domain trees, taking the map's lock only once.
+//
+// The placements are only kept in memory, and are never removed: like the domain trees,
+// dm.placements grows with every certificate added to the map, without bound.
+func (dm *DomainMap) addCertificatePlacements(placements []placedCertificate) {
+	if len(placements) == 0 {
+		return
+	}
+	dm.m.Lock()
+	defer dm.m.Unlock()
+	for _, p := range placements {
+		key := [2]uint64{p.LogIndex, p.CertificateIndex}
+		dm.placements[key] = append(dm.placements[key], p.CertificatePlacement)
+	}
+}
+
+// GetCertificatePlacements returns the positions of the certificate at the specified
+// index of a source log in all domain trees, sorted by domain name and index.
+//
+// The placements include entries that are not yet part of a published SMH.
+func (dm *DomainMap) GetCertificatePlacements(logIndex, certIndex uint64) []CertificatePlacement {
+	dm.m.RLock()
+	placements := dm.placements[[2]uint64{logIndex, certIndex}]
+	cp := make([]CertificatePlacement, len(placements))
+	copy(cp, placements)
+	dm.m.RUnlock()
+
+	sort.Slice(cp, func(i, j int) bool {
+		if cp[i].DomainName != cp[j].DomainName {
+			return cp[i].DomainName < cp[j].DomainName
+		}
+		return cp[i].Index < cp[j].Index
+	})
+	return cp
+}
@@ -0,0 +1,218 @@
+package ds
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+)
+
+// adminTimeout limits how long the admin operations wait for the worker.
+const adminTimeout = time.Minute
+
+// AdminConfig holds the controls used by the admin server.
+// Operations whose control is nil fail with CodeUnavailable.
+type AdminConfig struct {
+	Worker   *dt.WorkerControl
+	Fetchers *FetcherControl
+}
+
+// NewAdminServer creates the admin server, which should only be served over mTLS
+// (see AdminTLSConfig), since its operations are not otherwise authenticated.
+func NewAdminServer(addr string, config AdminConfig, tlsConfig *tls.Config) *http.Server {
+	h := &adminHandler{config}
+	mux := http.NewServeMux()
+	mux.Handle("/admin/v1/publish-smh", adminHandlerFunc{http.MethodPost, h.publishSMH})
+	mux.Handle("/admin/v1/worker-state", adminHandlerFunc{http.MethodGet, h.workerState})
+	mux.Handle("/admin/v1/snapshot", adminHandlerFunc{http.MethodPost, h.snapshot})
+	mux.Handle("/admin/v1/fetchers", adminHandlerFunc{http.MethodGet, h.fetchers})
+	mux.Handle("/admin/v1/pause-fetcher", adminHandlerFunc{http.MethodPost, h.pauseFetcher})
+	mux.Handle("/admin/v1/resume-fetcher", adminHandlerFunc{http.MethodPost, h.resumeFetcher})
+	return &http.Server{
+		Addr:        addr,
+		Handler:     mux,
+		TLSConfig:   tlsConfig,
+		ReadTimeout: 10 * time.Second,
+	}
+}
+
+type adminHandler struct {
+	config AdminConfig
+}
+
+// An adminHandlerFunc serves an admin operation, which only accepts the specified method.
+type adminHandlerFunc struct {
+	method string
+	handle func(*http.Request) (interface{}, error)
+}
+
+func (f adminHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer recoverPanic(w, r)
+	if r.Method != f.method {
+		w.Header().Set("Allow", f.method)
+		sendError(w, apiError{CodeInvalidArgument, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)})
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	ctx, cancel := context.WithTimeout(r.Context(), adminTimeout)
+	defer cancel()
+	data, err := f.handle(r.WithContext(ctx))
+	if err != nil {
+		sendError(w, err)
+	} else if err := sendJSON(w, data); err != nil {
+		fmt.Printf("Error responding to %q: %v\n", r.URL, err)
+	}
+}
+
+// workerError classifies an error returned by a WorkerControl.
+func workerError(err error) error {
+	if errors.Is(err, dt.ErrNothingToPublish) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return apiError{Code: CodeUnavailable, Base: err}
+	}
+	return apiError{Code: CodeInternal, Base: err}
+}
+
+func (h *adminHandler) worker() (*dt.WorkerControl, error) {
+	if h.config.Worker == nil {
+		return nil, errorf(CodeUnavailable, "the worker can't be controlled by this server")
+	}
+	return h.config.Worker, nil
+}
+
+// POST /admin/v1/publish-smh
+// Response:
+//
+//	the published SMH (as in get-smh)
+//
+// Publishes an SMH immediately. If the map didn't change, the latest SMH is republished.
+func (h *adminHandler) publishSMH(r *http.Request) (interface{}, error) {
+	worker, err := h.worker()
+	if err != nil {
+		return nil, err
+	}
+	smh, err := worker.PublishSMH(r.Context())
+	if err != nil {
+		return nil, workerError(err)
+	}
+	return smh, nil
+}
+
+// GET /admin/v1/worker-state
+// Response:
+//
+//	map_size: integer (including changes not yet published)
+//	map_root_hash: base64
+//	source_log_revisions: array of {tree_size: integer, root_hash: base64}
+//	channel_length: integer
+//	channel_capacity: integer
+//	queue: array of {log_index: integer, tree_size: integer, domains: integer, certificates: integer}
+func (h *adminHandler) workerState(r *http.Request) (interface{}, error) {
+	worker, err := h.worker()
+	if err != nil {
+		return nil, err
+	}
+	state, err := worker.State(r.Context())
+	if err != nil {
+		return nil, workerError(err)
+	}
+	return state, nil
+}
+
+// POST /admin/v1/snapshot
+// Response:
+//
+//	map_size: integer
+//	map_root_hash: base64
+//	source_log_revisions: array of {tree_size: integer, root_hash: base64}
+//	source_logs: array of base64 (log IDs)
+//	domain_trees: array of {domain_name: string, entries: array of {log_index: integer,
+//	  certificate_index: integer, leaf_hash: base64, certificate_fingerprint: base64}}
+//	latest_smh: SMH (as in get-smh)
+//
+// Returns a consistent copy of the contents of the map, including changes not yet published.
+// The worker is paused while the map is copied.
+func (h *adminHandler) snapshot(r *http.Request) (interface{}, error) {
+	worker, err := h.worker()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := worker.Snapshot(r.Context())
+	if err != nil {
+		return nil, workerError(err)
+	}
+	return snapshot, nil
+}
+
+func (h *adminHandler) fetcherControl() (*FetcherControl, error) {
+	if h.config.Fetchers == nil {
+		return nil, errorf(CodeUnavailable, "the fetchers can't be controlled by this server")
+	}
+	return h.config.Fetchers, nil
+}
+
+// GET /admin/v1/fetchers
+// Response:
+//
+//	array of {log_index: integer, paused: boolean}
+func (h *adminHandler) fetchers(r *http.Request) (interface{}, error) {
+	fetchers, err := h.fetcherControl()
+	if err != nil {
+		return nil, err
+	}
+	return fetchers.Status(), nil
+}
+
+// POST /admin/v1/pause-fetcher
+// Body:
+//
+//	log_index: integer
+//
+// Response:
+//
+//	array of {log_index: integer, paused: boolean} (as in fetchers)
+//
+// The fetcher stops before its next check for a new STH.
+func (h *adminHandler) pauseFetcher(r *http.Request) (interface{}, error) {
+	return h.setFetcherPaused(r, true)
+}
+
+// POST /admin/v1/resume-fetcher
+// Body:
+//
+//	log_index: integer
+//
+// Response:
+//
+//	array of {log_index: integer, paused: boolean} (as in fetchers)
+func (h *adminHandler) resumeFetcher(r *http.Request) (interface{}, error) {
+	return h.setFetcherPaused(r, false)
+}
+
+func (h *adminHandler) setFetcherPaused(r *http.Request, paused bool) (interface{}, error) {
+	fetchers, err := h.fetcherControl()
+	if err != nil {
+		return nil, err
+	}
+	var req AdminFetcherRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxPostBodySize)).Decode(&req); err != nil {
+		return nil, errorf(CodeInvalidArgument, "invalid request body: %s", err)
+	}
+	if req.LogIndex == nil {
+		return nil, errorf(CodeInvalidArgument, "missing log_index")
+	}
+	if paused {
+		err = fetchers.Pause(*req.LogIndex)
+	} else {
+		err = fetchers.Resume(*req.LogIndex)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fetchers.Status(), nil
+}
@@ -0,0 +1,193 @@
+package ds
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A cachePolicy tells how clients and proxies may cache the responses of an endpoint.
+type cachePolicy int
+
+const (
+	// noStore responses must not be cached (e.g. inclusion promises, which are timestamped).
+	noStore cachePolicy = iota
+	// shortLived responses depend on the latest state of the map, and may be cached for shortMaxAge.
+	shortLived
+	// immutable responses are pinned to a map, domain tree or source tree size, and never change.
+	// They are also kept in the in-process response cache.
+	immutable
+)
+
+// shortMaxAge is how long clients may cache shortLived responses.
+const shortMaxAge = 10 * time.Second
+
+// maxResponseCacheBytes limits the total size of the bodies kept in the in-process response cache.
+const maxResponseCacheBytes = 64 << 20
+
+func (p cachePolicy) cacheControl() string {
+	switch p {
+	case shortLived:
+		return "public, max-age=" + strconv.Itoa(int(shortMaxAge/time.Second))
+	case immutable:
+		return "public, max-age=31536000, immutable"
+	default:
+		return "no-store"
+	}
+}
+
+// A cachedResponse is a rendered response body, along with its strong ETag.
+type cachedResponse struct {
+	body        []byte
+	contentType string
+	etag        string
+}
+
+func newCachedResponse(body []byte, contentType string) *cachedResponse {
+	sum := sha256.Sum256(body)
+	return &cachedResponse{body, contentType, `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
+
+// A responseCache is an LRU cache of immutable responses, indexed by path and query.
+type responseCache struct {
+	m        sync.Mutex
+	maxBytes int
+	bytes    int
+	lru      *list.List               // of *responseCacheEntry, most recently used first
+	entries  map[string]*list.Element // indexed by key
+}
+
+type responseCacheEntry struct {
+	key  string
+	resp *cachedResponse
+}
+
+func newResponseCache(maxBytes int) *responseCache {
+	return &responseCache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *responseCache) get(key string) *cachedResponse {
+	c.m.Lock()
+	defer c.m.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*responseCacheEntry).resp
+}
+
+func (c *responseCache) add(key string, resp *cachedResponse) {
+	size := len(key) + len(resp.body)
+	if size > c.maxBytes {
+		return
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&responseCacheEntry{key, resp})
+	c.bytes += size
+	for c.bytes > c.maxBytes {
+		e := c.lru.Back().Value.(*responseCacheEntry)
+		c.lru.Remove(c.lru.Back())
+		delete(c.entries, e.key)
+		c.bytes -= len(e.key) + len(e.resp.body)
+	}
+}
+
+// A cachedHandler serves a GET endpoint with the caching headers of its policy:
+// a strong ETag (except for noStore responses), Cache-Control, and 304 responses
+// to matching If-None-Match requests. Immutable responses are kept in the cache.
+type cachedHandler struct {
+	policy cachePolicy
+	cache  *responseCache
+	render func(context.Context, url.Values) (*cachedResponse, error)
+}
+
+// cachedJSON returns a cachedHandler for a handler of JSON responses.
+func cachedJSON(policy cachePolicy, cache *responseCache, handler dtHandlerFunc) cachedHandler {
+	return cachedHandler{policy, cache, func(ctx context.Context, query url.Values) (*cachedResponse, error) {
+		data, err := handler(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		body, err := json.Marshal(data)
+		if err != nil {
+			return nil, errorf(CodeInternal, "error marshaling response: %s", err)
+		}
+		return newCachedResponse(body, "application/json; charset=utf-8"), nil
+	}}
+}
+
+// cachedText returns a cachedHandler for a handler of text responses.
+func cachedText(policy cachePolicy, cache *responseCache, handler dtTextHandlerFunc) cachedHandler {
+	return cachedHandler{policy, cache, func(ctx context.Context, query url.Values) (*cachedResponse, error) {
+		body, err := handler(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return newCachedResponse(body, "text/plain; charset=utf-8"), nil
+	}}
+}
+
+func (h cachedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer recoverPanic(w, r)
+	query := r.URL.Query()
+	key := r.URL.Path + "?" + query.Encode() // Encode sorts the parameters
+
+	var resp *cachedResponse
+	if h.policy == immutable {
+		resp = h.cache.get(key)
+	}
+	if resp == nil {
+		var err error
+		if resp, err = h.render(r.Context(), query); err != nil {
+			sendError(w, err)
+			return
+		}
+		if h.policy == immutable {
+			h.cache.add(key, resp)
+		}
+	}
+
+	w.Header().Set("Cache-Control", h.policy.cacheControl())
+	if h.policy != noStore {
+		w.Header().Set("ETag", resp.etag)
+		if etagMatches(r.Header.Get("If-None-Match"), resp.etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", resp.contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := w.Write(resp.body); err != nil {
+		fmt.Printf("Error responding to %q: %v\n", r.URL, err)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches the ETag,
+// using the weak comparison required by RFC 7232.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package ds
+
+import (
+	"context"
+
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+// maxGetCertificates is the maximum number of entries returned by get-certificates.
+const maxGetCertificates = 64
+
+// GET /dt/v1/get-certificates
+// Params:
+//
+//	domain_name: string
+//	start: integer
+//	end: integer
+//
+// Response:
+//
+//	map_size: integer (the map size of the latest SMH)
+//	certificates: array of {
+//	  entry: [integer, integer],
+//	  leaf_input: base64,
+//	  extra_data: base64,
+//	  ct_audit_path: array of base64
+//	}
+//
+// Returns the CT log entries of the domain tree entries in [start, end], so that clients
+// don't need to fetch them from the CT logs. The CT audit paths are inclusion proofs at the
+// source log revisions in the latest SMH, and only the entries of the domain tree in that
+// SMH are returned. At most maxGetCertificates entries are returned.
+func (h *dtHandler) getCertificates(ctx context.Context, req *GetCertificatesRequest) (*GetCertificatesResponse, error) {
+	if req.Start > req.End {
+		return nil, errorf(CodeInvalidArgument, "invalid range: [%d,%d]", req.Start, req.End)
+	}
+	if h.CTLogs == nil {
+		return nil, errorf(CodeUnavailable, "CT log entries are not available")
+	}
+	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
+	if err != nil {
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
+	}
+
+	smh := h.dm.GetLatestSMH()
+	dtr, err := h.dm.GetDomainTreeRoot(smh.MapRootHash[:], normalizedDomain)
+	if err != nil {
+		return nil, errorf(CodeInternal, "%s", err)
+	}
+	if dtr.DomainTreeSize == 0 {
+		return nil, errorf(CodeNotFound, "no domain tree for %q in the latest SMH", normalizedDomain)
+	}
+	if req.Start >= dtr.DomainTreeSize {
+		return nil, errorf(CodeOutOfRange, "invalid start: %d >= domain tree size (%d)", req.Start, dtr.DomainTreeSize)
+	}
+	end := req.End
+	if end >= dtr.DomainTreeSize {
+		end = dtr.DomainTreeSize - 1
+	}
+	if end-req.Start >= maxGetCertificates {
+		end = req.Start + maxGetCertificates - 1
+	}
+
+	tree, err := h.dm.GetDomainTree(normalizedDomain)
+	if err != nil {
+		return nil, errorf(CodeInternal, "%s", err)
+	}
+	entries, err := tree.GetEntries(req.Start, end)
+	if err != nil {
+		return nil, errorf(CodeInternal, "%s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ctRequestTimeout)
+	defer cancel()
+	resp := GetCertificatesResponse{MapSize: smh.MapSize, Certificates: make([]CertificateEntry, len(entries))}
+	for i, entry := range entries {
+		leaf, ctProof, err := h.ctEntry(ctx, smh, req.Start+uint64(i), entry)
+		if err != nil {
+			return nil, err
+		}
+		resp.Certificates[i] = CertificateEntry{
+			Entry:       [2]uint64{entry.LogIndex, entry.CertificateIndex},
+			LeafInput:   leaf.LeafInput,
+			ExtraData:   leaf.ExtraData,
+			CTAuditPath: ctProof,
+		}
+	}
+	return &resp, nil
+}
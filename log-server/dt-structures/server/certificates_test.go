@@ -0,0 +1,89 @@
+package ds_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+func TestGetCertificates(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	svr, h := ds.NewServer(dm, "localhost", 0)
+	h.CTLogs = ds.NewCTLogCache()
+	hs := httptest.NewServer(svr.Handler)
+	defer hs.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 4, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+
+	var certs []testCert
+	for i := uint64(0); i < 70; i++ {
+		certs = append(certs, testCert{0, i, []string{"a.com"}})
+	}
+	bad := testCert{0, 70, []string{"b.com"}}
+	certs = append(certs, bad)
+	addCertificates(t, dm, c, h.CTLogs, 0, uint64(len(certs)), certs)
+	// the cached CT entry of b.com doesn't match its leaf hash
+	h.CTLogs.AddLeaf(0, bad.certIndex, ct.LeafEntry{LeafInput: []byte("other leaf input")})
+
+	mc := mapclient.New(hs.URL+"/", hs.Client(), &key.PublicKey)
+	mc.SetRetryPolicy(mapclient.RetryPolicy{})
+	tests := []struct {
+		domain     string
+		start, end uint64
+		wantStart  uint64
+		wantCount  int
+		wantErr    error
+	}{
+		{"a.com", 0, 9, 0, 10, nil},
+		{"a.com", 0, 1000, 0, 64, nil},   // truncated to maxGetCertificates
+		{"a.com", 60, 1000, 60, 10, nil}, // truncated to the domain tree size
+		{"a.com", 69, 69, 69, 1, nil},
+		{"a.com", 70, 80, 0, 0, mapclient.ErrOutOfRange},
+		{"a.com", 5, 4, 0, 0, mapclient.ErrInvalidArgument},
+		{"missing.com", 0, 1, 0, 0, mapclient.ErrNotFound},
+		{"b.com", 0, 0, 0, 0, mapclient.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		resp, err := mc.GetCertificates(ctx, &ds.GetCertificatesRequest{DomainName: tt.domain, Start: tt.start, End: tt.end})
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s [%d,%d]: got error %v, expected %v", tt.domain, tt.start, tt.end, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s [%d,%d]: %v", tt.domain, tt.start, tt.end, err)
+			continue
+		}
+		if resp.MapSize != dm.GetLatestSMH().MapSize {
+			t.Errorf("%s [%d,%d]: got map size %d, expected %d", tt.domain, tt.start, tt.end, resp.MapSize, dm.GetLatestSMH().MapSize)
+		}
+		if len(resp.Certificates) != tt.wantCount {
+			t.Errorf("%s [%d,%d]: got %d certificates, expected %d", tt.domain, tt.start, tt.end, len(resp.Certificates), tt.wantCount)
+			continue
+		}
+		for i, cert := range resp.Certificates {
+			want := certs[tt.wantStart+uint64(i)]
+			if cert.Entry != [2]uint64{want.logIndex, want.certIndex} || string(cert.LeafInput) != string(want.leafInput()) {
+				t.Errorf("%s [%d,%d]: certificate %d is %v (leaf input %x), expected %v", tt.domain, tt.start, tt.end, i, cert.Entry, cert.LeafInput, want)
+			}
+		}
+	}
+}
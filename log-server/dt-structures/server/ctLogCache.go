@@ -0,0 +1,146 @@
+package ds
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/client"
+)
+
+// maxCachedProofs is the maximum number of CT inclusion proofs kept by a CTLogCache.
+const maxCachedProofs = 4096
+
+// maxCachedLeaves is the maximum number of CT log entries kept in memory by a CTLogCache
+// without a LeafStore.
+const maxCachedLeaves = 4096
+
+// A CTLogCache keeps a client for each source log and the CT log entries of the
+// certificates added to the map, so that the server can return CT entries along
+// with the domain tree entries (see get-domain-updates and get-certificates).
+type CTLogCache struct {
+	m       sync.RWMutex
+	clients map[uint64]*client.LogClient // indexed by log index
+	leaves  map[[2]uint64]ct.LeafEntry   // indexed by (log index, certificate index); unused if store is set
+	proofs  map[[3]uint64][][]byte       // indexed by (log index, certificate index, tree size)
+	store   *LeafStore
+}
+
+// NewCTLogCache creates a new empty CTLogCache, which keeps the most recent CT log entries
+// in memory (see maxCachedLeaves).
+func NewCTLogCache() *CTLogCache {
+	return &CTLogCache{
+		clients: make(map[uint64]*client.LogClient),
+		leaves:  make(map[[2]uint64]ct.LeafEntry),
+		proofs:  make(map[[3]uint64][][]byte),
+	}
+}
+
+// NewPersistentCTLogCache creates a CTLogCache that keeps the CT log entries in a LeafStore,
+// so that they survive restarts and don't take up memory.
+func NewPersistentCTLogCache(store *LeafStore) *CTLogCache {
+	c := NewCTLogCache()
+	c.store = store
+	return c
+}
+
+// AddLog sets the client used to fetch entries and proofs from the source log with the specified index.
+func (c *CTLogCache) AddLog(logIndex uint64, lc *client.LogClient) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.clients[logIndex] = lc
+}
+
+// AddLeaf caches the CT log entry at the specified index of a source log.
+func (c *CTLogCache) AddLeaf(logIndex, certIndex uint64, leaf ct.LeafEntry) {
+	if c.store != nil {
+		if err := c.store.Put(&leaf); err != nil {
+			fmt.Printf("Warning (log %d): error storing entry %d: %v\n", logIndex, certIndex, err)
+		}
+		return
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if len(c.leaves) >= maxCachedLeaves {
+		c.leaves = make(map[[2]uint64]ct.LeafEntry)
+	}
+	c.leaves[[2]uint64{logIndex, certIndex}] = leaf
+}
+
+func (c *CTLogCache) getClient(logIndex uint64) (*client.LogClient, error) {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	lc, ok := c.clients[logIndex]
+	if !ok {
+		return nil, fmt.Errorf("no client for log %d", logIndex)
+	}
+	return lc, nil
+}
+
+// GetLeaf returns the CT log entry with the specified leaf hash at the specified index of
+// a source log, fetching it from the log if it isn't cached. Entries fetched from the log
+// are returned even if their leaf hash differs, so callers should check it.
+func (c *CTLogCache) GetLeaf(ctx context.Context, logIndex, certIndex uint64, leafHash ct.SHA256Hash) (*ct.LeafEntry, error) {
+	if c.store != nil {
+		leaf, err := c.store.Get(leafHash)
+		if err != nil {
+			fmt.Printf("Warning (log %d): %v\n", logIndex, err)
+		} else if leaf != nil {
+			return leaf, nil
+		}
+	} else {
+		c.m.RLock()
+		leaf, ok := c.leaves[[2]uint64{logIndex, certIndex}]
+		c.m.RUnlock()
+		if ok {
+			return &leaf, nil
+		}
+	}
+
+	lc, err := c.getClient(logIndex)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := lc.GetRawEntries(ctx, int64(certIndex), int64(certIndex))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching entry %d of log %d: %w", certIndex, logIndex, err)
+	}
+	if len(resp.Entries) != 1 {
+		return nil, fmt.Errorf("error fetching entry %d of log %d: got %d entries", certIndex, logIndex, len(resp.Entries))
+	}
+	c.AddLeaf(logIndex, certIndex, resp.Entries[0])
+	return &resp.Entries[0], nil
+}
+
+// GetInclusionProof returns the inclusion proof for the CT log entry with the specified
+// index and leaf hash, at the specified tree size of the source log.
+func (c *CTLogCache) GetInclusionProof(ctx context.Context, logIndex, certIndex, treeSize uint64, leafHash ct.SHA256Hash) ([][]byte, error) {
+	key := [3]uint64{logIndex, certIndex, treeSize}
+	c.m.RLock()
+	proof, ok := c.proofs[key]
+	c.m.RUnlock()
+	if ok {
+		return proof, nil
+	}
+
+	lc, err := c.getClient(logIndex)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := lc.GetProofByHash(ctx, leafHash[:], treeSize)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching inclusion proof for entry %d of log %d: %w", certIndex, logIndex, err)
+	}
+	if resp.LeafIndex != int64(certIndex) {
+		return nil, fmt.Errorf("error fetching inclusion proof for entry %d of log %d: got proof for index %d", certIndex, logIndex, resp.LeafIndex)
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if len(c.proofs) >= maxCachedProofs {
+		c.proofs = make(map[[3]uint64][][]byte)
+	}
+	c.proofs[key] = resp.AuditPath
+	return resp.AuditPath, nil
+}
@@ -3,29 +3,56 @@ package ds
 import (
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // NewServer creates a new domain server.
 // The handler flags should only be modified BEFORE calling serve().
 func NewServer(dm *dt.DomainMap, ip string, port int) (*http.Server, *dtHandler) {
-	h := &dtHandler{dm}
+	h := &dtHandler{dm: dm, shutdown: make(chan struct{}), costs: make(map[string]int)}
+	cache := newResponseCache(maxResponseCacheBytes)
 	mux := http.NewServeMux()
-	mux.Handle("/dt/v1/get-smh", dtHandlerFunc(h.getSMH))
-	mux.Handle("/dt/v1/get-domain-root-and-proof", dtHandlerFunc(h.getDomainRootAndProof))
-	mux.Handle("/dt/v1/get-consistency-proof", dtHandlerFunc(h.getConsistencyProof))
-	mux.Handle("/dt/v1/get-entries", dtHandlerFunc(h.getEntries))
-	mux.Handle("/dt/v1/get-entry-and-proof", dtHandlerFunc(h.getEntryAndProof))
-	mux.Handle("/dt/v1/get-domain-tree-index", dtHandlerFunc(h.getDomainTreeIndex))
-	mux.Handle("/dt/v1/get-source-logs", dtHandlerFunc(h.getSourceLogs))
-	mux.Handle("/dt/v1/get-source-log-and-proof", dtHandlerFunc(h.getSourceLogAndProof))
-	mux.Handle("/dt/v1/get-source-consistency-proof", dtHandlerFunc(h.getSourceConsistencyProof))
-	return &http.Server{
+	handle := func(path string, cost int, handler http.Handler) {
+		h.costs[path] = cost
+		mux.Handle(path, instrumented(path, h.rateLimited(cost, h.requireSMH(handler))))
+	}
+	handle("/dt/v1/get-smh", costCheap, cachedJSON(shortLived, cache, jsonQuery(h.getSMH)))
+	handle("/dt/v1/get-smh-range", costCheap, cachedJSON(shortLived, cache, jsonQuery(h.getSMHRange)))
+	handle("/dt/v1/get-smh-at", costCheap, cachedJSON(shortLived, cache, jsonQuery(h.getSMHAt)))
+	handle("/dt/v1/checkpoint", costCheap, cachedText(shortLived, cache, textQuery(h.getCheckpoint)))
+	handle("/dt/v1/get-domain-root-and-proof", costSMT, cachedJSON(immutable, cache, jsonQuery(h.getDomainRootAndProof)))
+	handle("/dt/v1/get-domain-roots-and-multiproof", costMultiSMT, cachedJSON(immutable, cache, jsonQuery(h.getDomainRootsAndMultiProof)))
+	handle("/dt/v1/get-changed-domains", costMultiSMT, cachedJSON(immutable, cache, jsonQuery(h.getChangedDomains)))
+	handle("/dt/v1/get-domain-updates", costMultiSMT, cachedJSON(shortLived, cache, jsonQuery(h.getDomainUpdates)))
+	handle("/dt/v1/get-certificate-placements", costMultiSMT, cachedJSON(immutable, cache, jsonQuery(h.getCertificatePlacements)))
+	handle("/dt/v1/get-consistency-proof", costCheap, cachedJSON(immutable, cache, jsonQuery(h.getConsistencyProof)))
+	handle("/dt/v1/get-entries", costCheap, cachedJSON(shortLived, cache, jsonQuery(h.getEntries)))
+	handle("/dt/v1/get-certificates", costMultiSMT, cachedJSON(shortLived, cache, jsonQuery(h.getCertificates)))
+	handle("/dt/v1/get-entry-and-proof", costCheap, cachedJSON(immutable, cache, jsonQuery(h.getEntryAndProof)))
+	handle("/dt/v1/get-domain-tree-index", costCheap, cachedJSON(shortLived, cache, jsonQuery(h.getDomainTreeIndex)))
+	handle("/dt/v1/get-source-logs", costCheap, cachedJSON(shortLived, cache, jsonQuery(h.getSourceLogs)))
+	handle("/dt/v1/get-source-log-and-proof", costCheap, cachedJSON(immutable, cache, jsonQuery(h.getSourceLogAndProof)))
+	handle("/dt/v1/get-source-consistency-proof", costCheap, cachedJSON(immutable, cache, jsonQuery(h.getSourceConsistencyProof)))
+	handle("/dt/v1/get-inclusion-promise", costSMT, cachedJSON(noStore, cache, jsonQuery(h.getInclusionPromise)))
+	handle("/dt/v1/add-cosignature", costCheap, jsonBody(h.addCosignature))
+	mux.Handle("/dt/v1/subscribe", h.rateLimited(costCheap, h.requireSMH(http.HandlerFunc(h.subscribe))))
+	mux.Handle("/dt/v1/openapi.json", instrumented("/dt/v1/openapi.json", cachedHandler{shortLived, cache, getOpenAPI}))
+	mux.HandleFunc("/healthz", h.healthz)
+	mux.HandleFunc("/readyz", h.readyz)
+	mux.Handle("/metrics", promhttp.Handler())
+	svr := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", ip, port),
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
-	}, h
+		ConnContext:  saveConn,
+	}
+	// End subscriptions, so that Shutdown doesn't wait for them
+	var once sync.Once
+	svr.RegisterOnShutdown(func() { once.Do(func() { close(h.shutdown) }) })
+	return svr, h
 }
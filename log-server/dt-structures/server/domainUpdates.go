@@ -0,0 +1,155 @@
+package ds
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
+)
+
+const (
+	// maxDomainUpdates is the maximum number of entries returned by get-domain-updates.
+	maxDomainUpdates = 64
+	// ctRequestTimeout limits the time spent fetching CT entries and proofs for a single request.
+	ctRequestTimeout = 5 * time.Second
+)
+
+// GET /dt/v1/get-domain-updates
+// Params:
+//
+//	domain_name: string
+//	from: integer (the domain tree size already known by the client)
+//	map_size: integer
+//	start: integer (optional, defaults to from)
+//
+// Response:
+//
+//	smh: the SMH with the specified map size, with cosignatures (as in get-smh)
+//	domain_root: the domain tree root and its multiproof (as in get-domain-roots-and-multiproof)
+//	consistency_proof: array of base64 (from `from` to the domain tree size, empty if from is 0)
+//	entries: array of {
+//	  index: integer,
+//	  entry, leaf_hash, certificate_fingerprint, audit_path: as in get-entry-and-proof,
+//	  leaf_input: base64,
+//	  extra_data: base64,
+//	  ct_audit_path: array of base64
+//	}
+//	source_logs: array of {log_index: integer, log_id: base64, audit_path: array of base64}
+//
+// Returns a bundle that proves all domain tree entries in [max(from, start), domain tree size)
+// without further requests. The CT audit paths are inclusion proofs at the source log
+// revisions in the SMH, and the source log audit paths are inclusion proofs in the
+// source tree of the SMH. At most maxDomainUpdates entries are returned, so clients
+// should request the remaining ones starting after the last returned index.
+func (h *dtHandler) getDomainUpdates(ctx context.Context, req *GetDomainUpdatesRequest) (*GetDomainUpdatesResponse, error) {
+	if h.CTLogs == nil {
+		return nil, errorf(CodeUnavailable, "CT log entries are not available")
+	}
+	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
+	if err != nil {
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
+	}
+	smh, cosigs := h.dm.GetSMHAndCosignatures(req.MapSize)
+	if smh == nil {
+		return nil, errorf(CodeNotFound, "no SMH with map size %d", req.MapSize)
+	}
+	domainRoot, err := h.domainRootsAndMultiProof(smh.MapRootHash[:], []string{normalizedDomain})
+	if err != nil {
+		return nil, err
+	}
+	treeSize := domainRoot.Domains[0].DomainTreeSize
+	if req.From > treeSize {
+		return nil, errorf(CodeOutOfRange, "invalid from: %d > domain tree size (%d)", req.From, treeSize)
+	}
+
+	resp := GetDomainUpdatesResponse{
+		SMH:              GetSMHResponse{SignedMapHead: *smh, Cosignatures: cosigs},
+		DomainRoot:       *domainRoot,
+		ConsistencyProof: [][]byte{},
+		Entries:          []DomainUpdateEntry{},
+		SourceLogs:       []SourceLogInBundle{},
+	}
+	start := req.From
+	if req.Start > start {
+		start = req.Start
+	}
+	end := treeSize
+	if end > start+maxDomainUpdates {
+		end = start + maxDomainUpdates
+	}
+	if req.From == treeSize || (req.From == 0 && start >= end) {
+		return &resp, nil
+	}
+
+	tree, err := h.dm.GetDomainTree(normalizedDomain)
+	if err != nil {
+		return nil, errorf(CodeInternal, "%s", err)
+	}
+	if req.From > 0 {
+		resp.ConsistencyProof = tree.GetConsistencyProof(req.From, treeSize)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ctRequestTimeout)
+	defer cancel()
+	logIndices := make(map[uint64]struct{})
+	for i := start; i < end; i++ {
+		entry, proof, err := tree.GetEntryAndProof(treeSize, i)
+		if err != nil {
+			return nil, errorf(CodeInternal, "%s", err)
+		}
+		leaf, ctProof, err := h.ctEntry(ctx, smh, i, entry)
+		if err != nil {
+			return nil, err
+		}
+		resp.Entries = append(resp.Entries, DomainUpdateEntry{
+			Index: i,
+			GetEntryAndProofResponse: GetEntryAndProofResponse{
+				Entry:                  [2]uint64{entry.LogIndex, entry.CertificateIndex},
+				LeafHash:               entry.LeafHash[:],
+				CertificateFingerprint: entry.CertificateFingerprint[:],
+				AuditPath:              proof,
+			},
+			LeafInput:   leaf.LeafInput,
+			ExtraData:   leaf.ExtraData,
+			CTAuditPath: ctProof,
+		})
+		logIndices[entry.LogIndex] = struct{}{}
+	}
+
+	for logIndex := range logIndices {
+		logID, proof, err := h.dm.GetSourceTree().GetEntryAndProof(uint64(len(smh.SourceLogRevisions)), logIndex)
+		if err != nil {
+			return nil, errorf(CodeInternal, "%s", err)
+		}
+		resp.SourceLogs = append(resp.SourceLogs, SourceLogInBundle{
+			LogIndex:                     logIndex,
+			GetSourceLogAndProofResponse: GetSourceLogAndProofResponse{LogID: logID.Bytes(), AuditPath: proof},
+		})
+	}
+	sort.Slice(resp.SourceLogs, func(i, j int) bool { return resp.SourceLogs[i].LogIndex < resp.SourceLogs[j].LogIndex })
+	return &resp, nil
+}
+
+// ctEntry returns the CT log entry of a domain tree entry (with the specified index) and its
+// inclusion proof at the source log revision in the SMH.
+func (h *dtHandler) ctEntry(ctx context.Context, smh *dt.SignedMapHead, index uint64, entry dt.DomainTreeEntry) (*ct.LeafEntry, [][]byte, error) {
+	if entry.LogIndex >= uint64(len(smh.SourceLogRevisions)) {
+		return nil, nil, errorf(CodeInternal, "entry %d refers to unknown log %d", index, entry.LogIndex)
+	}
+	leaf, err := h.CTLogs.GetLeaf(ctx, entry.LogIndex, entry.CertificateIndex, entry.LeafHash)
+	if err != nil {
+		return nil, nil, apiError{Code: CodeUnavailable, Base: err}
+	}
+	if leafHash := leafHashOf(leaf); leafHash != entry.LeafHash {
+		return nil, nil, errorf(CodeUnavailable, "entry %d of log %d has leaf hash %x, expected %x", entry.CertificateIndex, entry.LogIndex, leafHash, entry.LeafHash)
+	}
+	rev := smh.SourceLogRevisions[entry.LogIndex]
+	ctProof, err := h.CTLogs.GetInclusionProof(ctx, entry.LogIndex, entry.CertificateIndex, rev.TreeSize, entry.LeafHash)
+	if err != nil {
+		return nil, nil, apiError{Code: CodeUnavailable, Base: err}
+	}
+	return leaf, ctProof, nil
+}
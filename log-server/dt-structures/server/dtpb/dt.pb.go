@@ -0,0 +1,4875 @@
+// The gRPC API of a domain map, served by ds.NewGRPCServer alongside the JSON HTTP API.
+//
+// Each unary method corresponds to a `/dt/v1/*` query of the HTTP API (see API.md),
+// with the same parameters and results. The Go code in this directory is generated
+// from this file (see generate.go).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: dt.proto
+
+package dtpb
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type LogRevision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeSize uint64 `protobuf:"varint,1,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	RootHash []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+}
+
+func (x *LogRevision) Reset() {
+	*x = LogRevision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRevision) ProtoMessage() {}
+
+func (x *LogRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRevision.ProtoReflect.Descriptor instead.
+func (*LogRevision) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LogRevision) GetTreeSize() uint64 {
+	if x != nil {
+		return x.TreeSize
+	}
+	return 0
+}
+
+func (x *LogRevision) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+type SignedMapHead struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version            uint32         `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"` // 0 for version 1 map heads
+	Timestamp          uint64         `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MapSize            uint64         `protobuf:"varint,3,opt,name=map_size,json=mapSize,proto3" json:"map_size,omitempty"`
+	MapRootHash        []byte         `protobuf:"bytes,4,opt,name=map_root_hash,json=mapRootHash,proto3" json:"map_root_hash,omitempty"`
+	SourceTreeRootHash []byte         `protobuf:"bytes,5,opt,name=source_tree_root_hash,json=sourceTreeRootHash,proto3" json:"source_tree_root_hash,omitempty"`
+	SourceLogRevisions []*LogRevision `protobuf:"bytes,6,rep,name=source_log_revisions,json=sourceLogRevisions,proto3" json:"source_log_revisions,omitempty"`
+	MapHeadSignature   []byte         `protobuf:"bytes,7,opt,name=map_head_signature,json=mapHeadSignature,proto3" json:"map_head_signature,omitempty"`
+}
+
+func (x *SignedMapHead) Reset() {
+	*x = SignedMapHead{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedMapHead) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedMapHead) ProtoMessage() {}
+
+func (x *SignedMapHead) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedMapHead.ProtoReflect.Descriptor instead.
+func (*SignedMapHead) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SignedMapHead) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SignedMapHead) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SignedMapHead) GetMapSize() uint64 {
+	if x != nil {
+		return x.MapSize
+	}
+	return 0
+}
+
+func (x *SignedMapHead) GetMapRootHash() []byte {
+	if x != nil {
+		return x.MapRootHash
+	}
+	return nil
+}
+
+func (x *SignedMapHead) GetSourceTreeRootHash() []byte {
+	if x != nil {
+		return x.SourceTreeRootHash
+	}
+	return nil
+}
+
+func (x *SignedMapHead) GetSourceLogRevisions() []*LogRevision {
+	if x != nil {
+		return x.SourceLogRevisions
+	}
+	return nil
+}
+
+func (x *SignedMapHead) GetMapHeadSignature() []byte {
+	if x != nil {
+		return x.MapHeadSignature
+	}
+	return nil
+}
+
+type MapHeadCosignature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WitnessKeyHash []byte `protobuf:"bytes,1,opt,name=witness_key_hash,json=witnessKeyHash,proto3" json:"witness_key_hash,omitempty"`
+	Timestamp      uint64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature      []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *MapHeadCosignature) Reset() {
+	*x = MapHeadCosignature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MapHeadCosignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapHeadCosignature) ProtoMessage() {}
+
+func (x *MapHeadCosignature) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapHeadCosignature.ProtoReflect.Descriptor instead.
+func (*MapHeadCosignature) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MapHeadCosignature) GetWitnessKeyHash() []byte {
+	if x != nil {
+		return x.WitnessKeyHash
+	}
+	return nil
+}
+
+func (x *MapHeadCosignature) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *MapHeadCosignature) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type GetSMHRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MapSize      *uint64 `protobuf:"varint,1,opt,name=map_size,json=mapSize,proto3,oneof" json:"map_size,omitempty"` // the latest SMH if omitted
+	Cosignatures bool    `protobuf:"varint,2,opt,name=cosignatures,proto3" json:"cosignatures,omitempty"`
+}
+
+func (x *GetSMHRequest) Reset() {
+	*x = GetSMHRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSMHRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSMHRequest) ProtoMessage() {}
+
+func (x *GetSMHRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSMHRequest.ProtoReflect.Descriptor instead.
+func (*GetSMHRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSMHRequest) GetMapSize() uint64 {
+	if x != nil && x.MapSize != nil {
+		return *x.MapSize
+	}
+	return 0
+}
+
+func (x *GetSMHRequest) GetCosignatures() bool {
+	if x != nil {
+		return x.Cosignatures
+	}
+	return false
+}
+
+type GetSMHResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Smh          *SignedMapHead        `protobuf:"bytes,1,opt,name=smh,proto3" json:"smh,omitempty"`
+	Cosignatures []*MapHeadCosignature `protobuf:"bytes,2,rep,name=cosignatures,proto3" json:"cosignatures,omitempty"` // only if requested
+}
+
+func (x *GetSMHResponse) Reset() {
+	*x = GetSMHResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSMHResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSMHResponse) ProtoMessage() {}
+
+func (x *GetSMHResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSMHResponse.ProtoReflect.Descriptor instead.
+func (*GetSMHResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSMHResponse) GetSmh() *SignedMapHead {
+	if x != nil {
+		return x.Smh
+	}
+	return nil
+}
+
+func (x *GetSMHResponse) GetCosignatures() []*MapHeadCosignature {
+	if x != nil {
+		return x.Cosignatures
+	}
+	return nil
+}
+
+type GetSMHRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start uint64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *GetSMHRangeRequest) Reset() {
+	*x = GetSMHRangeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSMHRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSMHRangeRequest) ProtoMessage() {}
+
+func (x *GetSMHRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSMHRangeRequest.ProtoReflect.Descriptor instead.
+func (*GetSMHRangeRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetSMHRangeRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *GetSMHRangeRequest) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type GetSMHRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Smhs []*SignedMapHead `protobuf:"bytes,1,rep,name=smhs,proto3" json:"smhs,omitempty"`
+}
+
+func (x *GetSMHRangeResponse) Reset() {
+	*x = GetSMHRangeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSMHRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSMHRangeResponse) ProtoMessage() {}
+
+func (x *GetSMHRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSMHRangeResponse.ProtoReflect.Descriptor instead.
+func (*GetSMHRangeResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetSMHRangeResponse) GetSmhs() []*SignedMapHead {
+	if x != nil {
+		return x.Smhs
+	}
+	return nil
+}
+
+type GetSMHAtRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp uint64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *GetSMHAtRequest) Reset() {
+	*x = GetSMHAtRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSMHAtRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSMHAtRequest) ProtoMessage() {}
+
+func (x *GetSMHAtRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSMHAtRequest.ProtoReflect.Descriptor instead.
+func (*GetSMHAtRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSMHAtRequest) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type GetCheckpointRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetCheckpointRequest) Reset() {
+	*x = GetCheckpointRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCheckpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckpointRequest) ProtoMessage() {}
+
+func (x *GetCheckpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckpointRequest.ProtoReflect.Descriptor instead.
+func (*GetCheckpointRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{8}
+}
+
+type GetCheckpointResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Checkpoint []byte `protobuf:"bytes,1,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"` // the signed note served by /dt/v1/checkpoint
+}
+
+func (x *GetCheckpointResponse) Reset() {
+	*x = GetCheckpointResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCheckpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckpointResponse) ProtoMessage() {}
+
+func (x *GetCheckpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckpointResponse.ProtoReflect.Descriptor instead.
+func (*GetCheckpointResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCheckpointResponse) GetCheckpoint() []byte {
+	if x != nil {
+		return x.Checkpoint
+	}
+	return nil
+}
+
+type GetDomainRootAndProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName    string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	DomainMapSize uint64 `protobuf:"varint,2,opt,name=domain_map_size,json=domainMapSize,proto3" json:"domain_map_size,omitempty"`
+}
+
+func (x *GetDomainRootAndProofRequest) Reset() {
+	*x = GetDomainRootAndProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainRootAndProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainRootAndProofRequest) ProtoMessage() {}
+
+func (x *GetDomainRootAndProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainRootAndProofRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainRootAndProofRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDomainRootAndProofRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetDomainRootAndProofRequest) GetDomainMapSize() uint64 {
+	if x != nil {
+		return x.DomainMapSize
+	}
+	return 0
+}
+
+type GetDomainRootAndProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainTreeSize        uint64   `protobuf:"varint,1,opt,name=domain_tree_size,json=domainTreeSize,proto3" json:"domain_tree_size,omitempty"`
+	DomainTreeRootHash    []byte   `protobuf:"bytes,2,opt,name=domain_tree_root_hash,json=domainTreeRootHash,proto3" json:"domain_tree_root_hash,omitempty"`
+	NormalizedDomainName  string   `protobuf:"bytes,3,opt,name=normalized_domain_name,json=normalizedDomainName,proto3" json:"normalized_domain_name,omitempty"`
+	AuditPath             [][]byte `protobuf:"bytes,4,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+	NonMembershipLeafData []byte   `protobuf:"bytes,5,opt,name=non_membership_leaf_data,json=nonMembershipLeafData,proto3" json:"non_membership_leaf_data,omitempty"`
+}
+
+func (x *GetDomainRootAndProofResponse) Reset() {
+	*x = GetDomainRootAndProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainRootAndProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainRootAndProofResponse) ProtoMessage() {}
+
+func (x *GetDomainRootAndProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainRootAndProofResponse.ProtoReflect.Descriptor instead.
+func (*GetDomainRootAndProofResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetDomainRootAndProofResponse) GetDomainTreeSize() uint64 {
+	if x != nil {
+		return x.DomainTreeSize
+	}
+	return 0
+}
+
+func (x *GetDomainRootAndProofResponse) GetDomainTreeRootHash() []byte {
+	if x != nil {
+		return x.DomainTreeRootHash
+	}
+	return nil
+}
+
+func (x *GetDomainRootAndProofResponse) GetNormalizedDomainName() string {
+	if x != nil {
+		return x.NormalizedDomainName
+	}
+	return ""
+}
+
+func (x *GetDomainRootAndProofResponse) GetAuditPath() [][]byte {
+	if x != nil {
+		return x.AuditPath
+	}
+	return nil
+}
+
+func (x *GetDomainRootAndProofResponse) GetNonMembershipLeafData() []byte {
+	if x != nil {
+		return x.NonMembershipLeafData
+	}
+	return nil
+}
+
+type GetDomainRootsAndMultiProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName    []string `protobuf:"bytes,1,rep,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	DomainMapSize uint64   `protobuf:"varint,2,opt,name=domain_map_size,json=domainMapSize,proto3" json:"domain_map_size,omitempty"`
+}
+
+func (x *GetDomainRootsAndMultiProofRequest) Reset() {
+	*x = GetDomainRootsAndMultiProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainRootsAndMultiProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainRootsAndMultiProofRequest) ProtoMessage() {}
+
+func (x *GetDomainRootsAndMultiProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainRootsAndMultiProofRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainRootsAndMultiProofRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetDomainRootsAndMultiProofRequest) GetDomainName() []string {
+	if x != nil {
+		return x.DomainName
+	}
+	return nil
+}
+
+func (x *GetDomainRootsAndMultiProofRequest) GetDomainMapSize() uint64 {
+	if x != nil {
+		return x.DomainMapSize
+	}
+	return 0
+}
+
+type DomainRootInMultiProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainTreeSize        uint64 `protobuf:"varint,1,opt,name=domain_tree_size,json=domainTreeSize,proto3" json:"domain_tree_size,omitempty"`
+	DomainTreeRootHash    []byte `protobuf:"bytes,2,opt,name=domain_tree_root_hash,json=domainTreeRootHash,proto3" json:"domain_tree_root_hash,omitempty"`
+	NormalizedDomainName  string `protobuf:"bytes,3,opt,name=normalized_domain_name,json=normalizedDomainName,proto3" json:"normalized_domain_name,omitempty"`
+	ProofDepth            int32  `protobuf:"varint,4,opt,name=proof_depth,json=proofDepth,proto3" json:"proof_depth,omitempty"`
+	NonMembershipLeafData []byte `protobuf:"bytes,5,opt,name=non_membership_leaf_data,json=nonMembershipLeafData,proto3" json:"non_membership_leaf_data,omitempty"`
+}
+
+func (x *DomainRootInMultiProof) Reset() {
+	*x = DomainRootInMultiProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DomainRootInMultiProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainRootInMultiProof) ProtoMessage() {}
+
+func (x *DomainRootInMultiProof) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainRootInMultiProof.ProtoReflect.Descriptor instead.
+func (*DomainRootInMultiProof) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DomainRootInMultiProof) GetDomainTreeSize() uint64 {
+	if x != nil {
+		return x.DomainTreeSize
+	}
+	return 0
+}
+
+func (x *DomainRootInMultiProof) GetDomainTreeRootHash() []byte {
+	if x != nil {
+		return x.DomainTreeRootHash
+	}
+	return nil
+}
+
+func (x *DomainRootInMultiProof) GetNormalizedDomainName() string {
+	if x != nil {
+		return x.NormalizedDomainName
+	}
+	return ""
+}
+
+func (x *DomainRootInMultiProof) GetProofDepth() int32 {
+	if x != nil {
+		return x.ProofDepth
+	}
+	return 0
+}
+
+func (x *DomainRootInMultiProof) GetNonMembershipLeafData() []byte {
+	if x != nil {
+		return x.NonMembershipLeafData
+	}
+	return nil
+}
+
+type GetDomainRootsAndMultiProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Domains   []*DomainRootInMultiProof `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	SideNodes [][]byte                  `protobuf:"bytes,2,rep,name=side_nodes,json=sideNodes,proto3" json:"side_nodes,omitempty"`
+}
+
+func (x *GetDomainRootsAndMultiProofResponse) Reset() {
+	*x = GetDomainRootsAndMultiProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainRootsAndMultiProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainRootsAndMultiProofResponse) ProtoMessage() {}
+
+func (x *GetDomainRootsAndMultiProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainRootsAndMultiProofResponse.ProtoReflect.Descriptor instead.
+func (*GetDomainRootsAndMultiProofResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetDomainRootsAndMultiProofResponse) GetDomains() []*DomainRootInMultiProof {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *GetDomainRootsAndMultiProofResponse) GetSideNodes() [][]byte {
+	if x != nil {
+		return x.SideNodes
+	}
+	return nil
+}
+
+type GetChangedDomainsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	First  uint64 `protobuf:"varint,1,opt,name=first,proto3" json:"first,omitempty"`
+	Second uint64 `protobuf:"varint,2,opt,name=second,proto3" json:"second,omitempty"`
+	Start  uint64 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+}
+
+func (x *GetChangedDomainsRequest) Reset() {
+	*x = GetChangedDomainsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetChangedDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangedDomainsRequest) ProtoMessage() {}
+
+func (x *GetChangedDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangedDomainsRequest.ProtoReflect.Descriptor instead.
+func (*GetChangedDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetChangedDomainsRequest) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *GetChangedDomainsRequest) GetSecond() uint64 {
+	if x != nil {
+		return x.Second
+	}
+	return 0
+}
+
+func (x *GetChangedDomainsRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+type GetChangedDomainsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total  uint64                               `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	First  *GetDomainRootsAndMultiProofResponse `protobuf:"bytes,2,opt,name=first,proto3" json:"first,omitempty"`
+	Second *GetDomainRootsAndMultiProofResponse `protobuf:"bytes,3,opt,name=second,proto3" json:"second,omitempty"`
+}
+
+func (x *GetChangedDomainsResponse) Reset() {
+	*x = GetChangedDomainsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetChangedDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChangedDomainsResponse) ProtoMessage() {}
+
+func (x *GetChangedDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChangedDomainsResponse.ProtoReflect.Descriptor instead.
+func (*GetChangedDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetChangedDomainsResponse) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetChangedDomainsResponse) GetFirst() *GetDomainRootsAndMultiProofResponse {
+	if x != nil {
+		return x.First
+	}
+	return nil
+}
+
+func (x *GetChangedDomainsResponse) GetSecond() *GetDomainRootsAndMultiProofResponse {
+	if x != nil {
+		return x.Second
+	}
+	return nil
+}
+
+type GetDomainUpdatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	From       uint64 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	MapSize    uint64 `protobuf:"varint,3,opt,name=map_size,json=mapSize,proto3" json:"map_size,omitempty"`
+	Start      uint64 `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"`
+}
+
+func (x *GetDomainUpdatesRequest) Reset() {
+	*x = GetDomainUpdatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainUpdatesRequest) ProtoMessage() {}
+
+func (x *GetDomainUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetDomainUpdatesRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetDomainUpdatesRequest) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetDomainUpdatesRequest) GetMapSize() uint64 {
+	if x != nil {
+		return x.MapSize
+	}
+	return 0
+}
+
+func (x *GetDomainUpdatesRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+// A domain tree entry of a GetDomainUpdates bundle (as in GetEntryAndProofResponse),
+// along with its CT log entry.
+type DomainUpdateEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index                  uint64           `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Entry                  *DomainTreeEntry `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	LeafHash               []byte           `protobuf:"bytes,3,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	CertificateFingerprint []byte           `protobuf:"bytes,4,opt,name=certificate_fingerprint,json=certificateFingerprint,proto3" json:"certificate_fingerprint,omitempty"`
+	AuditPath              [][]byte         `protobuf:"bytes,5,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+	LeafInput              []byte           `protobuf:"bytes,6,opt,name=leaf_input,json=leafInput,proto3" json:"leaf_input,omitempty"`
+	ExtraData              []byte           `protobuf:"bytes,7,opt,name=extra_data,json=extraData,proto3" json:"extra_data,omitempty"`
+	CtAuditPath            [][]byte         `protobuf:"bytes,8,rep,name=ct_audit_path,json=ctAuditPath,proto3" json:"ct_audit_path,omitempty"`
+}
+
+func (x *DomainUpdateEntry) Reset() {
+	*x = DomainUpdateEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DomainUpdateEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainUpdateEntry) ProtoMessage() {}
+
+func (x *DomainUpdateEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainUpdateEntry.ProtoReflect.Descriptor instead.
+func (*DomainUpdateEntry) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DomainUpdateEntry) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *DomainUpdateEntry) GetEntry() *DomainTreeEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *DomainUpdateEntry) GetLeafHash() []byte {
+	if x != nil {
+		return x.LeafHash
+	}
+	return nil
+}
+
+func (x *DomainUpdateEntry) GetCertificateFingerprint() []byte {
+	if x != nil {
+		return x.CertificateFingerprint
+	}
+	return nil
+}
+
+func (x *DomainUpdateEntry) GetAuditPath() [][]byte {
+	if x != nil {
+		return x.AuditPath
+	}
+	return nil
+}
+
+func (x *DomainUpdateEntry) GetLeafInput() []byte {
+	if x != nil {
+		return x.LeafInput
+	}
+	return nil
+}
+
+func (x *DomainUpdateEntry) GetExtraData() []byte {
+	if x != nil {
+		return x.ExtraData
+	}
+	return nil
+}
+
+func (x *DomainUpdateEntry) GetCtAuditPath() [][]byte {
+	if x != nil {
+		return x.CtAuditPath
+	}
+	return nil
+}
+
+// A source log of a GetDomainUpdates bundle (as in GetSourceLogAndProofResponse).
+type SourceLogInBundle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogIndex  uint64   `protobuf:"varint,1,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	LogId     []byte   `protobuf:"bytes,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	AuditPath [][]byte `protobuf:"bytes,3,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+}
+
+func (x *SourceLogInBundle) Reset() {
+	*x = SourceLogInBundle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SourceLogInBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceLogInBundle) ProtoMessage() {}
+
+func (x *SourceLogInBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceLogInBundle.ProtoReflect.Descriptor instead.
+func (*SourceLogInBundle) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SourceLogInBundle) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *SourceLogInBundle) GetLogId() []byte {
+	if x != nil {
+		return x.LogId
+	}
+	return nil
+}
+
+func (x *SourceLogInBundle) GetAuditPath() [][]byte {
+	if x != nil {
+		return x.AuditPath
+	}
+	return nil
+}
+
+type GetDomainUpdatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Smh              *GetSMHResponse                      `protobuf:"bytes,1,opt,name=smh,proto3" json:"smh,omitempty"`
+	DomainRoot       *GetDomainRootsAndMultiProofResponse `protobuf:"bytes,2,opt,name=domain_root,json=domainRoot,proto3" json:"domain_root,omitempty"`
+	ConsistencyProof [][]byte                             `protobuf:"bytes,3,rep,name=consistency_proof,json=consistencyProof,proto3" json:"consistency_proof,omitempty"`
+	Entries          []*DomainUpdateEntry                 `protobuf:"bytes,4,rep,name=entries,proto3" json:"entries,omitempty"`
+	SourceLogs       []*SourceLogInBundle                 `protobuf:"bytes,5,rep,name=source_logs,json=sourceLogs,proto3" json:"source_logs,omitempty"`
+}
+
+func (x *GetDomainUpdatesResponse) Reset() {
+	*x = GetDomainUpdatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainUpdatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainUpdatesResponse) ProtoMessage() {}
+
+func (x *GetDomainUpdatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainUpdatesResponse.ProtoReflect.Descriptor instead.
+func (*GetDomainUpdatesResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetDomainUpdatesResponse) GetSmh() *GetSMHResponse {
+	if x != nil {
+		return x.Smh
+	}
+	return nil
+}
+
+func (x *GetDomainUpdatesResponse) GetDomainRoot() *GetDomainRootsAndMultiProofResponse {
+	if x != nil {
+		return x.DomainRoot
+	}
+	return nil
+}
+
+func (x *GetDomainUpdatesResponse) GetConsistencyProof() [][]byte {
+	if x != nil {
+		return x.ConsistencyProof
+	}
+	return nil
+}
+
+func (x *GetDomainUpdatesResponse) GetEntries() []*DomainUpdateEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetDomainUpdatesResponse) GetSourceLogs() []*SourceLogInBundle {
+	if x != nil {
+		return x.SourceLogs
+	}
+	return nil
+}
+
+type GetCertificatePlacementsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogIndex         uint64 `protobuf:"varint,1,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	CertificateIndex uint64 `protobuf:"varint,2,opt,name=certificate_index,json=certificateIndex,proto3" json:"certificate_index,omitempty"`
+	MapSize          uint64 `protobuf:"varint,3,opt,name=map_size,json=mapSize,proto3" json:"map_size,omitempty"`
+	Start            uint64 `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"`
+}
+
+func (x *GetCertificatePlacementsRequest) Reset() {
+	*x = GetCertificatePlacementsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCertificatePlacementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificatePlacementsRequest) ProtoMessage() {}
+
+func (x *GetCertificatePlacementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificatePlacementsRequest.ProtoReflect.Descriptor instead.
+func (*GetCertificatePlacementsRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetCertificatePlacementsRequest) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *GetCertificatePlacementsRequest) GetCertificateIndex() uint64 {
+	if x != nil {
+		return x.CertificateIndex
+	}
+	return 0
+}
+
+func (x *GetCertificatePlacementsRequest) GetMapSize() uint64 {
+	if x != nil {
+		return x.MapSize
+	}
+	return 0
+}
+
+func (x *GetCertificatePlacementsRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+// The position of a certificate in a domain tree, along with the entry
+// and its audit path (as in GetEntryAndProofResponse).
+type CertificatePlacement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NormalizedDomainName   string           `protobuf:"bytes,1,opt,name=normalized_domain_name,json=normalizedDomainName,proto3" json:"normalized_domain_name,omitempty"`
+	Index                  uint64           `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Entry                  *DomainTreeEntry `protobuf:"bytes,3,opt,name=entry,proto3" json:"entry,omitempty"`
+	LeafHash               []byte           `protobuf:"bytes,4,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	CertificateFingerprint []byte           `protobuf:"bytes,5,opt,name=certificate_fingerprint,json=certificateFingerprint,proto3" json:"certificate_fingerprint,omitempty"`
+	AuditPath              [][]byte         `protobuf:"bytes,6,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+}
+
+func (x *CertificatePlacement) Reset() {
+	*x = CertificatePlacement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CertificatePlacement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificatePlacement) ProtoMessage() {}
+
+func (x *CertificatePlacement) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificatePlacement.ProtoReflect.Descriptor instead.
+func (*CertificatePlacement) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CertificatePlacement) GetNormalizedDomainName() string {
+	if x != nil {
+		return x.NormalizedDomainName
+	}
+	return ""
+}
+
+func (x *CertificatePlacement) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CertificatePlacement) GetEntry() *DomainTreeEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *CertificatePlacement) GetLeafHash() []byte {
+	if x != nil {
+		return x.LeafHash
+	}
+	return nil
+}
+
+func (x *CertificatePlacement) GetCertificateFingerprint() []byte {
+	if x != nil {
+		return x.CertificateFingerprint
+	}
+	return nil
+}
+
+func (x *CertificatePlacement) GetAuditPath() [][]byte {
+	if x != nil {
+		return x.AuditPath
+	}
+	return nil
+}
+
+type GetCertificatePlacementsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total       uint64                               `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Placements  []*CertificatePlacement              `protobuf:"bytes,2,rep,name=placements,proto3" json:"placements,omitempty"`
+	DomainRoots *GetDomainRootsAndMultiProofResponse `protobuf:"bytes,3,opt,name=domain_roots,json=domainRoots,proto3" json:"domain_roots,omitempty"`
+}
+
+func (x *GetCertificatePlacementsResponse) Reset() {
+	*x = GetCertificatePlacementsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCertificatePlacementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificatePlacementsResponse) ProtoMessage() {}
+
+func (x *GetCertificatePlacementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificatePlacementsResponse.ProtoReflect.Descriptor instead.
+func (*GetCertificatePlacementsResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetCertificatePlacementsResponse) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetCertificatePlacementsResponse) GetPlacements() []*CertificatePlacement {
+	if x != nil {
+		return x.Placements
+	}
+	return nil
+}
+
+func (x *GetCertificatePlacementsResponse) GetDomainRoots() *GetDomainRootsAndMultiProofResponse {
+	if x != nil {
+		return x.DomainRoots
+	}
+	return nil
+}
+
+type GetConsistencyProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	First      uint64 `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
+	Second     uint64 `protobuf:"varint,3,opt,name=second,proto3" json:"second,omitempty"`
+}
+
+func (x *GetConsistencyProofRequest) Reset() {
+	*x = GetConsistencyProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConsistencyProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConsistencyProofRequest) ProtoMessage() {}
+
+func (x *GetConsistencyProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConsistencyProofRequest.ProtoReflect.Descriptor instead.
+func (*GetConsistencyProofRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetConsistencyProofRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetConsistencyProofRequest) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *GetConsistencyProofRequest) GetSecond() uint64 {
+	if x != nil {
+		return x.Second
+	}
+	return 0
+}
+
+type GetConsistencyProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Proof [][]byte `protobuf:"bytes,1,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *GetConsistencyProofResponse) Reset() {
+	*x = GetConsistencyProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConsistencyProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConsistencyProofResponse) ProtoMessage() {}
+
+func (x *GetConsistencyProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConsistencyProofResponse.ProtoReflect.Descriptor instead.
+func (*GetConsistencyProofResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetConsistencyProofResponse) GetProof() [][]byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type GetEntriesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	Start      uint64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End        uint64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *GetEntriesRequest) Reset() {
+	*x = GetEntriesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEntriesRequest) ProtoMessage() {}
+
+func (x *GetEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEntriesRequest.ProtoReflect.Descriptor instead.
+func (*GetEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetEntriesRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetEntriesRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *GetEntriesRequest) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+// The position of a certificate in a source log.
+type DomainTreeEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogIndex         uint64 `protobuf:"varint,1,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	CertificateIndex uint64 `protobuf:"varint,2,opt,name=certificate_index,json=certificateIndex,proto3" json:"certificate_index,omitempty"`
+}
+
+func (x *DomainTreeEntry) Reset() {
+	*x = DomainTreeEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DomainTreeEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainTreeEntry) ProtoMessage() {}
+
+func (x *DomainTreeEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainTreeEntry.ProtoReflect.Descriptor instead.
+func (*DomainTreeEntry) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DomainTreeEntry) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *DomainTreeEntry) GetCertificateIndex() uint64 {
+	if x != nil {
+		return x.CertificateIndex
+	}
+	return 0
+}
+
+type GetEntriesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries                 []*DomainTreeEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	LeafHashes              [][]byte           `protobuf:"bytes,2,rep,name=leaf_hashes,json=leafHashes,proto3" json:"leaf_hashes,omitempty"`
+	CertificateFingerprints [][]byte           `protobuf:"bytes,3,rep,name=certificate_fingerprints,json=certificateFingerprints,proto3" json:"certificate_fingerprints,omitempty"`
+}
+
+func (x *GetEntriesResponse) Reset() {
+	*x = GetEntriesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEntriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEntriesResponse) ProtoMessage() {}
+
+func (x *GetEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEntriesResponse.ProtoReflect.Descriptor instead.
+func (*GetEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetEntriesResponse) GetEntries() []*DomainTreeEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetEntriesResponse) GetLeafHashes() [][]byte {
+	if x != nil {
+		return x.LeafHashes
+	}
+	return nil
+}
+
+func (x *GetEntriesResponse) GetCertificateFingerprints() [][]byte {
+	if x != nil {
+		return x.CertificateFingerprints
+	}
+	return nil
+}
+
+type GetCertificatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	Start      uint64 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End        uint64 `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *GetCertificatesRequest) Reset() {
+	*x = GetCertificatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCertificatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificatesRequest) ProtoMessage() {}
+
+func (x *GetCertificatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificatesRequest.ProtoReflect.Descriptor instead.
+func (*GetCertificatesRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetCertificatesRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetCertificatesRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *GetCertificatesRequest) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type CertificateEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entry       *DomainTreeEntry `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	LeafInput   []byte           `protobuf:"bytes,2,opt,name=leaf_input,json=leafInput,proto3" json:"leaf_input,omitempty"`
+	ExtraData   []byte           `protobuf:"bytes,3,opt,name=extra_data,json=extraData,proto3" json:"extra_data,omitempty"`
+	CtAuditPath [][]byte         `protobuf:"bytes,4,rep,name=ct_audit_path,json=ctAuditPath,proto3" json:"ct_audit_path,omitempty"`
+}
+
+func (x *CertificateEntry) Reset() {
+	*x = CertificateEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CertificateEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificateEntry) ProtoMessage() {}
+
+func (x *CertificateEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificateEntry.ProtoReflect.Descriptor instead.
+func (*CertificateEntry) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CertificateEntry) GetEntry() *DomainTreeEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *CertificateEntry) GetLeafInput() []byte {
+	if x != nil {
+		return x.LeafInput
+	}
+	return nil
+}
+
+func (x *CertificateEntry) GetExtraData() []byte {
+	if x != nil {
+		return x.ExtraData
+	}
+	return nil
+}
+
+func (x *CertificateEntry) GetCtAuditPath() [][]byte {
+	if x != nil {
+		return x.CtAuditPath
+	}
+	return nil
+}
+
+type GetCertificatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MapSize      uint64              `protobuf:"varint,1,opt,name=map_size,json=mapSize,proto3" json:"map_size,omitempty"`
+	Certificates []*CertificateEntry `protobuf:"bytes,2,rep,name=certificates,proto3" json:"certificates,omitempty"`
+}
+
+func (x *GetCertificatesResponse) Reset() {
+	*x = GetCertificatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCertificatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificatesResponse) ProtoMessage() {}
+
+func (x *GetCertificatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*GetCertificatesResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetCertificatesResponse) GetMapSize() uint64 {
+	if x != nil {
+		return x.MapSize
+	}
+	return 0
+}
+
+func (x *GetCertificatesResponse) GetCertificates() []*CertificateEntry {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+type GetEntryAndProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName     string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	Index          uint64 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	DomainTreeSize uint64 `protobuf:"varint,3,opt,name=domain_tree_size,json=domainTreeSize,proto3" json:"domain_tree_size,omitempty"`
+}
+
+func (x *GetEntryAndProofRequest) Reset() {
+	*x = GetEntryAndProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEntryAndProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEntryAndProofRequest) ProtoMessage() {}
+
+func (x *GetEntryAndProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEntryAndProofRequest.ProtoReflect.Descriptor instead.
+func (*GetEntryAndProofRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetEntryAndProofRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetEntryAndProofRequest) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *GetEntryAndProofRequest) GetDomainTreeSize() uint64 {
+	if x != nil {
+		return x.DomainTreeSize
+	}
+	return 0
+}
+
+type GetEntryAndProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entry                  *DomainTreeEntry `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	LeafHash               []byte           `protobuf:"bytes,2,opt,name=leaf_hash,json=leafHash,proto3" json:"leaf_hash,omitempty"`
+	CertificateFingerprint []byte           `protobuf:"bytes,3,opt,name=certificate_fingerprint,json=certificateFingerprint,proto3" json:"certificate_fingerprint,omitempty"`
+	AuditPath              [][]byte         `protobuf:"bytes,4,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+}
+
+func (x *GetEntryAndProofResponse) Reset() {
+	*x = GetEntryAndProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetEntryAndProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEntryAndProofResponse) ProtoMessage() {}
+
+func (x *GetEntryAndProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEntryAndProofResponse.ProtoReflect.Descriptor instead.
+func (*GetEntryAndProofResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetEntryAndProofResponse) GetEntry() *DomainTreeEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *GetEntryAndProofResponse) GetLeafHash() []byte {
+	if x != nil {
+		return x.LeafHash
+	}
+	return nil
+}
+
+func (x *GetEntryAndProofResponse) GetCertificateFingerprint() []byte {
+	if x != nil {
+		return x.CertificateFingerprint
+	}
+	return nil
+}
+
+func (x *GetEntryAndProofResponse) GetAuditPath() [][]byte {
+	if x != nil {
+		return x.AuditPath
+	}
+	return nil
+}
+
+type GetDomainTreeIndexRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName       string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	LogIndex         uint64 `protobuf:"varint,2,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	CertificateIndex uint64 `protobuf:"varint,3,opt,name=certificate_index,json=certificateIndex,proto3" json:"certificate_index,omitempty"`
+}
+
+func (x *GetDomainTreeIndexRequest) Reset() {
+	*x = GetDomainTreeIndexRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainTreeIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainTreeIndexRequest) ProtoMessage() {}
+
+func (x *GetDomainTreeIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainTreeIndexRequest.ProtoReflect.Descriptor instead.
+func (*GetDomainTreeIndexRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetDomainTreeIndexRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetDomainTreeIndexRequest) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *GetDomainTreeIndexRequest) GetCertificateIndex() uint64 {
+	if x != nil {
+		return x.CertificateIndex
+	}
+	return 0
+}
+
+type GetDomainTreeIndexResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainTreeIndex uint64 `protobuf:"varint,1,opt,name=domain_tree_index,json=domainTreeIndex,proto3" json:"domain_tree_index,omitempty"`
+}
+
+func (x *GetDomainTreeIndexResponse) Reset() {
+	*x = GetDomainTreeIndexResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDomainTreeIndexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDomainTreeIndexResponse) ProtoMessage() {}
+
+func (x *GetDomainTreeIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDomainTreeIndexResponse.ProtoReflect.Descriptor instead.
+func (*GetDomainTreeIndexResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetDomainTreeIndexResponse) GetDomainTreeIndex() uint64 {
+	if x != nil {
+		return x.DomainTreeIndex
+	}
+	return 0
+}
+
+type GetSourceLogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start uint64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   uint64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *GetSourceLogsRequest) Reset() {
+	*x = GetSourceLogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSourceLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSourceLogsRequest) ProtoMessage() {}
+
+func (x *GetSourceLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSourceLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetSourceLogsRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetSourceLogsRequest) GetStart() uint64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *GetSourceLogsRequest) GetEnd() uint64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type GetSourceLogsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogIds [][]byte `protobuf:"bytes,1,rep,name=log_ids,json=logIds,proto3" json:"log_ids,omitempty"`
+}
+
+func (x *GetSourceLogsResponse) Reset() {
+	*x = GetSourceLogsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSourceLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSourceLogsResponse) ProtoMessage() {}
+
+func (x *GetSourceLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSourceLogsResponse.ProtoReflect.Descriptor instead.
+func (*GetSourceLogsResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetSourceLogsResponse) GetLogIds() [][]byte {
+	if x != nil {
+		return x.LogIds
+	}
+	return nil
+}
+
+type GetSourceLogAndProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index          uint64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	SourceTreeSize uint64 `protobuf:"varint,2,opt,name=source_tree_size,json=sourceTreeSize,proto3" json:"source_tree_size,omitempty"`
+}
+
+func (x *GetSourceLogAndProofRequest) Reset() {
+	*x = GetSourceLogAndProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSourceLogAndProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSourceLogAndProofRequest) ProtoMessage() {}
+
+func (x *GetSourceLogAndProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSourceLogAndProofRequest.ProtoReflect.Descriptor instead.
+func (*GetSourceLogAndProofRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetSourceLogAndProofRequest) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *GetSourceLogAndProofRequest) GetSourceTreeSize() uint64 {
+	if x != nil {
+		return x.SourceTreeSize
+	}
+	return 0
+}
+
+type GetSourceLogAndProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogId     []byte   `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	AuditPath [][]byte `protobuf:"bytes,2,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+}
+
+func (x *GetSourceLogAndProofResponse) Reset() {
+	*x = GetSourceLogAndProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSourceLogAndProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSourceLogAndProofResponse) ProtoMessage() {}
+
+func (x *GetSourceLogAndProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSourceLogAndProofResponse.ProtoReflect.Descriptor instead.
+func (*GetSourceLogAndProofResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetSourceLogAndProofResponse) GetLogId() []byte {
+	if x != nil {
+		return x.LogId
+	}
+	return nil
+}
+
+func (x *GetSourceLogAndProofResponse) GetAuditPath() [][]byte {
+	if x != nil {
+		return x.AuditPath
+	}
+	return nil
+}
+
+type GetSourceConsistencyProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	First  uint64 `protobuf:"varint,1,opt,name=first,proto3" json:"first,omitempty"`
+	Second uint64 `protobuf:"varint,2,opt,name=second,proto3" json:"second,omitempty"`
+}
+
+func (x *GetSourceConsistencyProofRequest) Reset() {
+	*x = GetSourceConsistencyProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSourceConsistencyProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSourceConsistencyProofRequest) ProtoMessage() {}
+
+func (x *GetSourceConsistencyProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSourceConsistencyProofRequest.ProtoReflect.Descriptor instead.
+func (*GetSourceConsistencyProofRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetSourceConsistencyProofRequest) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *GetSourceConsistencyProofRequest) GetSecond() uint64 {
+	if x != nil {
+		return x.Second
+	}
+	return 0
+}
+
+type GetSourceConsistencyProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Proof [][]byte `protobuf:"bytes,1,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *GetSourceConsistencyProofResponse) Reset() {
+	*x = GetSourceConsistencyProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSourceConsistencyProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSourceConsistencyProofResponse) ProtoMessage() {}
+
+func (x *GetSourceConsistencyProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSourceConsistencyProofResponse.ProtoReflect.Descriptor instead.
+func (*GetSourceConsistencyProofResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetSourceConsistencyProofResponse) GetProof() [][]byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type GetInclusionPromiseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DomainName       string `protobuf:"bytes,1,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	LogIndex         uint64 `protobuf:"varint,2,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	CertificateIndex uint64 `protobuf:"varint,3,opt,name=certificate_index,json=certificateIndex,proto3" json:"certificate_index,omitempty"`
+}
+
+func (x *GetInclusionPromiseRequest) Reset() {
+	*x = GetInclusionPromiseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInclusionPromiseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInclusionPromiseRequest) ProtoMessage() {}
+
+func (x *GetInclusionPromiseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInclusionPromiseRequest.ProtoReflect.Descriptor instead.
+func (*GetInclusionPromiseRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetInclusionPromiseRequest) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *GetInclusionPromiseRequest) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *GetInclusionPromiseRequest) GetCertificateIndex() uint64 {
+	if x != nil {
+		return x.CertificateIndex
+	}
+	return 0
+}
+
+type GetInclusionPromiseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp            uint64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	LogIndex             uint64 `protobuf:"varint,2,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	CertificateIndex     uint64 `protobuf:"varint,3,opt,name=certificate_index,json=certificateIndex,proto3" json:"certificate_index,omitempty"`
+	NormalizedDomainName string `protobuf:"bytes,4,opt,name=normalized_domain_name,json=normalizedDomainName,proto3" json:"normalized_domain_name,omitempty"`
+	PromiseSignature     []byte `protobuf:"bytes,5,opt,name=promise_signature,json=promiseSignature,proto3" json:"promise_signature,omitempty"`
+}
+
+func (x *GetInclusionPromiseResponse) Reset() {
+	*x = GetInclusionPromiseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInclusionPromiseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInclusionPromiseResponse) ProtoMessage() {}
+
+func (x *GetInclusionPromiseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInclusionPromiseResponse.ProtoReflect.Descriptor instead.
+func (*GetInclusionPromiseResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetInclusionPromiseResponse) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *GetInclusionPromiseResponse) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+func (x *GetInclusionPromiseResponse) GetCertificateIndex() uint64 {
+	if x != nil {
+		return x.CertificateIndex
+	}
+	return 0
+}
+
+func (x *GetInclusionPromiseResponse) GetNormalizedDomainName() string {
+	if x != nil {
+		return x.NormalizedDomainName
+	}
+	return ""
+}
+
+func (x *GetInclusionPromiseResponse) GetPromiseSignature() []byte {
+	if x != nil {
+		return x.PromiseSignature
+	}
+	return nil
+}
+
+type AddCosignatureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MapSize        uint64 `protobuf:"varint,1,opt,name=map_size,json=mapSize,proto3" json:"map_size,omitempty"`
+	WitnessKeyHash []byte `protobuf:"bytes,2,opt,name=witness_key_hash,json=witnessKeyHash,proto3" json:"witness_key_hash,omitempty"`
+	Timestamp      uint64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature      []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *AddCosignatureRequest) Reset() {
+	*x = AddCosignatureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddCosignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCosignatureRequest) ProtoMessage() {}
+
+func (x *AddCosignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCosignatureRequest.ProtoReflect.Descriptor instead.
+func (*AddCosignatureRequest) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AddCosignatureRequest) GetMapSize() uint64 {
+	if x != nil {
+		return x.MapSize
+	}
+	return 0
+}
+
+func (x *AddCosignatureRequest) GetWitnessKeyHash() []byte {
+	if x != nil {
+		return x.WitnessKeyHash
+	}
+	return nil
+}
+
+func (x *AddCosignatureRequest) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AddCosignatureRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type AddCosignatureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddCosignatureResponse) Reset() {
+	*x = AddCosignatureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dt_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddCosignatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCosignatureResponse) ProtoMessage() {}
+
+func (x *AddCosignatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dt_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCosignatureResponse.ProtoReflect.Descriptor instead.
+func (*AddCosignatureResponse) Descriptor() ([]byte, []int) {
+	return file_dt_proto_rawDescGZIP(), []int{45}
+}
+
+var File_dt_proto protoreflect.FileDescriptor
+
+var file_dt_proto_rawDesc = []byte{
+	0x0a, 0x08, 0x64, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x64, 0x74, 0x2e, 0x76,
+	0x31, 0x22, 0x47, 0x0a, 0x0b, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x08, 0x74, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0xad, 0x02, 0x0a, 0x0d, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x4d, 0x61, 0x70, 0x48, 0x65, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x70, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x6d, 0x61, 0x70, 0x52, 0x6f, 0x6f, 0x74, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x31, 0x0a, 0x15, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x74, 0x72,
+	0x65, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x12, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x72, 0x65, 0x65, 0x52, 0x6f,
+	0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x44, 0x0a, 0x14, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67,
+	0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x12, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x4c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x12,
+	0x6d, 0x61, 0x70, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x6d, 0x61, 0x70, 0x48, 0x65, 0x61,
+	0x64, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x7a, 0x0a, 0x12, 0x4d, 0x61,
+	0x70, 0x48, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x12, 0x28, 0x0a, 0x10, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x77, 0x69, 0x74, 0x6e,
+	0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x60, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x07, 0x6d, 0x61, 0x70,
+	0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x63,
+	0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x6d, 0x61, 0x70, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x77, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x53,
+	0x4d, 0x48, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x73, 0x6d,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x4d, 0x61, 0x70, 0x48, 0x65, 0x61, 0x64, 0x52, 0x03, 0x73,
+	0x6d, 0x68, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x4d, 0x61, 0x70, 0x48, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x52, 0x0c, 0x63, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x22, 0x3c, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22,
+	0x3f, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x73, 0x6d, 0x68, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x4d, 0x61, 0x70, 0x48, 0x65, 0x61, 0x64, 0x52, 0x04, 0x73, 0x6d, 0x68, 0x73,
+	0x22, 0x2f, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x41, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x22, 0x16, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x37, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x22, 0x67, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52,
+	0x6f, 0x6f, 0x74, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6d, 0x61,
+	0x70, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x4d, 0x61, 0x70, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x8a, 0x02, 0x0a, 0x1d,
+	0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x41, 0x6e, 0x64,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x10, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54,
+	0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x31, 0x0a, 0x15, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72,
+	0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x34, 0x0a, 0x16, 0x6e, 0x6f,
+	0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x6e, 0x6f, 0x72, 0x6d,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64, 0x69, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x61, 0x75, 0x64, 0x69, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x37, 0x0a, 0x18, 0x6e, 0x6f, 0x6e, 0x5f, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69,
+	0x70, 0x5f, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x15, 0x6e, 0x6f, 0x6e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70,
+	0x4c, 0x65, 0x61, 0x66, 0x44, 0x61, 0x74, 0x61, 0x22, 0x6d, 0x0a, 0x22, 0x47, 0x65, 0x74, 0x44,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x26, 0x0a, 0x0f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6d, 0x61, 0x70, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x4d, 0x61, 0x70, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x85, 0x02, 0x0a, 0x16, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x49, 0x6e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x28, 0x0a, 0x10, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x74, 0x72, 0x65,
+	0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x31, 0x0a, 0x15,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x34, 0x0a, 0x16, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x14, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x64,
+	0x65, 0x70, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x37, 0x0a, 0x18, 0x6e, 0x6f, 0x6e, 0x5f, 0x6d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x5f, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x15, 0x6e, 0x6f, 0x6e, 0x4d, 0x65, 0x6d,
+	0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x4c, 0x65, 0x61, 0x66, 0x44, 0x61, 0x74, 0x61, 0x22,
+	0x7d, 0x0a, 0x23, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74,
+	0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x49, 0x6e, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x64, 0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x64, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x5e,
+	0x0a, 0x18, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0xb7,
+	0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x40, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x12, 0x42, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x52, 0x06, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x22, 0x7f, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x44,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0xae, 0x02, 0x0a, 0x11, 0x44, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2c, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6c, 0x65, 0x61, 0x66, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x37, 0x0a, 0x17, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f,
+	0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x16, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64,
+	0x69, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x61,
+	0x75, 0x64, 0x69, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x61, 0x66,
+	0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x65,
+	0x61, 0x66, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61,
+	0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74,
+	0x72, 0x61, 0x44, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x0d, 0x63, 0x74, 0x5f, 0x61, 0x75, 0x64,
+	0x69, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x63,
+	0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0x66, 0x0a, 0x11, 0x53, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x49, 0x6e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x15, 0x0a, 0x06,
+	0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6c, 0x6f,
+	0x67, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64, 0x69, 0x74, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x61, 0x75, 0x64, 0x69, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x22, 0xac, 0x02, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x27, 0x0a, 0x03, 0x73, 0x6d, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x64,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x03, 0x73, 0x6d, 0x68, 0x12, 0x4b, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e,
+	0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52,
+	0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c,
+	0x52, 0x10, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x32, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0b, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x5f, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x49, 0x6e, 0x42,
+	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x52, 0x0a, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67,
+	0x73, 0x22, 0x9c, 0x01, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x63,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x19, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x22, 0x85, 0x02, 0x0a, 0x14, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x16, 0x6e, 0x6f, 0x72,
+	0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x6e, 0x6f, 0x72, 0x6d, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2c, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6c, 0x65, 0x61, 0x66, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x37, 0x0a, 0x17, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f,
+	0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x16, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64,
+	0x69, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x61,
+	0x75, 0x64, 0x69, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0xc4, 0x01, 0x0a, 0x20, 0x47, 0x65, 0x74,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x12, 0x3b, 0x0a, 0x0a, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x4d, 0x0a, 0x0c, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64,
+	0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x52, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x22,
+	0x6b, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x22, 0x33, 0x0a, 0x1b,
+	0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x22, 0x5c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22,
+	0x5b, 0x0a, 0x0f, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x2b, 0x0a, 0x11, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x63, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0xa2, 0x01, 0x0a,
+	0x12, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x6c, 0x65, 0x61, 0x66,
+	0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x18, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x17, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x73, 0x22, 0x61, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x03, 0x65, 0x6e, 0x64, 0x22, 0xa2, 0x01, 0x0a, 0x10, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x65, 0x6e, 0x74,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x61, 0x66, 0x5f,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x65, 0x61,
+	0x66, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72,
+	0x61, 0x44, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x0d, 0x63, 0x74, 0x5f, 0x61, 0x75, 0x64, 0x69,
+	0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x74,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0x71, 0x0a, 0x17, 0x47, 0x65, 0x74,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x61, 0x70, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x3b, 0x0a, 0x0c, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c,
+	0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x22, 0x7a, 0x0a, 0x17,
+	0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x28,
+	0x0a, 0x10, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x54, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0xbd, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6c, 0x65, 0x61, 0x66, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x37, 0x0a, 0x17, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f,
+	0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x16, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64,
+	0x69, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x61,
+	0x75, 0x64, 0x69, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0x86, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x10, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x22, 0x48, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72,
+	0x65, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x3e, 0x0a, 0x14, 0x47,
+	0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x30, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x6c, 0x6f, 0x67, 0x49, 0x64, 0x73, 0x22, 0x5d, 0x0a,
+	0x1b, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x41, 0x6e, 0x64,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x74, 0x72, 0x65,
+	0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x54, 0x72, 0x65, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x54, 0x0a, 0x1c,
+	0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x41, 0x6e, 0x64, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15, 0x0a, 0x06,
+	0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6c, 0x6f,
+	0x67, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x64, 0x69, 0x74, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x61, 0x75, 0x64, 0x69, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x22, 0x50, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43,
+	0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x22, 0x39, 0x0a, 0x21, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x22,
+	0x87, 0x01, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e,
+	0x50, 0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x2b, 0x0a, 0x11,
+	0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0xe8, 0x01, 0x0a, 0x1b, 0x47, 0x65,
+	0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6d, 0x69, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x10, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x34, 0x0a, 0x16, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x14, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x70, 0x72, 0x6f, 0x6d, 0x69,
+	0x73, 0x65, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x15, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x6d, 0x61, 0x70, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x6d, 0x61, 0x70, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x69, 0x74,
+	0x6e, 0x65, 0x73, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0e, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22,
+	0x18, 0x0a, 0x16, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xbe, 0x0d, 0x0a, 0x12, 0x44, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x35, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x12, 0x14, 0x2e, 0x64, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x15, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x53, 0x4d,
+	0x48, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x19, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x53, 0x4d, 0x48, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a,
+	0x08, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x41, 0x74, 0x12, 0x16, 0x2e, 0x64, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x41, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x15, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x4d, 0x48,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1b, 0x2e, 0x64, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x23, 0x2e,
+	0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52,
+	0x6f, 0x6f, 0x74, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x24, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x74, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x44,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x29, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e,
+	0x64, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6f, 0x74, 0x73, 0x41, 0x6e, 0x64, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56,
+	0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x73, 0x12, 0x1f, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x64, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x64, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6b, 0x0a, 0x18, 0x47,
+	0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61,
+	0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x26, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x6c,
+	0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x27, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12,
+	0x21, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x73, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f,
+	0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x64,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x64, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x10, 0x47,
+	0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12,
+	0x1e, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1f, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x59, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65,
+	0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x20, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x54, 0x72, 0x65, 0x65, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x47,
+	0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x1b, 0x2e, 0x64,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x53, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12,
+	0x22, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x4c, 0x6f, 0x67, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x41, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6e, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x27, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28,
+	0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x12,
+	0x21, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75,
+	0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6d, 0x69, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x64, 0x64, 0x43, 0x6f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53,
+	0x4d, 0x48, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x19, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x53, 0x4d, 0x48, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x64, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x4d, 0x61, 0x70, 0x48, 0x65, 0x61, 0x64, 0x30, 0x01, 0x42, 0x5e, 0x5a, 0x5c, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x61, 0x72, 0x63, 0x2d, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x2d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x2d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x2f, 0x6c, 0x6f, 0x67, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2f, 0x64, 0x74, 0x2d, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x2f, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x64, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_dt_proto_rawDescOnce sync.Once
+	file_dt_proto_rawDescData = file_dt_proto_rawDesc
+)
+
+func file_dt_proto_rawDescGZIP() []byte {
+	file_dt_proto_rawDescOnce.Do(func() {
+		file_dt_proto_rawDescData = protoimpl.X.CompressGZIP(file_dt_proto_rawDescData)
+	})
+	return file_dt_proto_rawDescData
+}
+
+var file_dt_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
+var file_dt_proto_goTypes = []interface{}{
+	(*LogRevision)(nil),                         // 0: dt.v1.LogRevision
+	(*SignedMapHead)(nil),                       // 1: dt.v1.SignedMapHead
+	(*MapHeadCosignature)(nil),                  // 2: dt.v1.MapHeadCosignature
+	(*GetSMHRequest)(nil),                       // 3: dt.v1.GetSMHRequest
+	(*GetSMHResponse)(nil),                      // 4: dt.v1.GetSMHResponse
+	(*GetSMHRangeRequest)(nil),                  // 5: dt.v1.GetSMHRangeRequest
+	(*GetSMHRangeResponse)(nil),                 // 6: dt.v1.GetSMHRangeResponse
+	(*GetSMHAtRequest)(nil),                     // 7: dt.v1.GetSMHAtRequest
+	(*GetCheckpointRequest)(nil),                // 8: dt.v1.GetCheckpointRequest
+	(*GetCheckpointResponse)(nil),               // 9: dt.v1.GetCheckpointResponse
+	(*GetDomainRootAndProofRequest)(nil),        // 10: dt.v1.GetDomainRootAndProofRequest
+	(*GetDomainRootAndProofResponse)(nil),       // 11: dt.v1.GetDomainRootAndProofResponse
+	(*GetDomainRootsAndMultiProofRequest)(nil),  // 12: dt.v1.GetDomainRootsAndMultiProofRequest
+	(*DomainRootInMultiProof)(nil),              // 13: dt.v1.DomainRootInMultiProof
+	(*GetDomainRootsAndMultiProofResponse)(nil), // 14: dt.v1.GetDomainRootsAndMultiProofResponse
+	(*GetChangedDomainsRequest)(nil),            // 15: dt.v1.GetChangedDomainsRequest
+	(*GetChangedDomainsResponse)(nil),           // 16: dt.v1.GetChangedDomainsResponse
+	(*GetDomainUpdatesRequest)(nil),             // 17: dt.v1.GetDomainUpdatesRequest
+	(*DomainUpdateEntry)(nil),                   // 18: dt.v1.DomainUpdateEntry
+	(*SourceLogInBundle)(nil),                   // 19: dt.v1.SourceLogInBundle
+	(*GetDomainUpdatesResponse)(nil),            // 20: dt.v1.GetDomainUpdatesResponse
+	(*GetCertificatePlacementsRequest)(nil),     // 21: dt.v1.GetCertificatePlacementsRequest
+	(*CertificatePlacement)(nil),                // 22: dt.v1.CertificatePlacement
+	(*GetCertificatePlacementsResponse)(nil),    // 23: dt.v1.GetCertificatePlacementsResponse
+	(*GetConsistencyProofRequest)(nil),          // 24: dt.v1.GetConsistencyProofRequest
+	(*GetConsistencyProofResponse)(nil),         // 25: dt.v1.GetConsistencyProofResponse
+	(*GetEntriesRequest)(nil),                   // 26: dt.v1.GetEntriesRequest
+	(*DomainTreeEntry)(nil),                     // 27: dt.v1.DomainTreeEntry
+	(*GetEntriesResponse)(nil),                  // 28: dt.v1.GetEntriesResponse
+	(*GetCertificatesRequest)(nil),              // 29: dt.v1.GetCertificatesRequest
+	(*CertificateEntry)(nil),                    // 30: dt.v1.CertificateEntry
+	(*GetCertificatesResponse)(nil),             // 31: dt.v1.GetCertificatesResponse
+	(*GetEntryAndProofRequest)(nil),             // 32: dt.v1.GetEntryAndProofRequest
+	(*GetEntryAndProofResponse)(nil),            // 33: dt.v1.GetEntryAndProofResponse
+	(*GetDomainTreeIndexRequest)(nil),           // 34: dt.v1.GetDomainTreeIndexRequest
+	(*GetDomainTreeIndexResponse)(nil),          // 35: dt.v1.GetDomainTreeIndexResponse
+	(*GetSourceLogsRequest)(nil),                // 36: dt.v1.GetSourceLogsRequest
+	(*GetSourceLogsResponse)(nil),               // 37: dt.v1.GetSourceLogsResponse
+	(*GetSourceLogAndProofRequest)(nil),         // 38: dt.v1.GetSourceLogAndProofRequest
+	(*GetSourceLogAndProofResponse)(nil),        // 39: dt.v1.GetSourceLogAndProofResponse
+	(*GetSourceConsistencyProofRequest)(nil),    // 40: dt.v1.GetSourceConsistencyProofRequest
+	(*GetSourceConsistencyProofResponse)(nil),   // 41: dt.v1.GetSourceConsistencyProofResponse
+	(*GetInclusionPromiseRequest)(nil),          // 42: dt.v1.GetInclusionPromiseRequest
+	(*GetInclusionPromiseResponse)(nil),         // 43: dt.v1.GetInclusionPromiseResponse
+	(*AddCosignatureRequest)(nil),               // 44: dt.v1.AddCosignatureRequest
+	(*AddCosignatureResponse)(nil),              // 45: dt.v1.AddCosignatureResponse
+}
+var file_dt_proto_depIdxs = []int32{
+	0,  // 0: dt.v1.SignedMapHead.source_log_revisions:type_name -> dt.v1.LogRevision
+	1,  // 1: dt.v1.GetSMHResponse.smh:type_name -> dt.v1.SignedMapHead
+	2,  // 2: dt.v1.GetSMHResponse.cosignatures:type_name -> dt.v1.MapHeadCosignature
+	1,  // 3: dt.v1.GetSMHRangeResponse.smhs:type_name -> dt.v1.SignedMapHead
+	13, // 4: dt.v1.GetDomainRootsAndMultiProofResponse.domains:type_name -> dt.v1.DomainRootInMultiProof
+	14, // 5: dt.v1.GetChangedDomainsResponse.first:type_name -> dt.v1.GetDomainRootsAndMultiProofResponse
+	14, // 6: dt.v1.GetChangedDomainsResponse.second:type_name -> dt.v1.GetDomainRootsAndMultiProofResponse
+	27, // 7: dt.v1.DomainUpdateEntry.entry:type_name -> dt.v1.DomainTreeEntry
+	4,  // 8: dt.v1.GetDomainUpdatesResponse.smh:type_name -> dt.v1.GetSMHResponse
+	14, // 9: dt.v1.GetDomainUpdatesResponse.domain_root:type_name -> dt.v1.GetDomainRootsAndMultiProofResponse
+	18, // 10: dt.v1.GetDomainUpdatesResponse.entries:type_name -> dt.v1.DomainUpdateEntry
+	19, // 11: dt.v1.GetDomainUpdatesResponse.source_logs:type_name -> dt.v1.SourceLogInBundle
+	27, // 12: dt.v1.CertificatePlacement.entry:type_name -> dt.v1.DomainTreeEntry
+	22, // 13: dt.v1.GetCertificatePlacementsResponse.placements:type_name -> dt.v1.CertificatePlacement
+	14, // 14: dt.v1.GetCertificatePlacementsResponse.domain_roots:type_name -> dt.v1.GetDomainRootsAndMultiProofResponse
+	27, // 15: dt.v1.GetEntriesResponse.entries:type_name -> dt.v1.DomainTreeEntry
+	27, // 16: dt.v1.CertificateEntry.entry:type_name -> dt.v1.DomainTreeEntry
+	30, // 17: dt.v1.GetCertificatesResponse.certificates:type_name -> dt.v1.CertificateEntry
+	27, // 18: dt.v1.GetEntryAndProofResponse.entry:type_name -> dt.v1.DomainTreeEntry
+	3,  // 19: dt.v1.DomainTransparency.GetSMH:input_type -> dt.v1.GetSMHRequest
+	5,  // 20: dt.v1.DomainTransparency.GetSMHRange:input_type -> dt.v1.GetSMHRangeRequest
+	7,  // 21: dt.v1.DomainTransparency.GetSMHAt:input_type -> dt.v1.GetSMHAtRequest
+	8,  // 22: dt.v1.DomainTransparency.GetCheckpoint:input_type -> dt.v1.GetCheckpointRequest
+	10, // 23: dt.v1.DomainTransparency.GetDomainRootAndProof:input_type -> dt.v1.GetDomainRootAndProofRequest
+	12, // 24: dt.v1.DomainTransparency.GetDomainRootsAndMultiProof:input_type -> dt.v1.GetDomainRootsAndMultiProofRequest
+	15, // 25: dt.v1.DomainTransparency.GetChangedDomains:input_type -> dt.v1.GetChangedDomainsRequest
+	17, // 26: dt.v1.DomainTransparency.GetDomainUpdates:input_type -> dt.v1.GetDomainUpdatesRequest
+	21, // 27: dt.v1.DomainTransparency.GetCertificatePlacements:input_type -> dt.v1.GetCertificatePlacementsRequest
+	24, // 28: dt.v1.DomainTransparency.GetConsistencyProof:input_type -> dt.v1.GetConsistencyProofRequest
+	26, // 29: dt.v1.DomainTransparency.GetEntries:input_type -> dt.v1.GetEntriesRequest
+	29, // 30: dt.v1.DomainTransparency.GetCertificates:input_type -> dt.v1.GetCertificatesRequest
+	32, // 31: dt.v1.DomainTransparency.GetEntryAndProof:input_type -> dt.v1.GetEntryAndProofRequest
+	34, // 32: dt.v1.DomainTransparency.GetDomainTreeIndex:input_type -> dt.v1.GetDomainTreeIndexRequest
+	36, // 33: dt.v1.DomainTransparency.GetSourceLogs:input_type -> dt.v1.GetSourceLogsRequest
+	38, // 34: dt.v1.DomainTransparency.GetSourceLogAndProof:input_type -> dt.v1.GetSourceLogAndProofRequest
+	40, // 35: dt.v1.DomainTransparency.GetSourceConsistencyProof:input_type -> dt.v1.GetSourceConsistencyProofRequest
+	42, // 36: dt.v1.DomainTransparency.GetInclusionPromise:input_type -> dt.v1.GetInclusionPromiseRequest
+	44, // 37: dt.v1.DomainTransparency.AddCosignature:input_type -> dt.v1.AddCosignatureRequest
+	5,  // 38: dt.v1.DomainTransparency.StreamSMHRange:input_type -> dt.v1.GetSMHRangeRequest
+	4,  // 39: dt.v1.DomainTransparency.GetSMH:output_type -> dt.v1.GetSMHResponse
+	6,  // 40: dt.v1.DomainTransparency.GetSMHRange:output_type -> dt.v1.GetSMHRangeResponse
+	4,  // 41: dt.v1.DomainTransparency.GetSMHAt:output_type -> dt.v1.GetSMHResponse
+	9,  // 42: dt.v1.DomainTransparency.GetCheckpoint:output_type -> dt.v1.GetCheckpointResponse
+	11, // 43: dt.v1.DomainTransparency.GetDomainRootAndProof:output_type -> dt.v1.GetDomainRootAndProofResponse
+	14, // 44: dt.v1.DomainTransparency.GetDomainRootsAndMultiProof:output_type -> dt.v1.GetDomainRootsAndMultiProofResponse
+	16, // 45: dt.v1.DomainTransparency.GetChangedDomains:output_type -> dt.v1.GetChangedDomainsResponse
+	20, // 46: dt.v1.DomainTransparency.GetDomainUpdates:output_type -> dt.v1.GetDomainUpdatesResponse
+	23, // 47: dt.v1.DomainTransparency.GetCertificatePlacements:output_type -> dt.v1.GetCertificatePlacementsResponse
+	25, // 48: dt.v1.DomainTransparency.GetConsistencyProof:output_type -> dt.v1.GetConsistencyProofResponse
+	28, // 49: dt.v1.DomainTransparency.GetEntries:output_type -> dt.v1.GetEntriesResponse
+	31, // 50: dt.v1.DomainTransparency.GetCertificates:output_type -> dt.v1.GetCertificatesResponse
+	33, // 51: dt.v1.DomainTransparency.GetEntryAndProof:output_type -> dt.v1.GetEntryAndProofResponse
+	35, // 52: dt.v1.DomainTransparency.GetDomainTreeIndex:output_type -> dt.v1.GetDomainTreeIndexResponse
+	37, // 53: dt.v1.DomainTransparency.GetSourceLogs:output_type -> dt.v1.GetSourceLogsResponse
+	39, // 54: dt.v1.DomainTransparency.GetSourceLogAndProof:output_type -> dt.v1.GetSourceLogAndProofResponse
+	41, // 55: dt.v1.DomainTransparency.GetSourceConsistencyProof:output_type -> dt.v1.GetSourceConsistencyProofResponse
+	43, // 56: dt.v1.DomainTransparency.GetInclusionPromise:output_type -> dt.v1.GetInclusionPromiseResponse
+	45, // 57: dt.v1.DomainTransparency.AddCosignature:output_type -> dt.v1.AddCosignatureResponse
+	1,  // 58: dt.v1.DomainTransparency.StreamSMHRange:output_type -> dt.v1.SignedMapHead
+	39, // [39:59] is the sub-list for method output_type
+	19, // [19:39] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_dt_proto_init() }
+func file_dt_proto_init() {
+	if File_dt_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_dt_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogRevision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignedMapHead); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapHeadCosignature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSMHRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSMHResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSMHRangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSMHRangeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSMHAtRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCheckpointRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCheckpointResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainRootAndProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainRootAndProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainRootsAndMultiProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DomainRootInMultiProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainRootsAndMultiProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetChangedDomainsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetChangedDomainsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainUpdatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DomainUpdateEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SourceLogInBundle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainUpdatesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCertificatePlacementsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CertificatePlacement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCertificatePlacementsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConsistencyProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConsistencyProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEntriesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DomainTreeEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEntriesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCertificatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CertificateEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCertificatesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEntryAndProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetEntryAndProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainTreeIndexRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDomainTreeIndexResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSourceLogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSourceLogsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSourceLogAndProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSourceLogAndProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSourceConsistencyProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSourceConsistencyProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInclusionPromiseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInclusionPromiseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCosignatureRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dt_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCosignatureResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_dt_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_dt_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   46,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dt_proto_goTypes,
+		DependencyIndexes: file_dt_proto_depIdxs,
+		MessageInfos:      file_dt_proto_msgTypes,
+	}.Build()
+	File_dt_proto = out.File
+	file_dt_proto_rawDesc = nil
+	file_dt_proto_goTypes = nil
+	file_dt_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// DomainTransparencyClient is the client API for DomainTransparency service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DomainTransparencyClient interface {
+	GetSMH(ctx context.Context, in *GetSMHRequest, opts ...grpc.CallOption) (*GetSMHResponse, error)
+	GetSMHRange(ctx context.Context, in *GetSMHRangeRequest, opts ...grpc.CallOption) (*GetSMHRangeResponse, error)
+	GetSMHAt(ctx context.Context, in *GetSMHAtRequest, opts ...grpc.CallOption) (*GetSMHResponse, error)
+	GetCheckpoint(ctx context.Context, in *GetCheckpointRequest, opts ...grpc.CallOption) (*GetCheckpointResponse, error)
+	GetDomainRootAndProof(ctx context.Context, in *GetDomainRootAndProofRequest, opts ...grpc.CallOption) (*GetDomainRootAndProofResponse, error)
+	GetDomainRootsAndMultiProof(ctx context.Context, in *GetDomainRootsAndMultiProofRequest, opts ...grpc.CallOption) (*GetDomainRootsAndMultiProofResponse, error)
+	GetChangedDomains(ctx context.Context, in *GetChangedDomainsRequest, opts ...grpc.CallOption) (*GetChangedDomainsResponse, error)
+	GetDomainUpdates(ctx context.Context, in *GetDomainUpdatesRequest, opts ...grpc.CallOption) (*GetDomainUpdatesResponse, error)
+	GetCertificatePlacements(ctx context.Context, in *GetCertificatePlacementsRequest, opts ...grpc.CallOption) (*GetCertificatePlacementsResponse, error)
+	GetConsistencyProof(ctx context.Context, in *GetConsistencyProofRequest, opts ...grpc.CallOption) (*GetConsistencyProofResponse, error)
+	GetEntries(ctx context.Context, in *GetEntriesRequest, opts ...grpc.CallOption) (*GetEntriesResponse, error)
+	GetCertificates(ctx context.Context, in *GetCertificatesRequest, opts ...grpc.CallOption) (*GetCertificatesResponse, error)
+	GetEntryAndProof(ctx context.Context, in *GetEntryAndProofRequest, opts ...grpc.CallOption) (*GetEntryAndProofResponse, error)
+	GetDomainTreeIndex(ctx context.Context, in *GetDomainTreeIndexRequest, opts ...grpc.CallOption) (*GetDomainTreeIndexResponse, error)
+	GetSourceLogs(ctx context.Context, in *GetSourceLogsRequest, opts ...grpc.CallOption) (*GetSourceLogsResponse, error)
+	GetSourceLogAndProof(ctx context.Context, in *GetSourceLogAndProofRequest, opts ...grpc.CallOption) (*GetSourceLogAndProofResponse, error)
+	GetSourceConsistencyProof(ctx context.Context, in *GetSourceConsistencyProofRequest, opts ...grpc.CallOption) (*GetSourceConsistencyProofResponse, error)
+	GetInclusionPromise(ctx context.Context, in *GetInclusionPromiseRequest, opts ...grpc.CallOption) (*GetInclusionPromiseResponse, error)
+	AddCosignature(ctx context.Context, in *AddCosignatureRequest, opts ...grpc.CallOption) (*AddCosignatureResponse, error)
+	// Streams every SMH with map size in [start, end], in increasing order of map size,
+	// without the paging limit of GetSMHRange.
+	StreamSMHRange(ctx context.Context, in *GetSMHRangeRequest, opts ...grpc.CallOption) (DomainTransparency_StreamSMHRangeClient, error)
+}
+
+type domainTransparencyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDomainTransparencyClient(cc grpc.ClientConnInterface) DomainTransparencyClient {
+	return &domainTransparencyClient{cc}
+}
+
+func (c *domainTransparencyClient) GetSMH(ctx context.Context, in *GetSMHRequest, opts ...grpc.CallOption) (*GetSMHResponse, error) {
+	out := new(GetSMHResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetSMH", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetSMHRange(ctx context.Context, in *GetSMHRangeRequest, opts ...grpc.CallOption) (*GetSMHRangeResponse, error) {
+	out := new(GetSMHRangeResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetSMHRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetSMHAt(ctx context.Context, in *GetSMHAtRequest, opts ...grpc.CallOption) (*GetSMHResponse, error) {
+	out := new(GetSMHResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetSMHAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetCheckpoint(ctx context.Context, in *GetCheckpointRequest, opts ...grpc.CallOption) (*GetCheckpointResponse, error) {
+	out := new(GetCheckpointResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetCheckpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetDomainRootAndProof(ctx context.Context, in *GetDomainRootAndProofRequest, opts ...grpc.CallOption) (*GetDomainRootAndProofResponse, error) {
+	out := new(GetDomainRootAndProofResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetDomainRootAndProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetDomainRootsAndMultiProof(ctx context.Context, in *GetDomainRootsAndMultiProofRequest, opts ...grpc.CallOption) (*GetDomainRootsAndMultiProofResponse, error) {
+	out := new(GetDomainRootsAndMultiProofResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetDomainRootsAndMultiProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetChangedDomains(ctx context.Context, in *GetChangedDomainsRequest, opts ...grpc.CallOption) (*GetChangedDomainsResponse, error) {
+	out := new(GetChangedDomainsResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetChangedDomains", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetDomainUpdates(ctx context.Context, in *GetDomainUpdatesRequest, opts ...grpc.CallOption) (*GetDomainUpdatesResponse, error) {
+	out := new(GetDomainUpdatesResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetDomainUpdates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetCertificatePlacements(ctx context.Context, in *GetCertificatePlacementsRequest, opts ...grpc.CallOption) (*GetCertificatePlacementsResponse, error) {
+	out := new(GetCertificatePlacementsResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetCertificatePlacements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetConsistencyProof(ctx context.Context, in *GetConsistencyProofRequest, opts ...grpc.CallOption) (*GetConsistencyProofResponse, error) {
+	out := new(GetConsistencyProofResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetConsistencyProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetEntries(ctx context.Context, in *GetEntriesRequest, opts ...grpc.CallOption) (*GetEntriesResponse, error) {
+	out := new(GetEntriesResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetEntries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetCertificates(ctx context.Context, in *GetCertificatesRequest, opts ...grpc.CallOption) (*GetCertificatesResponse, error) {
+	out := new(GetCertificatesResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetCertificates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetEntryAndProof(ctx context.Context, in *GetEntryAndProofRequest, opts ...grpc.CallOption) (*GetEntryAndProofResponse, error) {
+	out := new(GetEntryAndProofResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetEntryAndProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetDomainTreeIndex(ctx context.Context, in *GetDomainTreeIndexRequest, opts ...grpc.CallOption) (*GetDomainTreeIndexResponse, error) {
+	out := new(GetDomainTreeIndexResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetDomainTreeIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetSourceLogs(ctx context.Context, in *GetSourceLogsRequest, opts ...grpc.CallOption) (*GetSourceLogsResponse, error) {
+	out := new(GetSourceLogsResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetSourceLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetSourceLogAndProof(ctx context.Context, in *GetSourceLogAndProofRequest, opts ...grpc.CallOption) (*GetSourceLogAndProofResponse, error) {
+	out := new(GetSourceLogAndProofResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetSourceLogAndProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetSourceConsistencyProof(ctx context.Context, in *GetSourceConsistencyProofRequest, opts ...grpc.CallOption) (*GetSourceConsistencyProofResponse, error) {
+	out := new(GetSourceConsistencyProofResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetSourceConsistencyProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) GetInclusionPromise(ctx context.Context, in *GetInclusionPromiseRequest, opts ...grpc.CallOption) (*GetInclusionPromiseResponse, error) {
+	out := new(GetInclusionPromiseResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/GetInclusionPromise", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) AddCosignature(ctx context.Context, in *AddCosignatureRequest, opts ...grpc.CallOption) (*AddCosignatureResponse, error) {
+	out := new(AddCosignatureResponse)
+	err := c.cc.Invoke(ctx, "/dt.v1.DomainTransparency/AddCosignature", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *domainTransparencyClient) StreamSMHRange(ctx context.Context, in *GetSMHRangeRequest, opts ...grpc.CallOption) (DomainTransparency_StreamSMHRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DomainTransparency_serviceDesc.Streams[0], "/dt.v1.DomainTransparency/StreamSMHRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &domainTransparencyStreamSMHRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DomainTransparency_StreamSMHRangeClient interface {
+	Recv() (*SignedMapHead, error)
+	grpc.ClientStream
+}
+
+type domainTransparencyStreamSMHRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *domainTransparencyStreamSMHRangeClient) Recv() (*SignedMapHead, error) {
+	m := new(SignedMapHead)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DomainTransparencyServer is the server API for DomainTransparency service.
+type DomainTransparencyServer interface {
+	GetSMH(context.Context, *GetSMHRequest) (*GetSMHResponse, error)
+	GetSMHRange(context.Context, *GetSMHRangeRequest) (*GetSMHRangeResponse, error)
+	GetSMHAt(context.Context, *GetSMHAtRequest) (*GetSMHResponse, error)
+	GetCheckpoint(context.Context, *GetCheckpointRequest) (*GetCheckpointResponse, error)
+	GetDomainRootAndProof(context.Context, *GetDomainRootAndProofRequest) (*GetDomainRootAndProofResponse, error)
+	GetDomainRootsAndMultiProof(context.Context, *GetDomainRootsAndMultiProofRequest) (*GetDomainRootsAndMultiProofResponse, error)
+	GetChangedDomains(context.Context, *GetChangedDomainsRequest) (*GetChangedDomainsResponse, error)
+	GetDomainUpdates(context.Context, *GetDomainUpdatesRequest) (*GetDomainUpdatesResponse, error)
+	GetCertificatePlacements(context.Context, *GetCertificatePlacementsRequest) (*GetCertificatePlacementsResponse, error)
+	GetConsistencyProof(context.Context, *GetConsistencyProofRequest) (*GetConsistencyProofResponse, error)
+	GetEntries(context.Context, *GetEntriesRequest) (*GetEntriesResponse, error)
+	GetCertificates(context.Context, *GetCertificatesRequest) (*GetCertificatesResponse, error)
+	GetEntryAndProof(context.Context, *GetEntryAndProofRequest) (*GetEntryAndProofResponse, error)
+	GetDomainTreeIndex(context.Context, *GetDomainTreeIndexRequest) (*GetDomainTreeIndexResponse, error)
+	GetSourceLogs(context.Context, *GetSourceLogsRequest) (*GetSourceLogsResponse, error)
+	GetSourceLogAndProof(context.Context, *GetSourceLogAndProofRequest) (*GetSourceLogAndProofResponse, error)
+	GetSourceConsistencyProof(context.Context, *GetSourceConsistencyProofRequest) (*GetSourceConsistencyProofResponse, error)
+	GetInclusionPromise(context.Context, *GetInclusionPromiseRequest) (*GetInclusionPromiseResponse, error)
+	AddCosignature(context.Context, *AddCosignatureRequest) (*AddCosignatureResponse, error)
+	// Streams every SMH with map size in [start, end], in increasing order of map size,
+	// without the paging limit of GetSMHRange.
+	StreamSMHRange(*GetSMHRangeRequest, DomainTransparency_StreamSMHRangeServer) error
+}
+
+// UnimplementedDomainTransparencyServer can be embedded to have forward compatible implementations.
+type UnimplementedDomainTransparencyServer struct {
+}
+
+func (*UnimplementedDomainTransparencyServer) GetSMH(context.Context, *GetSMHRequest) (*GetSMHResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSMH not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetSMHRange(context.Context, *GetSMHRangeRequest) (*GetSMHRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSMHRange not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetSMHAt(context.Context, *GetSMHAtRequest) (*GetSMHResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSMHAt not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetCheckpoint(context.Context, *GetCheckpointRequest) (*GetCheckpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCheckpoint not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetDomainRootAndProof(context.Context, *GetDomainRootAndProofRequest) (*GetDomainRootAndProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainRootAndProof not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetDomainRootsAndMultiProof(context.Context, *GetDomainRootsAndMultiProofRequest) (*GetDomainRootsAndMultiProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainRootsAndMultiProof not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetChangedDomains(context.Context, *GetChangedDomainsRequest) (*GetChangedDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChangedDomains not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetDomainUpdates(context.Context, *GetDomainUpdatesRequest) (*GetDomainUpdatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainUpdates not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetCertificatePlacements(context.Context, *GetCertificatePlacementsRequest) (*GetCertificatePlacementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCertificatePlacements not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetConsistencyProof(context.Context, *GetConsistencyProofRequest) (*GetConsistencyProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConsistencyProof not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetEntries(context.Context, *GetEntriesRequest) (*GetEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEntries not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetCertificates(context.Context, *GetCertificatesRequest) (*GetCertificatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCertificates not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetEntryAndProof(context.Context, *GetEntryAndProofRequest) (*GetEntryAndProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEntryAndProof not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetDomainTreeIndex(context.Context, *GetDomainTreeIndexRequest) (*GetDomainTreeIndexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainTreeIndex not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetSourceLogs(context.Context, *GetSourceLogsRequest) (*GetSourceLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSourceLogs not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetSourceLogAndProof(context.Context, *GetSourceLogAndProofRequest) (*GetSourceLogAndProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSourceLogAndProof not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetSourceConsistencyProof(context.Context, *GetSourceConsistencyProofRequest) (*GetSourceConsistencyProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSourceConsistencyProof not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) GetInclusionPromise(context.Context, *GetInclusionPromiseRequest) (*GetInclusionPromiseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInclusionPromise not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) AddCosignature(context.Context, *AddCosignatureRequest) (*AddCosignatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCosignature not implemented")
+}
+func (*UnimplementedDomainTransparencyServer) StreamSMHRange(*GetSMHRangeRequest, DomainTransparency_StreamSMHRangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSMHRange not implemented")
+}
+
+func RegisterDomainTransparencyServer(s *grpc.Server, srv DomainTransparencyServer) {
+	s.RegisterService(&_DomainTransparency_serviceDesc, srv)
+}
+
+func _DomainTransparency_GetSMH_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSMHRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetSMH(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetSMH",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetSMH(ctx, req.(*GetSMHRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetSMHRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSMHRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetSMHRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetSMHRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetSMHRange(ctx, req.(*GetSMHRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetSMHAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSMHAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetSMHAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetSMHAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetSMHAt(ctx, req.(*GetSMHAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetCheckpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetCheckpoint(ctx, req.(*GetCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetDomainRootAndProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainRootAndProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetDomainRootAndProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetDomainRootAndProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetDomainRootAndProof(ctx, req.(*GetDomainRootAndProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetDomainRootsAndMultiProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainRootsAndMultiProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetDomainRootsAndMultiProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetDomainRootsAndMultiProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetDomainRootsAndMultiProof(ctx, req.(*GetDomainRootsAndMultiProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetChangedDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChangedDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetChangedDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetChangedDomains",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetChangedDomains(ctx, req.(*GetChangedDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetDomainUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainUpdatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetDomainUpdates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetDomainUpdates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetDomainUpdates(ctx, req.(*GetDomainUpdatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetCertificatePlacements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCertificatePlacementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetCertificatePlacements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetCertificatePlacements",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetCertificatePlacements(ctx, req.(*GetCertificatePlacementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetConsistencyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConsistencyProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetConsistencyProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetConsistencyProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetConsistencyProof(ctx, req.(*GetConsistencyProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetEntries(ctx, req.(*GetEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetCertificates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetCertificates(ctx, req.(*GetCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetEntryAndProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEntryAndProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetEntryAndProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetEntryAndProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetEntryAndProof(ctx, req.(*GetEntryAndProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetDomainTreeIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainTreeIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetDomainTreeIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetDomainTreeIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetDomainTreeIndex(ctx, req.(*GetDomainTreeIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetSourceLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSourceLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetSourceLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetSourceLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetSourceLogs(ctx, req.(*GetSourceLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetSourceLogAndProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSourceLogAndProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetSourceLogAndProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetSourceLogAndProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetSourceLogAndProof(ctx, req.(*GetSourceLogAndProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetSourceConsistencyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSourceConsistencyProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetSourceConsistencyProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetSourceConsistencyProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetSourceConsistencyProof(ctx, req.(*GetSourceConsistencyProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_GetInclusionPromise_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInclusionPromiseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).GetInclusionPromise(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/GetInclusionPromise",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).GetInclusionPromise(ctx, req.(*GetInclusionPromiseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_AddCosignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCosignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DomainTransparencyServer).AddCosignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dt.v1.DomainTransparency/AddCosignature",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DomainTransparencyServer).AddCosignature(ctx, req.(*AddCosignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DomainTransparency_StreamSMHRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetSMHRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DomainTransparencyServer).StreamSMHRange(m, &domainTransparencyStreamSMHRangeServer{stream})
+}
+
+type DomainTransparency_StreamSMHRangeServer interface {
+	Send(*SignedMapHead) error
+	grpc.ServerStream
+}
+
+type domainTransparencyStreamSMHRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *domainTransparencyStreamSMHRangeServer) Send(m *SignedMapHead) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _DomainTransparency_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dt.v1.DomainTransparency",
+	HandlerType: (*DomainTransparencyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSMH",
+			Handler:    _DomainTransparency_GetSMH_Handler,
+		},
+		{
+			MethodName: "GetSMHRange",
+			Handler:    _DomainTransparency_GetSMHRange_Handler,
+		},
+		{
+			MethodName: "GetSMHAt",
+			Handler:    _DomainTransparency_GetSMHAt_Handler,
+		},
+		{
+			MethodName: "GetCheckpoint",
+			Handler:    _DomainTransparency_GetCheckpoint_Handler,
+		},
+		{
+			MethodName: "GetDomainRootAndProof",
+			Handler:    _DomainTransparency_GetDomainRootAndProof_Handler,
+		},
+		{
+			MethodName: "GetDomainRootsAndMultiProof",
+			Handler:    _DomainTransparency_GetDomainRootsAndMultiProof_Handler,
+		},
+		{
+			MethodName: "GetChangedDomains",
+			Handler:    _DomainTransparency_GetChangedDomains_Handler,
+		},
+		{
+			MethodName: "GetDomainUpdates",
+			Handler:    _DomainTransparency_GetDomainUpdates_Handler,
+		},
+		{
+			MethodName: "GetCertificatePlacements",
+			Handler:    _DomainTransparency_GetCertificatePlacements_Handler,
+		},
+		{
+			MethodName: "GetConsistencyProof",
+			Handler:    _DomainTransparency_GetConsistencyProof_Handler,
+		},
+		{
+			MethodName: "GetEntries",
+			Handler:    _DomainTransparency_GetEntries_Handler,
+		},
+		{
+			MethodName: "GetCertificates",
+			Handler:    _DomainTransparency_GetCertificates_Handler,
+		},
+		{
+			MethodName: "GetEntryAndProof",
+			Handler:    _DomainTransparency_GetEntryAndProof_Handler,
+		},
+		{
+			MethodName: "GetDomainTreeIndex",
+			Handler:    _DomainTransparency_GetDomainTreeIndex_Handler,
+		},
+		{
+			MethodName: "GetSourceLogs",
+			Handler:    _DomainTransparency_GetSourceLogs_Handler,
+		},
+		{
+			MethodName: "GetSourceLogAndProof",
+			Handler:    _DomainTransparency_GetSourceLogAndProof_Handler,
+		},
+		{
+			MethodName: "GetSourceConsistencyProof",
+			Handler:    _DomainTransparency_GetSourceConsistencyProof_Handler,
+		},
+		{
+			MethodName: "GetInclusionPromise",
+			Handler:    _DomainTransparency_GetInclusionPromise_Handler,
+		},
+		{
+			MethodName: "AddCosignature",
+			Handler:    _DomainTransparency_AddCosignature_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSMHRange",
+			Handler:       _DomainTransparency_StreamSMHRange_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dt.proto",
+}
@@ -0,0 +1,5 @@
+// Package dtpb contains the protobuf messages and gRPC stubs of the domain map's
+// gRPC API, generated from dt.proto. The server is implemented by ds.NewGRPCServer.
+package dtpb
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. dt.proto
@@ -0,0 +1,102 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/schema"
+)
+
+// An ErrorCode is a stable, machine-readable identifier of the kind of error
+// returned by the API. Clients should rely on it instead of the error message.
+type ErrorCode string
+
+const (
+	// CodeInvalidArgument means that the request is malformed: missing or
+	// invalid parameters, an invalid range or an invalid request body.
+	CodeInvalidArgument ErrorCode = "invalid_argument"
+	// CodeNotFound means that the requested object (an SMH, a domain tree,
+	// an entry, a witness) does not exist in the map.
+	CodeNotFound ErrorCode = "not_found"
+	// CodeOutOfRange means that an index or a size is beyond the current
+	// size of a tree. The request may succeed once the map grows.
+	CodeOutOfRange ErrorCode = "out_of_range"
+	// CodeUnavailable means that the server cannot serve the request at the
+	// moment (e.g. no SMH was published yet, or a CT log is unreachable).
+	// The request may be retried later.
+	CodeUnavailable ErrorCode = "unavailable"
+	// CodeResourceExhausted means that the client exceeded its rate limit.
+	// The request may be retried after the delay in the Retry-After header.
+	CodeResourceExhausted ErrorCode = "resource_exhausted"
+	// CodeInternal means that the server failed unexpectedly.
+	CodeInternal ErrorCode = "internal"
+)
+
+// HTTPStatus returns the HTTP status code of the responses with this error code.
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case CodeInvalidArgument, CodeOutOfRange:
+		return http.StatusBadRequest
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// An ErrorResponse is the JSON body of the error responses of the API.
+type ErrorResponse struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// apiError is an error returned by a handler, along with the code sent to the client.
+type apiError struct {
+	Code   ErrorCode
+	Status int // the HTTP status code, if it differs from Code.HTTPStatus()
+	Base   error
+}
+
+func (e apiError) Error() string {
+	return e.Base.Error()
+}
+
+func (e apiError) Unwrap() error {
+	return e.Base
+}
+
+// errorf returns an apiError with the specified code and a formatted message.
+func errorf(code ErrorCode, format string, a ...interface{}) error {
+	return apiError{Code: code, Base: fmt.Errorf(format, a...)}
+}
+
+// toErrorResponse returns the error response and HTTP status code for an error returned by a handler.
+// Errors decoding the query parameters are invalid_argument, and other errors without a code are internal.
+func toErrorResponse(err error) (ErrorResponse, int) {
+	var ae apiError
+	if errors.As(err, &ae) {
+		status := ae.Status
+		if status == 0 {
+			status = ae.Code.HTTPStatus()
+		}
+		return ErrorResponse{ae.Code, ae.Error()}, status
+	}
+
+	var me schema.MultiError
+	if errors.As(err, &me) {
+		msgs := make([]string, 0, len(me))
+		for k, v := range me {
+			msgs = append(msgs, fmt.Sprintf("%s: %v", k, v))
+		}
+		sort.Strings(msgs)
+		return ErrorResponse{CodeInvalidArgument, strings.Join(msgs, "; ")}, http.StatusBadRequest
+	}
+	return ErrorResponse{CodeInternal, err.Error()}, http.StatusInternalServerError
+}
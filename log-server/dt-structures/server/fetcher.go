@@ -2,7 +2,11 @@ package ds
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	ct "github.com/google/certificate-transparency-go"
@@ -21,6 +25,17 @@ type FetchParams struct {
 	LogClient        *client.LogClient
 	ReturnOnError    bool
 
+	// CTLogs, if set, caches the entries of the certificates passed to the worker.
+	CTLogs *CTLogCache
+
+	// Readiness, if set, tracks how far behind the log the fetcher is. The log should be
+	// registered with Readiness.AddLog before the fetcher is started, since the server is
+	// ready as long as all registered logs are caught up.
+	Readiness *Readiness
+
+	// Control, if set, allows the fetcher to be paused and resumed (e.g. by the admin server).
+	Control *FetcherControl
+
 	C chan<- dt.WorkerTransaction
 }
 
@@ -30,15 +45,30 @@ func FetchLogForWorker(ctx context.Context, params FetchParams) error {
 	opts.ParallelFetch = 1
 	opts.BatchSize = 64
 	opts.StartIndex = int64(params.InitialTreeSize)
+	if params.CTLogs != nil {
+		params.CTLogs.AddLog(params.LogIndex, params.LogClient)
+	}
+	if params.Readiness != nil {
+		params.Readiness.AddLog(params.LogIndex)
+	}
+	if params.Control != nil {
+		params.Control.addLog(params.LogIndex)
+	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	for {
+		if params.Control != nil {
+			if err := params.Control.waitWhilePaused(ctx, params.LogIndex); err != nil {
+				return err
+			}
+		}
 		if err := runFetcherIteration(ctx, cancel, params, opts); err != nil {
 			if params.ReturnOnError || err == ctx.Err() {
 				return err
 			} else {
+				fetcherErrors.WithLabelValues(strconv.FormatUint(params.LogIndex, 10)).Inc()
 				fmt.Printf("Error (log %d): %v\n", params.LogIndex, err)
 				time.Sleep(params.STHCheckInterval)
 			}
@@ -46,6 +76,92 @@ func FetchLogForWorker(ctx context.Context, params FetchParams) error {
 	}
 }
 
+// A FetcherControl pauses and resumes the fetchers that use it.
+// A paused fetcher stops before its next check for a new STH,
+// so the batch being fetched is still passed to the worker.
+type FetcherControl struct {
+	m      sync.Mutex
+	resume map[uint64]chan struct{} // indexed by log index; nil if the fetcher is running
+}
+
+// A FetcherStatus tells whether the fetcher of a log is paused.
+type FetcherStatus struct {
+	LogIndex uint64 `json:"log_index"`
+	Paused   bool   `json:"paused"`
+}
+
+// NewFetcherControl creates a FetcherControl.
+func NewFetcherControl() *FetcherControl {
+	return &FetcherControl{resume: make(map[uint64]chan struct{})}
+}
+
+func (c *FetcherControl) addLog(logIndex uint64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.resume[logIndex]; !ok {
+		c.resume[logIndex] = nil
+	}
+}
+
+// Pause pauses the fetcher of the specified log.
+func (c *FetcherControl) Pause(logIndex uint64) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	ch, ok := c.resume[logIndex]
+	if !ok {
+		return errorf(CodeNotFound, "no fetcher for log %d", logIndex)
+	}
+	if ch == nil {
+		c.resume[logIndex] = make(chan struct{})
+	}
+	return nil
+}
+
+// Resume resumes the fetcher of the specified log.
+func (c *FetcherControl) Resume(logIndex uint64) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	ch, ok := c.resume[logIndex]
+	if !ok {
+		return errorf(CodeNotFound, "no fetcher for log %d", logIndex)
+	}
+	if ch != nil {
+		close(ch)
+		c.resume[logIndex] = nil
+	}
+	return nil
+}
+
+// Status returns the status of every fetcher, in order of log index.
+func (c *FetcherControl) Status() []FetcherStatus {
+	c.m.Lock()
+	defer c.m.Unlock()
+	status := make([]FetcherStatus, 0, len(c.resume))
+	for logIndex, ch := range c.resume {
+		status = append(status, FetcherStatus{logIndex, ch != nil})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].LogIndex < status[j].LogIndex })
+	return status
+}
+
+// waitWhilePaused blocks while the fetcher of the specified log is paused.
+func (c *FetcherControl) waitWhilePaused(ctx context.Context, logIndex uint64) error {
+	c.m.Lock()
+	ch := c.resume[logIndex]
+	c.m.Unlock()
+	if ch == nil {
+		return nil
+	}
+	fmt.Printf("Fetcher (log %d): paused\n", logIndex)
+	select {
+	case <-ch:
+		fmt.Printf("Fetcher (log %d): resumed\n", logIndex)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func runFetcherIteration(ctx context.Context, cancel context.CancelFunc, params FetchParams, opts *scanner.FetcherOptions) error {
 	opts.EndIndex = 0
 	f := scanner.NewFetcher(params.LogClient, opts)
@@ -56,6 +172,10 @@ func runFetcherIteration(ctx context.Context, cancel context.CancelFunc, params
 	if err != nil {
 		return err
 	}
+	setFetcherProgress(params.LogIndex, sth.TreeSize, uint64(opts.StartIndex))
+	if params.Readiness != nil {
+		params.Readiness.setProgress(params.LogIndex, sth.TreeSize, uint64(opts.StartIndex))
+	}
 	if opts.EndIndex <= opts.StartIndex {
 		time.Sleep(params.STHCheckInterval)
 		return nil
@@ -69,6 +189,7 @@ func runFetcherIteration(ctx context.Context, cancel context.CancelFunc, params
 			RootHash: sth.SHA256RootHash,
 		},
 		NewCertificatesIndices: make(map[string][]uint64),
+		CertificateHashes:      make(map[uint64]dt.CertificateHashes),
 	}
 
 	var processErr error
@@ -92,24 +213,25 @@ func runFetcherIteration(ctx context.Context, cancel context.CancelFunc, params
 				fmt.Printf("Warning (log %d): ignoring JSON Data (index=%d)\n", params.LogIndex, leafIndex)
 				continue
 			}
-
-			for _, d := range cert.DNSNames {
-				d, err := util.NormalizeDomainName(d)
-				if err != nil {
-					fmt.Printf("Warning (log %d): ignoring invalid domain name for certificate at index=%d: %q\n", params.LogIndex, leafIndex, d)
-					continue
-				}
-				t.NewCertificatesIndices[d] = append(t.NewCertificatesIndices[d], uint64(leafIndex))
+			hashes, err := HashesForLogEntry(logEntry)
+			if err != nil {
+				processErr = fmt.Errorf("error hashing entry (index=%d): %w", leafIndex, err)
+				cancel()
+				return
 			}
+			t.CertificateHashes[uint64(leafIndex)] = hashes
 
-			d, err := util.NormalizeDomainName(cert.Subject.CommonName)
-			if err != nil {
-				if len(cert.DNSNames) == 0 {
-					fmt.Printf("Warning (log %d): ignoring certificate at index=%d: no valid domain names found\n", params.LogIndex, leafIndex)
-				}
+			domains := CertificateDomainNames(cert)
+			if len(domains) == 0 {
+				fmt.Printf("Warning (log %d): ignoring certificate at index=%d: no valid domain names found\n", params.LogIndex, leafIndex)
 				continue
 			}
-			t.NewCertificatesIndices[d] = append(t.NewCertificatesIndices[d], uint64(leafIndex))
+			for _, d := range domains {
+				t.NewCertificatesIndices[d] = append(t.NewCertificatesIndices[d], uint64(leafIndex))
+			}
+			if params.CTLogs != nil {
+				params.CTLogs.AddLeaf(params.LogIndex, uint64(leafIndex), leaf)
+			}
 		}
 	}
 
@@ -122,5 +244,51 @@ func runFetcherIteration(ctx context.Context, cancel context.CancelFunc, params
 
 	params.C <- t
 	opts.StartIndex = int64(sth.TreeSize)
+	setFetcherProgress(params.LogIndex, sth.TreeSize, sth.TreeSize)
+	if params.Readiness != nil {
+		params.Readiness.setProgress(params.LogIndex, sth.TreeSize, sth.TreeSize)
+	}
 	return nil
 }
+
+// HashesForLogEntry returns the Merkle leaf hash of a CT log entry and the
+// SHA-256 fingerprint of its certificate (or precertificate).
+func HashesForLogEntry(logEntry *ct.LogEntry) (dt.CertificateHashes, error) {
+	var hashes dt.CertificateHashes
+	switch logEntry.Leaf.TimestampedEntry.EntryType {
+	case ct.X509LogEntryType:
+		hashes.Fingerprint = sha256.Sum256(logEntry.Leaf.TimestampedEntry.X509Entry.Data)
+	case ct.PrecertLogEntryType:
+		if logEntry.Precert == nil {
+			return dt.CertificateHashes{}, fmt.Errorf("missing precertificate")
+		}
+		hashes.Fingerprint = sha256.Sum256(logEntry.Precert.Submitted.Data)
+	default:
+		return dt.CertificateHashes{}, fmt.Errorf("unsupported entry type %v", logEntry.Leaf.TimestampedEntry.EntryType)
+	}
+	leafHash, err := ct.LeafHashForLeaf(&logEntry.Leaf)
+	if err != nil {
+		return dt.CertificateHashes{}, err
+	}
+	hashes.LeafHash = leafHash
+	return hashes, nil
+}
+
+// CertificateDomainNames returns the normalized domain names under which the fetcher
+// adds a certificate to the map: its DNS names and its subject common name, without
+// duplicates. Invalid names are skipped.
+func CertificateDomainNames(cert *x509.Certificate) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, d := range append(cert.DNSNames, cert.Subject.CommonName) {
+		normalizedDomain, err := util.NormalizeDomainName(d)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[normalizedDomain]; !ok {
+			seen[normalizedDomain] = struct{}{}
+			names = append(names, normalizedDomain)
+		}
+	}
+	return names
+}
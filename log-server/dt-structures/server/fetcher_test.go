@@ -0,0 +1,232 @@
+package ds_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	"github.com/google/certificate-transparency-go/tls"
+	"github.com/google/certificate-transparency-go/x509"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newCertificate returns a DER-encoded self-signed certificate with the specified names.
+func newCertificate(t *testing.T, commonName string, dnsNames []string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &stdx509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := stdx509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// newFakeCTLogWithEntries serves the STH and the entries of a CT log with the specified certificates.
+func newFakeCTLogWithEntries(t *testing.T, certs [][]byte) *client.LogClient {
+	var entries []ct.LeafEntry
+	for _, der := range certs {
+		leaf := ct.MerkleTreeLeaf{
+			Version:  ct.V1,
+			LeafType: ct.TimestampedEntryLeafType,
+			TimestampedEntry: &ct.TimestampedEntry{
+				EntryType: ct.X509LogEntryType,
+				X509Entry: &ct.ASN1Cert{Data: der},
+			},
+		}
+		leafInput, err := tls.Marshal(leaf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		extraData, err := tls.Marshal(ct.CertificateChain{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, ct.LeafEntry{LeafInput: leafInput, ExtraData: extraData})
+	}
+
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ct/v1/get-sth":
+			json.NewEncoder(w).Encode(ct.GetSTHResponse{
+				TreeSize:          uint64(len(entries)),
+				SHA256RootHash:    make([]byte, 32),
+				TreeHeadSignature: []byte{4, 3, 0, 1, 0}, // unverified
+			})
+		case "/ct/v1/get-entries":
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			end, _ := strconv.Atoi(r.URL.Query().Get("end"))
+			if start < 0 || start > end || end >= len(entries) {
+				http.Error(w, "invalid range", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(ct.GetEntriesResponse{Entries: entries[start : end+1]})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(hs.Close)
+	lc, err := client.New(hs.URL, hs.Client(), jsonclient.Options{})
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	return lc
+}
+
+func TestFetchLogForWorker(t *testing.T) {
+	certs := [][]byte{
+		newCertificate(t, "a.com", []string{"a.com", "www.a.com"}), // the common name is also a DNS name
+		newCertificate(t, "B.com", []string{"mail.b.com"}),         // the same name after normalization
+		newCertificate(t, "c.com", nil),
+		newCertificate(t, "d.com", []string{"e.com"}),
+		newCertificate(t, "", nil), // no domain names
+	}
+	c := make(chan dt.WorkerTransaction, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- ds.FetchLogForWorker(ctx, ds.FetchParams{
+			STHCheckInterval: time.Hour,
+			LogClient:        newFakeCTLogWithEntries(t, certs),
+			ReturnOnError:    true,
+			C:                c,
+		})
+	}()
+
+	var tx dt.WorkerTransaction
+	select {
+	case tx = <-c:
+	case err := <-done:
+		t.Fatalf("FetchLogForWorker: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for a transaction")
+	}
+	cancel()
+	<-done
+
+	// Each certificate is added once to the domain tree of each of its (normalized) domain names
+	names := [][]string{{"a.com"}, {"b.com"}, {"c.com"}, {"e.com", "d.com"}, nil}
+	want := make(map[string][]uint64)
+	for i, domains := range names {
+		for _, d := range domains {
+			want[d] = append(want[d], uint64(i))
+		}
+	}
+	if !reflect.DeepEqual(tx.NewCertificatesIndices, want) {
+		t.Errorf("got certificate indices %v, expected %v", tx.NewCertificatesIndices, want)
+	}
+	if tx.LogRevision.TreeSize != uint64(len(certs)) || len(tx.CertificateHashes) != len(certs) {
+		t.Errorf("got tree size %d and %d certificate hashes, expected %d", tx.LogRevision.TreeSize, len(tx.CertificateHashes), len(certs))
+	}
+
+	for i, der := range certs {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ds.CertificateDomainNames(cert); !reflect.DeepEqual(got, names[i]) {
+			t.Errorf("CertificateDomainNames (certificate %d): got %v, expected %v", i, got, names[i])
+		}
+	}
+}
+
+// fetcherMetric returns the value of a fetcher gauge for the specified log, if it is set.
+func fetcherMetric(t *testing.T, name string, logIndex uint64) (float64, bool) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "log" && l.GetValue() == strconv.FormatUint(logIndex, 10) {
+					return m.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestFetcherMetrics(t *testing.T) {
+	certs := [][]byte{newCertificate(t, "a.com", nil), newCertificate(t, "b.com", nil), newCertificate(t, "c.com", nil)}
+	lc := newFakeCTLogWithEntries(t, certs)
+	// fetch runs a fetcher of the log until wait returns
+	fetch := func(logIndex, initialTreeSize uint64, wait func(c <-chan dt.WorkerTransaction)) {
+		c := make(chan dt.WorkerTransaction)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			done <- ds.FetchLogForWorker(ctx, ds.FetchParams{
+				InitialTreeSize:  initialTreeSize,
+				STHCheckInterval: 10 * time.Millisecond,
+				LogIndex:         logIndex,
+				LogClient:        lc,
+				ReturnOnError:    true,
+				C:                c,
+			})
+		}()
+		wait(c)
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Fatalf("FetchLogForWorker: %v", err)
+		}
+	}
+	check := func(logIndex uint64, want map[string]float64) {
+		for name, v := range want {
+			if got, ok := fetcherMetric(t, name, logIndex); !ok || got != v {
+				t.Errorf("%s (log %d): got %v (set: %v), expected %v", name, logIndex, got, ok, v)
+			}
+		}
+	}
+
+	// The progress is updated once the transaction is passed to the worker
+	fetch(7, 1, func(c <-chan dt.WorkerTransaction) {
+		select {
+		case <-c:
+		case <-time.After(10 * time.Second):
+			t.Errorf("timeout waiting for a transaction")
+		}
+	})
+	check(7, map[string]float64{"dt_fetcher_sth_size": 3, "dt_fetcher_fetched_index": 3, "dt_fetcher_lag_entries": 0})
+
+	// A fetcher ahead of the log's STH has no lag
+	fetch(8, 5, func(<-chan dt.WorkerTransaction) {
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if _, ok := fetcherMetric(t, "dt_fetcher_lag_entries", 8); ok {
+				return
+			}
+		}
+		t.Errorf("timeout waiting for the fetcher metrics")
+	})
+	check(8, map[string]float64{"dt_fetcher_sth_size": 3, "dt_fetcher_fetched_index": 5, "dt_fetcher_lag_entries": 0})
+}
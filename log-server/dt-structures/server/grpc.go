@@ -0,0 +1,440 @@
+package ds
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server/dtpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The gRPC API mirrors the JSON HTTP API: each `/dt/v1/*` query is a unary method of
+// the GRPCServiceName service, defined in dtpb/dt.proto, and is served by the same
+// dtHandler method as the HTTP path, with the protobuf messages converted to the
+// request and response types of the HTTP API.
+const GRPCServiceName = "dt.v1.DomainTransparency"
+
+// grpcPaths maps the gRPC methods to the HTTP paths that they mirror, whose
+// rate limiting costs they share. StreamSMHRange costs as much as GetSMHRange.
+var grpcPaths = map[string]string{
+	"GetSMH":                      "/dt/v1/get-smh",
+	"GetSMHRange":                 "/dt/v1/get-smh-range",
+	"GetSMHAt":                    "/dt/v1/get-smh-at",
+	"GetCheckpoint":               "/dt/v1/checkpoint",
+	"GetDomainRootAndProof":       "/dt/v1/get-domain-root-and-proof",
+	"GetDomainRootsAndMultiProof": "/dt/v1/get-domain-roots-and-multiproof",
+	"GetChangedDomains":           "/dt/v1/get-changed-domains",
+	"GetDomainUpdates":            "/dt/v1/get-domain-updates",
+	"GetCertificatePlacements":    "/dt/v1/get-certificate-placements",
+	"GetConsistencyProof":         "/dt/v1/get-consistency-proof",
+	"GetEntries":                  "/dt/v1/get-entries",
+	"GetCertificates":             "/dt/v1/get-certificates",
+	"GetEntryAndProof":            "/dt/v1/get-entry-and-proof",
+	"GetDomainTreeIndex":          "/dt/v1/get-domain-tree-index",
+	"GetSourceLogs":               "/dt/v1/get-source-logs",
+	"GetSourceLogAndProof":        "/dt/v1/get-source-log-and-proof",
+	"GetSourceConsistencyProof":   "/dt/v1/get-source-consistency-proof",
+	"GetInclusionPromise":         "/dt/v1/get-inclusion-promise",
+	"AddCosignature":              "/dt/v1/add-cosignature",
+	"StreamSMHRange":              "/dt/v1/get-smh-range",
+}
+
+// grpcMethodName returns the name of a gRPC method (e.g. "GetSMH") from its full name.
+func grpcMethodName(fullMethod string) string {
+	return strings.TrimPrefix(fullMethod, "/"+GRPCServiceName+"/")
+}
+
+// grpcError converts an error returned by a dtHandler into a gRPC status error
+// with the code that corresponds to its ErrorCode (see toErrorResponse).
+func grpcError(err error) error {
+	resp, _ := toErrorResponse(err)
+	switch resp.Code {
+	case CodeInvalidArgument:
+		return status.Error(codes.InvalidArgument, resp.Message)
+	case CodeNotFound:
+		return status.Error(codes.NotFound, resp.Message)
+	case CodeOutOfRange:
+		return status.Error(codes.OutOfRange, resp.Message)
+	case CodeUnavailable:
+		return status.Error(codes.Unavailable, resp.Message)
+	case CodeResourceExhausted:
+		return status.Error(codes.ResourceExhausted, resp.Message)
+	default:
+		return status.Error(codes.Internal, resp.Message)
+	}
+}
+
+// grpcUnaryServe is the innermost interceptor of unary calls. It responds with errNotReady
+// until the map publishes its first signed SMH, converts the errors of the handlers into
+// gRPC status errors, recovers from panics and records the metrics of each call.
+func (h *dtHandler) grpcUnaryServe(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	name := grpcMethodName(info.FullMethod)
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			fmt.Printf("Panic serving %s: %v\n%s", name, v, debug.Stack())
+			resp, err = nil, status.Error(codes.Internal, "internal error")
+		}
+		observeGRPCRequest(name, start, err)
+	}()
+	if err := h.checkReady(); err != nil {
+		return nil, grpcError(err)
+	}
+	if resp, err = handler(ctx, req); err != nil {
+		return nil, grpcError(err)
+	}
+	return resp, nil
+}
+
+// grpcCost returns the rate limiting cost of the gRPC method with the specified full name,
+// which is the cost of the corresponding HTTP path.
+func (h *dtHandler) grpcCost(fullMethod string) int {
+	if cost, ok := h.costs[grpcPaths[grpcMethodName(fullMethod)]]; ok {
+		return cost
+	}
+	return costCheap
+}
+
+// NewGRPCServer creates a gRPC server for the handler returned by NewServer.
+// The calls are subject to the handler's RateLimiter, with the costs of the HTTP paths.
+func NewGRPCServer(h *dtHandler, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(h.grpcUnaryRateLimit, h.grpcUnaryServe),
+		grpc.ChainStreamInterceptor(h.grpcStreamRateLimit))
+	svr := grpc.NewServer(opts...)
+	dtpb.RegisterDomainTransparencyServer(svr, &grpcServer{h})
+	return svr
+}
+
+// grpcServer implements the gRPC API with the methods of a dtHandler.
+// Its errors are converted into gRPC status errors by grpcUnaryServe.
+type grpcServer struct {
+	h *dtHandler
+}
+
+var _ dtpb.DomainTransparencyServer = (*grpcServer)(nil)
+
+func (s *grpcServer) GetSMH(ctx context.Context, req *dtpb.GetSMHRequest) (*dtpb.GetSMHResponse, error) {
+	resp, err := s.h.getSMH(ctx, &GetSMHRequest{MapSize: req.MapSize, Cosignatures: req.Cosignatures})
+	if err != nil {
+		return nil, err
+	}
+	return smhResponseToProto(resp), nil
+}
+
+func (s *grpcServer) GetSMHRange(ctx context.Context, req *dtpb.GetSMHRangeRequest) (*dtpb.GetSMHRangeResponse, error) {
+	resp, err := s.h.getSMHRange(ctx, &GetSMHRangeRequest{Start: req.Start, End: req.End})
+	if err != nil {
+		return nil, err
+	}
+	pb := &dtpb.GetSMHRangeResponse{Smhs: make([]*dtpb.SignedMapHead, len(resp.SMHs))}
+	for i := range resp.SMHs {
+		pb.Smhs[i] = smhToProto(&resp.SMHs[i])
+	}
+	return pb, nil
+}
+
+func (s *grpcServer) GetSMHAt(ctx context.Context, req *dtpb.GetSMHAtRequest) (*dtpb.GetSMHResponse, error) {
+	resp, err := s.h.getSMHAt(ctx, &GetSMHAtRequest{Timestamp: req.Timestamp})
+	if err != nil {
+		return nil, err
+	}
+	return smhResponseToProto(resp), nil
+}
+
+func (s *grpcServer) GetCheckpoint(ctx context.Context, req *dtpb.GetCheckpointRequest) (*dtpb.GetCheckpointResponse, error) {
+	checkpoint, err := s.h.getCheckpoint(ctx, &GetCheckpointRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetCheckpointResponse{Checkpoint: checkpoint}, nil
+}
+
+func (s *grpcServer) GetDomainRootAndProof(ctx context.Context, req *dtpb.GetDomainRootAndProofRequest) (*dtpb.GetDomainRootAndProofResponse, error) {
+	resp, err := s.h.getDomainRootAndProof(ctx, &GetDomainRootAndProofRequest{DomainName: req.DomainName, DomainMapSize: req.DomainMapSize})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetDomainRootAndProofResponse{
+		DomainTreeSize:        resp.DomainTreeSize,
+		DomainTreeRootHash:    resp.DomainTreeRootHash,
+		NormalizedDomainName:  resp.NormalizedDomainName,
+		AuditPath:             resp.AuditPath,
+		NonMembershipLeafData: resp.NonMembershipLeafData,
+	}, nil
+}
+
+func (s *grpcServer) GetDomainRootsAndMultiProof(ctx context.Context, req *dtpb.GetDomainRootsAndMultiProofRequest) (*dtpb.GetDomainRootsAndMultiProofResponse, error) {
+	resp, err := s.h.getDomainRootsAndMultiProof(ctx, &GetDomainRootsAndMultiProofRequest{DomainNames: req.DomainName, DomainMapSize: req.DomainMapSize})
+	if err != nil {
+		return nil, err
+	}
+	return multiProofToProto(resp), nil
+}
+
+func (s *grpcServer) GetChangedDomains(ctx context.Context, req *dtpb.GetChangedDomainsRequest) (*dtpb.GetChangedDomainsResponse, error) {
+	resp, err := s.h.getChangedDomains(ctx, &GetChangedDomainsRequest{First: req.First, Second: req.Second, Start: req.Start})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetChangedDomainsResponse{
+		Total:  resp.Total,
+		First:  multiProofToProto(&resp.First),
+		Second: multiProofToProto(&resp.Second),
+	}, nil
+}
+
+func (s *grpcServer) GetDomainUpdates(ctx context.Context, req *dtpb.GetDomainUpdatesRequest) (*dtpb.GetDomainUpdatesResponse, error) {
+	resp, err := s.h.getDomainUpdates(ctx, &GetDomainUpdatesRequest{DomainName: req.DomainName, From: req.From, MapSize: req.MapSize, Start: req.Start})
+	if err != nil {
+		return nil, err
+	}
+	pb := &dtpb.GetDomainUpdatesResponse{
+		Smh:              smhResponseToProto(&resp.SMH),
+		DomainRoot:       multiProofToProto(&resp.DomainRoot),
+		ConsistencyProof: resp.ConsistencyProof,
+		Entries:          make([]*dtpb.DomainUpdateEntry, len(resp.Entries)),
+		SourceLogs:       make([]*dtpb.SourceLogInBundle, len(resp.SourceLogs)),
+	}
+	for i, e := range resp.Entries {
+		pb.Entries[i] = &dtpb.DomainUpdateEntry{
+			Index:                  e.Index,
+			Entry:                  entryToProto(e.Entry),
+			LeafHash:               e.LeafHash,
+			CertificateFingerprint: e.CertificateFingerprint,
+			AuditPath:              e.AuditPath,
+			LeafInput:              e.LeafInput,
+			ExtraData:              e.ExtraData,
+			CtAuditPath:            e.CTAuditPath,
+		}
+	}
+	for i, l := range resp.SourceLogs {
+		pb.SourceLogs[i] = &dtpb.SourceLogInBundle{LogIndex: l.LogIndex, LogId: l.LogID, AuditPath: l.AuditPath}
+	}
+	return pb, nil
+}
+
+func (s *grpcServer) GetCertificatePlacements(ctx context.Context, req *dtpb.GetCertificatePlacementsRequest) (*dtpb.GetCertificatePlacementsResponse, error) {
+	resp, err := s.h.getCertificatePlacements(ctx, &GetCertificatePlacementsRequest{
+		LogIndex:         req.LogIndex,
+		CertificateIndex: req.CertificateIndex,
+		MapSize:          req.MapSize,
+		Start:            req.Start,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pb := &dtpb.GetCertificatePlacementsResponse{
+		Total:       resp.Total,
+		Placements:  make([]*dtpb.CertificatePlacement, len(resp.Placements)),
+		DomainRoots: multiProofToProto(&resp.DomainRoots),
+	}
+	for i, p := range resp.Placements {
+		pb.Placements[i] = &dtpb.CertificatePlacement{
+			NormalizedDomainName:   p.NormalizedDomainName,
+			Index:                  p.Index,
+			Entry:                  entryToProto(p.Entry),
+			LeafHash:               p.LeafHash,
+			CertificateFingerprint: p.CertificateFingerprint,
+			AuditPath:              p.AuditPath,
+		}
+	}
+	return pb, nil
+}
+
+func (s *grpcServer) GetConsistencyProof(ctx context.Context, req *dtpb.GetConsistencyProofRequest) (*dtpb.GetConsistencyProofResponse, error) {
+	resp, err := s.h.getConsistencyProof(ctx, &GetConsistencyProofRequest{DomainName: req.DomainName, First: req.First, Second: req.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetConsistencyProofResponse{Proof: resp.Proof}, nil
+}
+
+func (s *grpcServer) GetEntries(ctx context.Context, req *dtpb.GetEntriesRequest) (*dtpb.GetEntriesResponse, error) {
+	resp, err := s.h.getEntries(ctx, &GetEntriesRequest{DomainName: req.DomainName, Start: req.Start, End: req.End})
+	if err != nil {
+		return nil, err
+	}
+	pb := &dtpb.GetEntriesResponse{
+		Entries:                 make([]*dtpb.DomainTreeEntry, len(resp.Entries)),
+		LeafHashes:              resp.LeafHashes,
+		CertificateFingerprints: resp.CertificateFingerprints,
+	}
+	for i, e := range resp.Entries {
+		pb.Entries[i] = entryToProto(e)
+	}
+	return pb, nil
+}
+
+func (s *grpcServer) GetCertificates(ctx context.Context, req *dtpb.GetCertificatesRequest) (*dtpb.GetCertificatesResponse, error) {
+	resp, err := s.h.getCertificates(ctx, &GetCertificatesRequest{DomainName: req.DomainName, Start: req.Start, End: req.End})
+	if err != nil {
+		return nil, err
+	}
+	pb := &dtpb.GetCertificatesResponse{MapSize: resp.MapSize, Certificates: make([]*dtpb.CertificateEntry, len(resp.Certificates))}
+	for i, c := range resp.Certificates {
+		pb.Certificates[i] = &dtpb.CertificateEntry{
+			Entry:       entryToProto(c.Entry),
+			LeafInput:   c.LeafInput,
+			ExtraData:   c.ExtraData,
+			CtAuditPath: c.CTAuditPath,
+		}
+	}
+	return pb, nil
+}
+
+func (s *grpcServer) GetEntryAndProof(ctx context.Context, req *dtpb.GetEntryAndProofRequest) (*dtpb.GetEntryAndProofResponse, error) {
+	resp, err := s.h.getEntryAndProof(ctx, &GetEntryAndProofRequest{DomainName: req.DomainName, Index: req.Index, DomainTreeSize: req.DomainTreeSize})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetEntryAndProofResponse{
+		Entry:                  entryToProto(resp.Entry),
+		LeafHash:               resp.LeafHash,
+		CertificateFingerprint: resp.CertificateFingerprint,
+		AuditPath:              resp.AuditPath,
+	}, nil
+}
+
+func (s *grpcServer) GetDomainTreeIndex(ctx context.Context, req *dtpb.GetDomainTreeIndexRequest) (*dtpb.GetDomainTreeIndexResponse, error) {
+	resp, err := s.h.getDomainTreeIndex(ctx, &GetDomainTreeIndexRequest{DomainName: req.DomainName, LogIndex: req.LogIndex, CertificateIndex: req.CertificateIndex})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetDomainTreeIndexResponse{DomainTreeIndex: resp.DomainTreeIndex}, nil
+}
+
+func (s *grpcServer) GetSourceLogs(ctx context.Context, req *dtpb.GetSourceLogsRequest) (*dtpb.GetSourceLogsResponse, error) {
+	resp, err := s.h.getSourceLogs(ctx, &GetSourceLogsRequest{Start: req.Start, End: req.End})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetSourceLogsResponse{LogIds: resp.LogIDs}, nil
+}
+
+func (s *grpcServer) GetSourceLogAndProof(ctx context.Context, req *dtpb.GetSourceLogAndProofRequest) (*dtpb.GetSourceLogAndProofResponse, error) {
+	resp, err := s.h.getSourceLogAndProof(ctx, &GetSourceLogAndProofRequest{Index: req.Index, SourceTreeSize: req.SourceTreeSize})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetSourceLogAndProofResponse{LogId: resp.LogID, AuditPath: resp.AuditPath}, nil
+}
+
+func (s *grpcServer) GetSourceConsistencyProof(ctx context.Context, req *dtpb.GetSourceConsistencyProofRequest) (*dtpb.GetSourceConsistencyProofResponse, error) {
+	resp, err := s.h.getSourceConsistencyProof(ctx, &GetSourceConsistencyProofRequest{First: req.First, Second: req.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetSourceConsistencyProofResponse{Proof: resp.Proof}, nil
+}
+
+func (s *grpcServer) GetInclusionPromise(ctx context.Context, req *dtpb.GetInclusionPromiseRequest) (*dtpb.GetInclusionPromiseResponse, error) {
+	resp, err := s.h.getInclusionPromise(ctx, &GetInclusionPromiseRequest{DomainName: req.DomainName, LogIndex: req.LogIndex, CertificateIndex: req.CertificateIndex})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.GetInclusionPromiseResponse{
+		Timestamp:            resp.Timestamp,
+		LogIndex:             resp.LogIndex,
+		CertificateIndex:     resp.CertificateIndex,
+		NormalizedDomainName: resp.NormalizedDomainName,
+		PromiseSignature:     resp.PromiseSignature,
+	}, nil
+}
+
+func (s *grpcServer) AddCosignature(ctx context.Context, req *dtpb.AddCosignatureRequest) (*dtpb.AddCosignatureResponse, error) {
+	_, err := s.h.addCosignature(ctx, &AddCosignatureRequest{
+		MapSize:        req.MapSize,
+		WitnessKeyHash: req.WitnessKeyHash,
+		Timestamp:      req.Timestamp,
+		Signature:      req.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dtpb.AddCosignatureResponse{}, nil
+}
+
+// StreamSMHRange streams every SMH in the requested range, without the paging limit of GetSMHRange.
+// Unlike the unary methods, it converts its own errors into gRPC status errors.
+func (s *grpcServer) StreamSMHRange(req *dtpb.GetSMHRangeRequest, stream dtpb.DomainTransparency_StreamSMHRangeServer) error {
+	if err := s.h.checkReady(); err != nil {
+		return grpcError(err)
+	}
+	if req.Start > req.End {
+		return status.Errorf(codes.InvalidArgument, "invalid range: [%d,%d]", req.Start, req.End)
+	}
+	for start := req.Start; ; {
+		smhs := s.h.dm.GetSMHRange(start, req.End, maxSMHRange)
+		for _, smh := range smhs {
+			if err := stream.Send(smhToProto(smh)); err != nil {
+				return err
+			}
+		}
+		if len(smhs) < maxSMHRange {
+			return nil
+		}
+		last := smhs[len(smhs)-1].MapSize
+		if last >= req.End {
+			return nil
+		}
+		start = last + 1
+	}
+}
+
+func smhToProto(smh *dt.SignedMapHead) *dtpb.SignedMapHead {
+	pb := &dtpb.SignedMapHead{
+		Version:            uint32(smh.Version),
+		Timestamp:          smh.Timestamp,
+		MapSize:            smh.MapSize,
+		MapRootHash:        smh.MapRootHash[:],
+		SourceTreeRootHash: smh.SourceTreeRootHash[:],
+		SourceLogRevisions: make([]*dtpb.LogRevision, len(smh.SourceLogRevisions)),
+		MapHeadSignature:   smh.MapHeadSignature,
+	}
+	for i := range smh.SourceLogRevisions {
+		rev := &smh.SourceLogRevisions[i]
+		pb.SourceLogRevisions[i] = &dtpb.LogRevision{TreeSize: rev.TreeSize, RootHash: rev.RootHash[:]}
+	}
+	return pb
+}
+
+func smhResponseToProto(resp *GetSMHResponse) *dtpb.GetSMHResponse {
+	pb := &dtpb.GetSMHResponse{Smh: smhToProto(&resp.SignedMapHead)}
+	for i := range resp.Cosignatures {
+		cosig := &resp.Cosignatures[i]
+		pb.Cosignatures = append(pb.Cosignatures, &dtpb.MapHeadCosignature{
+			WitnessKeyHash: cosig.WitnessKeyHash[:],
+			Timestamp:      cosig.Timestamp,
+			Signature:      cosig.Signature,
+		})
+	}
+	return pb
+}
+
+func multiProofToProto(resp *GetDomainRootsAndMultiProofResponse) *dtpb.GetDomainRootsAndMultiProofResponse {
+	pb := &dtpb.GetDomainRootsAndMultiProofResponse{
+		Domains:   make([]*dtpb.DomainRootInMultiProof, len(resp.Domains)),
+		SideNodes: resp.SideNodes,
+	}
+	for i, d := range resp.Domains {
+		pb.Domains[i] = &dtpb.DomainRootInMultiProof{
+			DomainTreeSize:        d.DomainTreeSize,
+			DomainTreeRootHash:    d.DomainTreeRootHash,
+			NormalizedDomainName:  d.NormalizedDomainName,
+			ProofDepth:            int32(d.ProofDepth),
+			NonMembershipLeafData: d.NonMembershipLeafData,
+		}
+	}
+	return pb
+}
+
+func entryToProto(entry [2]uint64) *dtpb.DomainTreeEntry {
+	return &dtpb.DomainTreeEntry{LogIndex: entry[0], CertificateIndex: entry[1]}
+}
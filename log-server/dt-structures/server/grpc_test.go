@@ -0,0 +1,233 @@
+package ds_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server/dtpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// withoutEmpty returns a JSON-decoded value without its nulls, empty strings and empty
+// arrays, which protobuf doesn't distinguish from omitted fields.
+func withoutEmpty(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if e = withoutEmpty(e); e == nil {
+				delete(v, k)
+			} else {
+				v[k] = e
+			}
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i := range v {
+			v[i] = withoutEmpty(v[i])
+		}
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return v
+}
+
+// sameJSON reports whether two responses have the same JSON encoding, up to empty fields.
+func sameJSON(t *testing.T, a, b interface{}) bool {
+	var values [2]interface{}
+	for i, v := range []interface{}{a, b} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reflect.DeepEqual(withoutEmpty(values[0]), withoutEmpty(values[1]))
+}
+
+// TestGRPC calls every method of the gRPC API through mapclient, and checks that the
+// responses are the same as those of the HTTP API. It also calls the generated stubs directly.
+func TestGRPC(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	if _, err := dm.AddWitness(&witnessKey.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	svr, h := ds.NewServer(dm, "localhost", 0)
+	h.CTLogs = ds.NewCTLogCache()
+	hs := httptest.NewServer(svr.Handler)
+	defer hs.Close()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := ds.NewGRPCServer(h)
+	go gs.Serve(lis)
+	defer gs.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 4, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+
+	addCertificates(t, dm, c, h.CTLogs, 0, 3, []testCert{
+		{0, 0, []string{"a.com", "b.com"}},
+		{0, 1, []string{"a.com"}},
+		{0, 2, []string{"c.com"}},
+	})
+	first := dm.GetLatestSMH().MapSize
+	addCertificates(t, dm, c, h.CTLogs, 1, 2, []testCert{
+		{1, 0, []string{"a.com"}},
+		{1, 1, []string{"d.com"}},
+	})
+	second := dm.GetLatestSMH().MapSize
+
+	hc := mapclient.New(hs.URL+"/", hs.Client(), &key.PublicKey)
+	gc := mapclient.NewGRPC(conn, &key.PublicKey)
+	gc.SetRetryPolicy(mapclient.RetryPolicy{})
+
+	smh, err := gc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatalf("GetAndVerifySMH: %v", err)
+	}
+	cosig, err := mapclient.CosignSMH(witnessKey, &witnessKey.PublicKey, smh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gc.AddCosignature(ctx, cosig); err != nil {
+		t.Fatalf("AddCosignature: %v", err)
+	}
+	if _, cosigs := dm.GetLatestSMHAndCosignatures(); len(cosigs) != 1 {
+		t.Fatalf("AddCosignature: got %d cosignatures, expected 1", len(cosigs))
+	}
+
+	calls := []struct {
+		name string
+		call func(mc *mapclient.MapClient) (interface{}, error)
+	}{
+		{"GetAndVerifySMH", func(mc *mapclient.MapClient) (interface{}, error) { return mc.GetAndVerifySMH(ctx) }},
+		{"GetAndVerifySMHForSize", func(mc *mapclient.MapClient) (interface{}, error) { return mc.GetAndVerifySMHForSize(ctx, first) }},
+		{"GetAndVerifySMHRange", func(mc *mapclient.MapClient) (interface{}, error) { return mc.GetAndVerifySMHRange(ctx, 0, second) }},
+		{"GetAndVerifySMHAt", func(mc *mapclient.MapClient) (interface{}, error) { return mc.GetAndVerifySMHAt(ctx, smh.Timestamp) }},
+		{"GetCheckpoint", func(mc *mapclient.MapClient) (interface{}, error) { return mc.GetCheckpoint(ctx) }},
+		{"GetDomainRootAndProof", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetDomainRootAndProof(ctx, &ds.GetDomainRootAndProofRequest{DomainName: "a.com", DomainMapSize: second})
+		}},
+		{"GetAndVerifyDomainRootsAndMultiProof", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetAndVerifyDomainRootsAndMultiProof(ctx, []string{"a.com", "missing.com"}, smh)
+		}},
+		{"GetChangedDomains", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetChangedDomains(ctx, &ds.GetChangedDomainsRequest{First: first, Second: second})
+		}},
+		{"GetDomainUpdates", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetDomainUpdates(ctx, &ds.GetDomainUpdatesRequest{DomainName: "a.com", From: 1, MapSize: second})
+		}},
+		{"GetCertificatePlacements", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetCertificatePlacements(ctx, &ds.GetCertificatePlacementsRequest{LogIndex: 0, CertificateIndex: 0, MapSize: second})
+		}},
+		{"GetConsistencyProof", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetConsistencyProof(ctx, &ds.GetConsistencyProofRequest{DomainName: "a.com", First: 1, Second: 3})
+		}},
+		{"GetEntries", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetEntries(ctx, &ds.GetEntriesRequest{DomainName: "a.com", Start: 0, End: 2})
+		}},
+		{"GetCertificates", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetCertificates(ctx, &ds.GetCertificatesRequest{DomainName: "a.com", Start: 1, End: 5})
+		}},
+		{"GetEntryAndProof", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetEntryAndProof(ctx, &ds.GetEntryAndProofRequest{DomainName: "a.com", Index: 1, DomainTreeSize: 3})
+		}},
+		{"GetDomainTreeIndex", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetDomainTreeIndex(ctx, &ds.GetDomainTreeIndexRequest{DomainName: "a.com", LogIndex: 1, CertificateIndex: 0})
+		}},
+		{"GetSourceLogs", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetSourceLogs(ctx, &ds.GetSourceLogsRequest{Start: 0, End: 1})
+		}},
+		{"GetSourceLogAndProof", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetSourceLogAndProof(ctx, &ds.GetSourceLogAndProofRequest{Index: 1, SourceTreeSize: 2})
+		}},
+		{"GetSourceConsistencyProof", func(mc *mapclient.MapClient) (interface{}, error) {
+			return mc.GetSourceConsistencyProof(ctx, &ds.GetSourceConsistencyProofRequest{First: 1, Second: 2})
+		}},
+	}
+	for _, tt := range calls {
+		want, err := tt.call(hc)
+		if err != nil {
+			t.Errorf("%s (HTTP): %v", tt.name, err)
+			continue
+		}
+		got, err := tt.call(gc)
+		if err != nil {
+			t.Errorf("%s (gRPC): %v", tt.name, err)
+			continue
+		}
+		if !sameJSON(t, got, want) {
+			t.Errorf("%s: got %+v over gRPC, expected %+v", tt.name, got, want)
+		}
+	}
+	if _, err := gc.GetAndVerifyInclusionPromise(ctx, &ds.GetInclusionPromiseRequest{DomainName: "d.com", LogIndex: 1, CertificateIndex: 1}); err != nil {
+		t.Errorf("GetAndVerifyInclusionPromise: %v", err)
+	}
+
+	var streamed []uint64
+	err = gc.StreamSMHRange(ctx, 0, second, func(smh *dt.SignedMapHead) error {
+		streamed = append(streamed, smh.MapSize)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(streamed, []uint64{first, second}) {
+		t.Errorf("StreamSMHRange: got map sizes %v (%v), expected %v", streamed, err, []uint64{first, second})
+	}
+
+	// The generated stubs only need the protobuf messages
+	stub := dtpb.NewDomainTransparencyClient(conn)
+	resp, err := stub.GetSMH(ctx, &dtpb.GetSMHRequest{MapSize: &first})
+	if err != nil {
+		t.Fatalf("stub GetSMH: %v", err)
+	}
+	if want := dm.GetSMH(first); resp.Smh.MapSize != first || !bytes.Equal(resp.Smh.MapRootHash, want.MapRootHash[:]) ||
+		!bytes.Equal(resp.Smh.MapHeadSignature, want.MapHeadSignature) {
+		t.Errorf("stub GetSMH: got %v, expected the SMH with map size %d", resp.Smh, first)
+	}
+	_, err = stub.GetEntries(ctx, &dtpb.GetEntriesRequest{DomainName: "missing.com", Start: 0, End: 1})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("stub GetEntries for a missing domain: got %v, expected NotFound", err)
+	}
+	_, err = stub.GetEntries(ctx, &dtpb.GetEntriesRequest{DomainName: "a.com", Start: 2, End: 1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("stub GetEntries with an invalid range: got %v, expected InvalidArgument", err)
+	}
+}
@@ -1,28 +1,78 @@
 package ds
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
-	"strings"
-
-	"github.com/gorilla/schema"
+	"runtime/debug"
 )
 
-type httpError struct {
-	Base error
-	Code int
+// dtHandlerFunc and dtTextHandlerFunc handle GET queries, and are served through a cachedHandler.
+// The context is the request's context, which is cancelled if the client goes away.
+type dtHandlerFunc func(context.Context, url.Values) (interface{}, error)
+
+type dtTextHandlerFunc func(context.Context, url.Values) ([]byte, error)
+
+type dtPostHandlerFunc func(context.Context, io.Reader) (interface{}, error)
+
+// The dtHandler methods take typed requests, so that they serve both the HTTP API and
+// the gRPC API (see grpcServer). jsonQuery, textQuery and jsonBody adapt them to the HTTP API.
+
+// jsonQuery returns a dtHandlerFunc that decodes the query into the request of handler.
+func jsonQuery[Req, Resp any](handler func(context.Context, *Req) (*Resp, error)) dtHandlerFunc {
+	return func(ctx context.Context, query url.Values) (interface{}, error) {
+		var req Req
+		if err := decoder.Decode(&req, query); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// textQuery returns a dtTextHandlerFunc that decodes the query into the request of handler.
+func textQuery[Req any](handler func(context.Context, *Req) ([]byte, error)) dtTextHandlerFunc {
+	return func(ctx context.Context, query url.Values) ([]byte, error) {
+		var req Req
+		if err := decoder.Decode(&req, query); err != nil {
+			return nil, err
+		}
+		return handler(ctx, &req)
+	}
 }
 
-func (he httpError) Error() string {
-	return he.Base.Error()
+// jsonBody returns a dtPostHandlerFunc that decodes the JSON body into the request of handler.
+func jsonBody[Req, Resp any](handler func(context.Context, *Req) (*Resp, error)) dtPostHandlerFunc {
+	return func(ctx context.Context, body io.Reader) (interface{}, error) {
+		var req Req
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return nil, errorf(CodeInvalidArgument, "invalid request body: %s", err)
+		}
+		resp, err := handler(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
 }
 
-type dtHandlerFunc func(url.Values) (interface{}, error)
+// maxPostBodySize limits the size of request bodies accepted by dtPostHandlerFunc.
+const maxPostBodySize = 1 << 16
 
-func (handler dtHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	data, err := handler(r.URL.Query())
+func (handler dtPostHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer recoverPanic(w, r)
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		sendError(w, apiError{CodeInvalidArgument, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)})
+		return
+	}
+	data, err := handler(r.Context(), http.MaxBytesReader(w, r.Body, maxPostBodySize))
 	if err != nil {
 		sendError(w, err)
 	} else if err := sendJSON(w, data); err != nil {
@@ -30,30 +80,35 @@ func (handler dtHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func sendError(w http.ResponseWriter, err error) {
-	code := http.StatusNotFound
-	if he, ok := err.(httpError); ok {
-		code = he.Code
-		err = he.Base
+// recoverPanic sends an internal error if the handler panics.
+// It must be deferred before calling the handler.
+func recoverPanic(w http.ResponseWriter, r *http.Request) {
+	if v := recover(); v != nil {
+		fmt.Printf("Panic serving %q: %v\n%s", r.URL, v, debug.Stack())
+		sendError(w, errorf(CodeInternal, "internal error"))
 	}
+}
 
-	var errMsg string
-	if me, ok := err.(schema.MultiError); ok {
-		var sb strings.Builder
-		for k, v := range me {
-			fmt.Fprintf(&sb, "%s: %v\n", k, v)
-		}
-		errMsg = sb.String()
-	} else {
-		errMsg = err.Error()
+// sendError sends the JSON error response for an error returned by a handler (see toErrorResponse).
+func sendError(w http.ResponseWriter, err error) {
+	resp, status := toErrorResponse(err)
+	data, err := json.Marshal(&resp)
+	if err != nil {
+		panic(fmt.Errorf("unexpected error marshaling %T: %w", resp, err))
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if _, err := w.Write(data); err != nil {
+		fmt.Printf("Error sending error response: %v\n", err)
 	}
-	http.Error(w, errMsg, code)
 }
 
 func sendJSON(w http.ResponseWriter, v interface{}) error {
 	data, err := json.Marshal(v)
 	if err != nil {
-		sendError(w, httpError{err, http.StatusInternalServerError})
+		sendError(w, errorf(CodeInternal, "error marshaling response: %s", err))
+		return nil
 	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
@@ -1,8 +1,9 @@
 package ds
 
 import (
-	"fmt"
-	"net/url"
+	"context"
+	"crypto/sha256"
+	"errors"
 
 	"github.com/gorilla/schema"
 	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
@@ -14,27 +15,124 @@ var decoder = schema.NewDecoder()
 // A dtHandler handles requests to a domain transparency server.
 type dtHandler struct {
 	dm *dt.DomainMap
+
+	// CheckpointOrigin is the origin line of the checkpoints served by getCheckpoint.
+	// If empty, the origin is derived from the map's public key (see dt.DefaultCheckpointOrigin).
+	CheckpointOrigin string
+
+	// CTLogs provides the CT log entries returned by getDomainUpdates.
+	CTLogs *CTLogCache
+
+	// RateLimiter, if set, limits the requests of each client.
+	RateLimiter *RateLimiter
+
+	// Readiness, if set, tells whether the fetchers have caught up with their source logs (see readyz).
+	Readiness *Readiness
+
+	// shutdown is closed when the HTTP server is shut down, ending all subscriptions.
+	shutdown chan struct{}
+
+	// costs are the rate limiting costs of the HTTP paths, which also apply to the gRPC methods.
+	costs map[string]int
 }
 
 // GET /dt/v1/get-smh
 // Params:
 //
-//	<none>
+//	map_size: integer (optional, defaults to the latest SMH)
+//	cosignatures: boolean (optional)
 //
 // Response:
 //
+//	version: integer
 //	timestamp: integer
 //	map_size: integer
 //	map_root_hash: base64
 //	source_tree_root_hash: base64
 //	source_log_revisions: array of {tree_size: integer, root_hash: base64}
 //	map_head_signature: base64
-func (h *dtHandler) getSMH(query url.Values) (interface{}, error) {
-	var req GetSMHRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
+//	cosignatures: array of {witness_key_hash: base64, timestamp: integer, signature: base64} (only if requested)
+func (h *dtHandler) getSMH(ctx context.Context, req *GetSMHRequest) (*GetSMHResponse, error) {
+	if req.MapSize != nil {
+		var smh *dt.SignedMapHead
+		var cosigs []dt.MapHeadCosignature
+		if req.Cosignatures {
+			smh, cosigs = h.dm.GetSMHAndCosignatures(*req.MapSize)
+		} else {
+			smh = h.dm.GetSMH(*req.MapSize)
+		}
+		if smh == nil {
+			return nil, errorf(CodeNotFound, "no SMH with map size %d", *req.MapSize)
+		}
+		return &GetSMHResponse{SignedMapHead: *smh, Cosignatures: cosigs}, nil
+	}
+	if !req.Cosignatures {
+		return &GetSMHResponse{SignedMapHead: *h.dm.GetLatestSMH()}, nil
+	}
+	smh, cosigs := h.dm.GetLatestSMHAndCosignatures()
+	return &GetSMHResponse{SignedMapHead: *smh, Cosignatures: cosigs}, nil
+}
+
+// maxSMHRange is the maximum number of SMHs returned by get-smh-range.
+const maxSMHRange = 256
+
+// GET /dt/v1/get-smh-range
+// Params:
+//
+//	start: integer
+//	end: integer
+//
+// Response:
+//
+//	smhs: array of SMHs (as in get-smh)
+//
+// Returns the latest SMH for each published map size in [start, end], in
+// increasing order of map size. At most maxSMHRange SMHs are returned, so
+// clients should request the remaining ones starting after the last returned map size.
+func (h *dtHandler) getSMHRange(ctx context.Context, req *GetSMHRangeRequest) (*GetSMHRangeResponse, error) {
+	if req.Start > req.End {
+		return nil, errorf(CodeInvalidArgument, "invalid range: [%d,%d]", req.Start, req.End)
 	}
-	return (*GetSMHResponse)(h.dm.GetLatestSMH()), nil
+	smhs := h.dm.GetSMHRange(req.Start, req.End, maxSMHRange)
+	resp := GetSMHRangeResponse{
+		SMHs: make([]dt.SignedMapHead, len(smhs)),
+	}
+	for i, smh := range smhs {
+		resp.SMHs[i] = *smh
+	}
+	return &resp, nil
+}
+
+// GET /dt/v1/get-smh-at
+// Params:
+//
+//	timestamp: integer
+//
+// Response:
+//
+//	the SMH that was current at the specified timestamp (as in get-smh)
+func (h *dtHandler) getSMHAt(ctx context.Context, req *GetSMHAtRequest) (*GetSMHResponse, error) {
+	smh := h.dm.GetSMHAt(req.Timestamp)
+	if smh == nil {
+		return nil, errorf(CodeNotFound, "no SMH published at or before timestamp %d", req.Timestamp)
+	}
+	return &GetSMHResponse{SignedMapHead: *smh}, nil
+}
+
+// GET /dt/v1/checkpoint
+// Params:
+//
+//	<none>
+//
+// Response (text/plain):
+//
+//	the latest SMH as a signed note checkpoint (see dt.FormatCheckpoint)
+func (h *dtHandler) getCheckpoint(ctx context.Context, req *GetCheckpointRequest) ([]byte, error) {
+	checkpoint, err := h.dm.GetLatestCheckpoint(h.CheckpointOrigin)
+	if err != nil {
+		return nil, errorf(CodeUnavailable, "%s", err)
+	}
+	return checkpoint, nil
 }
 
 // GET /dt/v1/get-consistency-proof
@@ -47,23 +145,22 @@ func (h *dtHandler) getSMH(query url.Values) (interface{}, error) {
 // Response:
 //
 //	proof: array of base64
-func (h *dtHandler) getConsistencyProof(query url.Values) (interface{}, error) {
-	var req GetConsistencyProofRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+func (h *dtHandler) getConsistencyProof(ctx context.Context, req *GetConsistencyProofRequest) (*GetConsistencyProofResponse, error) {
 	if req.First >= req.Second {
-		return nil, fmt.Errorf("invalid sizes: first (%d) >= second (%d)", req.First, req.Second)
+		return nil, errorf(CodeInvalidArgument, "invalid sizes: first (%d) >= second (%d)", req.First, req.Second)
 	}
 
 	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
 	if err != nil {
-		return nil, fmt.Errorf("invalid domain name %q: %s", req.DomainName, err)
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
 	}
 
 	tree, err := h.dm.GetDomainTree(normalizedDomain)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeNotFound, "%s", err)
+	}
+	if _, err := tree.GetRoot(req.Second); err != nil {
+		return nil, errorf(CodeOutOfRange, "%s", err)
 	}
 	entries := tree.GetConsistencyProof(req.First, req.Second)
 
@@ -81,41 +178,183 @@ func (h *dtHandler) getConsistencyProof(query url.Values) (interface{}, error) {
 //	domain_tree_size: integer
 //	domain_tree_root_hash: base64
 //	normalized_domain_name: string
-//	leaf_hash: base64
-//	audit_path: array of base64
-func (h *dtHandler) getDomainRootAndProof(query url.Values) (interface{}, error) {
-	var req GetDomainRootAndProofRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+//	audit_path: array of base64 (from the leaf up to the map root)
+//	non_membership_leaf_data: base64 (optional)
+func (h *dtHandler) getDomainRootAndProof(ctx context.Context, req *GetDomainRootAndProofRequest) (*GetDomainRootAndProofResponse, error) {
 	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
 	if err != nil {
-		return nil, fmt.Errorf("invalid domain name %q: %s", req.DomainName, err)
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
 	}
 	smh := h.dm.GetSMH(req.DomainMapSize)
 	if smh == nil {
-		return nil, fmt.Errorf("invalid STHTreeSize: %d", req.DomainMapSize)
+		return nil, errorf(CodeNotFound, "no SMH with map size %d", req.DomainMapSize)
 	}
 	root := smh.MapRootHash[:]
 	dtr, err := h.dm.GetDomainTreeRoot(root, normalizedDomain)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeInternal, "%s", err)
 	}
 
 	proof, err := h.dm.GetProofForDomain(root, normalizedDomain)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeInternal, "%s", err)
 	}
 
 	resp := GetDomainRootAndProofResponse{
-		DomainTreeSize:       dtr.DomainTreeSize,
-		DomainTreeRootHash:   dtr.DomainTreeRootHash[:],
-		NormalizedDomainName: normalizedDomain,
-		AuditPath:            proof.Proof,
+		DomainTreeSize:        dtr.DomainTreeSize,
+		DomainTreeRootHash:    dtr.DomainTreeRootHash[:],
+		NormalizedDomainName:  normalizedDomain,
+		AuditPath:             proof.Proof,
+		NonMembershipLeafData: proof.NonMembershipLeafData,
+	}
+	return &resp, nil
+}
+
+// maxMultiProofDomains is the maximum number of domains in a single multiproof request.
+const maxMultiProofDomains = 256
+
+// GET /dt/v1/get-domain-roots-and-multiproof
+// Params:
+//
+//	domain_name: string (repeatable)
+//	domain_map_size: integer
+//
+// Response:
+//
+//	domains: array of {
+//	  domain_tree_size: integer,
+//	  domain_tree_root_hash: base64,
+//	  normalized_domain_name: string,
+//	  proof_depth: integer,
+//	  non_membership_leaf_data: base64 (optional)
+//	}
+//	side_nodes: array of base64
+func (h *dtHandler) getDomainRootsAndMultiProof(ctx context.Context, req *GetDomainRootsAndMultiProofRequest) (*GetDomainRootsAndMultiProofResponse, error) {
+	if len(req.DomainNames) > maxMultiProofDomains {
+		return nil, errorf(CodeInvalidArgument, "too many domains: %d > %d", len(req.DomainNames), maxMultiProofDomains)
+	}
+	smh := h.dm.GetSMH(req.DomainMapSize)
+	if smh == nil {
+		return nil, errorf(CodeNotFound, "no SMH with map size %d", req.DomainMapSize)
+	}
+	normalizedDomains, err := normalizeDomainNames(req.DomainNames)
+	if err != nil {
+		return nil, err
+	}
+	return h.domainRootsAndMultiProof(smh.MapRootHash[:], normalizedDomains)
+}
+
+// normalizeDomainNames normalizes the specified domain names, removing duplicates.
+func normalizeDomainNames(domains []string) ([]string, error) {
+	var normalizedDomains []string
+	seen := make(map[string]struct{})
+	for _, d := range domains {
+		normalizedDomain, err := util.NormalizeDomainName(d)
+		if err != nil {
+			return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", d, err)
+		}
+		if _, ok := seen[normalizedDomain]; !ok {
+			seen[normalizedDomain] = struct{}{}
+			normalizedDomains = append(normalizedDomains, normalizedDomain)
+		}
+	}
+	return normalizedDomains, nil
+}
+
+// domainRootsAndMultiProof returns the domain tree roots for the specified
+// (normalized and unique) domains, along with a multiproof for the specified map root.
+func (h *dtHandler) domainRootsAndMultiProof(root []byte, normalizedDomains []string) (*GetDomainRootsAndMultiProofResponse, error) {
+	proof, err := h.dm.GetMultiProofForDomains(root, normalizedDomains)
+	if err != nil {
+		return nil, errorf(CodeInternal, "%s", err)
+	}
+
+	resp := GetDomainRootsAndMultiProofResponse{
+		Domains:   make([]DomainRootInMultiProof, len(normalizedDomains)),
+		SideNodes: proof.SideNodes,
+	}
+	for i, d := range normalizedDomains {
+		dtr, err := h.dm.GetDomainTreeRoot(root, d)
+		if err != nil {
+			return nil, errorf(CodeInternal, "%s", err)
+		}
+		resp.Domains[i] = DomainRootInMultiProof{
+			DomainTreeSize:        dtr.DomainTreeSize,
+			DomainTreeRootHash:    dtr.DomainTreeRootHash[:],
+			NormalizedDomainName:  d,
+			ProofDepth:            proof.Depths[i],
+			NonMembershipLeafData: proof.NonMembershipLeafData[i],
+		}
+	}
+	return &resp, nil
+}
+
+// GET /dt/v1/get-changed-domains
+// Params:
+//
+//	first: integer (0 refers to the empty map)
+//	second: integer
+//	start: integer (optional)
+//
+// Response:
+//
+//	total: integer
+//	first: domain roots and multiproof for the first map size (as in get-domain-roots-and-multiproof)
+//	second: domain roots and multiproof for the second map size (as in get-domain-roots-and-multiproof)
+//
+// Lists the domains whose domain trees changed between the two map sizes,
+// starting at the `start`-th changed domain. At most maxMultiProofDomains
+// domains are returned; `first` and `second` list the same domains in the same order.
+func (h *dtHandler) getChangedDomains(ctx context.Context, req *GetChangedDomainsRequest) (*GetChangedDomainsResponse, error) {
+	if req.First > req.Second {
+		return nil, errorf(CodeInvalidArgument, "invalid sizes: first (%d) > second (%d)", req.First, req.Second)
+	}
+	firstRoot := make([]byte, sha256.Size)
+	if req.First != 0 {
+		smh := h.dm.GetSMH(req.First)
+		if smh == nil {
+			return nil, errorf(CodeNotFound, "no SMH with map size %d", req.First)
+		}
+		firstRoot = smh.MapRootHash[:]
+	}
+	smh := h.dm.GetSMH(req.Second)
+	if smh == nil {
+		return nil, errorf(CodeNotFound, "no SMH with map size %d", req.Second)
+	}
+	secondRoot := smh.MapRootHash[:]
+
+	changes, err := h.dm.GetChangedDomains(firstRoot, secondRoot)
+	if err != nil {
+		return nil, errorf(CodeInternal, "%s", err)
+	}
+	if req.Start > uint64(len(changes)) {
+		return nil, errorf(CodeOutOfRange, "invalid start: %d > number of changed domains (%d)", req.Start, len(changes))
+	}
+	page := changes[req.Start:]
+	if len(page) > maxMultiProofDomains {
+		page = page[:maxMultiProofDomains]
+	}
+	domains := make([]string, len(page))
+	for i, c := range page {
+		domains[i] = c.DomainName
+	}
+
+	resp := GetChangedDomainsResponse{Total: uint64(len(changes))}
+	first, err := h.domainRootsAndMultiProof(firstRoot, domains)
+	if err != nil {
+		return nil, err
+	}
+	second, err := h.domainRootsAndMultiProof(secondRoot, domains)
+	if err != nil {
+		return nil, err
 	}
+	resp.First, resp.Second = *first, *second
 	return &resp, nil
 }
 
+// maxGetEntries is the maximum number of entries returned by get-entries.
+const maxGetEntries = 1024
+
 // GET /dt/v1/get-entries
 // Params:
 //
@@ -126,33 +365,46 @@ func (h *dtHandler) getDomainRootAndProof(query url.Values) (interface{}, error)
 // Response:
 //
 //	entries: array of [integer, integer]
-func (h *dtHandler) getEntries(query url.Values) (interface{}, error) {
-	var req GetEntriesRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+//	leaf_hashes: array of base64
+//	certificate_fingerprints: array of base64
+//
+// Returns the entries in [start, end]. As in CT's get-entries, fewer entries are
+// returned if end is not in the domain tree or if the interval has more than
+// maxGetEntries entries, so clients should request the remaining ones starting
+// after the last returned entry.
+func (h *dtHandler) getEntries(ctx context.Context, req *GetEntriesRequest) (*GetEntriesResponse, error) {
 	if req.Start > req.End {
-		return nil, fmt.Errorf("invalid range: [%d,%d]", req.Start, req.End)
+		return nil, errorf(CodeInvalidArgument, "invalid range: [%d,%d]", req.Start, req.End)
 	}
 	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
 	if err != nil {
-		return nil, fmt.Errorf("invalid domain name %q: %s", req.DomainName, err)
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
+	}
+
+	end := req.End
+	if end-req.Start >= maxGetEntries {
+		end = req.Start + maxGetEntries - 1
 	}
 
 	tree, err := h.dm.GetDomainTree(normalizedDomain)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeNotFound, "%s", err)
 	}
-	entries, err := tree.GetEntries(req.Start, req.End)
+	entries, err := tree.GetEntries(req.Start, end)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeOutOfRange, "%s", err)
 	}
 
 	resp := GetEntriesResponse{
-		Entries: make([][2]uint64, len(entries)),
+		Entries:                 make([][2]uint64, len(entries)),
+		LeafHashes:              make([][]byte, len(entries)),
+		CertificateFingerprints: make([][]byte, len(entries)),
 	}
-	for i, e := range entries {
+	for i := range entries {
+		e := &entries[i]
 		resp.Entries[i] = [2]uint64{e.LogIndex, e.CertificateIndex}
+		resp.LeafHashes[i] = e.LeafHash[:]
+		resp.CertificateFingerprints[i] = e.CertificateFingerprint[:]
 	}
 
 	return &resp, nil
@@ -168,29 +420,29 @@ func (h *dtHandler) getEntries(query url.Values) (interface{}, error) {
 // Response:
 //
 //	entry: [integer, integer]
+//	leaf_hash: base64
+//	certificate_fingerprint: base64
 //	audit_path: array of base64
-func (h *dtHandler) getEntryAndProof(query url.Values) (interface{}, error) {
-	var req GetEntryAndProofRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+func (h *dtHandler) getEntryAndProof(ctx context.Context, req *GetEntryAndProofRequest) (*GetEntryAndProofResponse, error) {
 	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
 	if err != nil {
-		return nil, fmt.Errorf("invalid domain name %q: %s", req.DomainName, err)
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
 	}
 
 	tree, err := h.dm.GetDomainTree(normalizedDomain)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeNotFound, "%s", err)
 	}
 	entry, proof, err := tree.GetEntryAndProof(req.DomainTreeSize, req.Index)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeOutOfRange, "%s", err)
 	}
 
 	resp := GetEntryAndProofResponse{
-		Entry:     [2]uint64{entry.LogIndex, entry.CertificateIndex},
-		AuditPath: proof,
+		Entry:                  [2]uint64{entry.LogIndex, entry.CertificateIndex},
+		LeafHash:               entry.LeafHash[:],
+		CertificateFingerprint: entry.CertificateFingerprint[:],
+		AuditPath:              proof,
 	}
 	return &resp, nil
 }
@@ -205,31 +457,30 @@ func (h *dtHandler) getEntryAndProof(query url.Values) (interface{}, error) {
 // Response:
 //
 //	domain_tree_index: integer
-func (h *dtHandler) getDomainTreeIndex(query url.Values) (interface{}, error) {
-	var req GetDomainTreeIndexRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+func (h *dtHandler) getDomainTreeIndex(ctx context.Context, req *GetDomainTreeIndexRequest) (*GetDomainTreeIndexResponse, error) {
 	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
 	if err != nil {
-		return nil, fmt.Errorf("invalid domain name %q: %s", req.DomainName, err)
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
 	}
 
 	tree, err := h.dm.GetDomainTree(normalizedDomain)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeNotFound, "%s", err)
 	}
 	index, err := tree.EntryToDomainTreeIndex(dt.DomainTreeEntry{
 		LogIndex:         req.LogIndex,
 		CertificateIndex: req.CertificateIndex,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeNotFound, "%s", err)
 	}
 
 	return &GetDomainTreeIndexResponse{index}, nil
 }
 
+// maxGetSourceLogs is the maximum number of log IDs returned by get-source-logs.
+const maxGetSourceLogs = 1024
+
 // GET /dt/v1/get-source-logs
 // Params:
 //
@@ -239,17 +490,21 @@ func (h *dtHandler) getDomainTreeIndex(query url.Values) (interface{}, error) {
 // Response:
 //
 //	log_ids: array of base64
-func (h *dtHandler) getSourceLogs(query url.Values) (interface{}, error) {
-	var req GetSourceLogsRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+//
+// Returns the log IDs in [start, end]. As in get-entries, fewer log IDs are
+// returned if end is not in the source tree or if the interval has more than
+// maxGetSourceLogs entries.
+func (h *dtHandler) getSourceLogs(ctx context.Context, req *GetSourceLogsRequest) (*GetSourceLogsResponse, error) {
 	if req.Start > req.End {
-		return nil, fmt.Errorf("invalid range: [%d,%d]", req.Start, req.End)
+		return nil, errorf(CodeInvalidArgument, "invalid range: [%d,%d]", req.Start, req.End)
+	}
+	end := req.End
+	if end-req.Start >= maxGetSourceLogs {
+		end = req.Start + maxGetSourceLogs - 1
 	}
-	entries, err := h.dm.GetSourceTree().GetEntries(req.Start, req.End)
+	entries, err := h.dm.GetSourceTree().GetEntries(req.Start, end)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeOutOfRange, "%s", err)
 	}
 
 	resp := GetSourceLogsResponse{
@@ -272,14 +527,10 @@ func (h *dtHandler) getSourceLogs(query url.Values) (interface{}, error) {
 //
 //	log_id: base64
 //	audit_path: array of base64
-func (h *dtHandler) getSourceLogAndProof(query url.Values) (interface{}, error) {
-	var req GetSourceLogAndProofRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+func (h *dtHandler) getSourceLogAndProof(ctx context.Context, req *GetSourceLogAndProofRequest) (*GetSourceLogAndProofResponse, error) {
 	logID, proof, err := h.dm.GetSourceTree().GetEntryAndProof(req.SourceTreeSize, req.Index)
 	if err != nil {
-		return nil, fmt.Errorf("error: %s", err)
+		return nil, errorf(CodeOutOfRange, "%s", err)
 	}
 
 	resp := GetSourceLogAndProofResponse{
@@ -298,16 +549,82 @@ func (h *dtHandler) getSourceLogAndProof(query url.Values) (interface{}, error)
 // Response:
 //
 //	proof: array of base64
-func (h *dtHandler) getSourceConsistencyProof(query url.Values) (interface{}, error) {
-	var req GetSourceConsistencyProofRequest
-	if err := decoder.Decode(&req, query); err != nil {
-		return nil, err
-	}
+func (h *dtHandler) getSourceConsistencyProof(ctx context.Context, req *GetSourceConsistencyProofRequest) (*GetSourceConsistencyProofResponse, error) {
 	if req.First >= req.Second {
-		return nil, fmt.Errorf("invalid sizes: first (%d) >= second (%d)", req.First, req.Second)
+		return nil, errorf(CodeInvalidArgument, "invalid sizes: first (%d) >= second (%d)", req.First, req.Second)
+	}
+	if _, err := h.dm.GetSourceTree().GetRoot(req.Second); err != nil {
+		return nil, errorf(CodeOutOfRange, "%s", err)
 	}
 
 	proof := h.dm.GetSourceTree().GetConsistencyProof(req.First, req.Second)
 
 	return &GetSourceConsistencyProofResponse{proof}, nil
 }
+
+// GET /dt/v1/get-inclusion-promise
+// Params:
+//
+//	domain_name: string
+//	log_index: integer
+//	certificate_index: integer
+//
+// Response:
+//
+//	timestamp: integer
+//	log_index: integer
+//	certificate_index: integer
+//	normalized_domain_name: string
+//	promise_signature: base64
+func (h *dtHandler) getInclusionPromise(ctx context.Context, req *GetInclusionPromiseRequest) (*GetInclusionPromiseResponse, error) {
+	normalizedDomain, err := util.NormalizeDomainName(req.DomainName)
+	if err != nil {
+		return nil, errorf(CodeInvalidArgument, "invalid domain name %q: %s", req.DomainName, err)
+	}
+
+	promise, err := h.dm.IssueInclusionPromise(dt.DomainTreeEntry{
+		LogIndex:         req.LogIndex,
+		CertificateIndex: req.CertificateIndex,
+	}, normalizedDomain)
+	if errors.Is(err, dt.ErrReadOnly) {
+		return nil, errorf(CodeUnavailable, "%s", err)
+	} else if err != nil {
+		return nil, errorf(CodeNotFound, "%s", err)
+	}
+
+	resp := GetInclusionPromiseResponse{
+		Timestamp:            promise.Timestamp,
+		LogIndex:             promise.LogIndex,
+		CertificateIndex:     promise.CertificateIndex,
+		NormalizedDomainName: string(promise.DomainName),
+		PromiseSignature:     promise.Signature,
+	}
+	return &resp, nil
+}
+
+// POST /dt/v1/add-cosignature
+// Body (JSON):
+//
+//	map_size: integer
+//	witness_key_hash: base64
+//	timestamp: integer
+//	signature: base64
+//
+// Response:
+//
+//	<empty object>
+func (h *dtHandler) addCosignature(ctx context.Context, req *AddCosignatureRequest) (*AddCosignatureResponse, error) {
+	if len(req.WitnessKeyHash) != 32 {
+		return nil, errorf(CodeInvalidArgument, "invalid witness key hash: length=%d, expected 32", len(req.WitnessKeyHash))
+	}
+
+	cosig := dt.MapHeadCosignature{
+		Timestamp: req.Timestamp,
+		Signature: req.Signature,
+	}
+	copy(cosig.WitnessKeyHash[:], req.WitnessKeyHash)
+	if err := h.dm.AddCosignature(req.MapSize, cosig); err != nil {
+		return nil, errorf(CodeInvalidArgument, "%s", err)
+	}
+	return &AddCosignatureResponse{}, nil
+}
@@ -0,0 +1,171 @@
+package ds
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxLag is the default number of entries that a source log may be ahead of
+// the map for the server to be considered ready.
+const DefaultMaxLag = 1000
+
+// A Readiness tracks how far behind their source logs the fetchers are.
+// The server is ready when every log registered by a fetcher has caught up
+// to within MaxLag entries of its latest STH, and a signed SMH was published.
+// A mirror is ready when it has caught up to within MaxLag entries of the upstream map.
+type Readiness struct {
+	// MaxLag is the maximum number of entries of a log's latest STH
+	// that may not have been passed to the worker yet.
+	MaxLag uint64
+
+	m        sync.Mutex
+	logs     map[uint64]*logProgress // indexed by log index
+	upstream *upstreamProgress       // nil if not a mirror
+}
+
+type upstreamProgress struct {
+	mapSize  uint64
+	mirrored uint64
+	err      error // set if the mirror stopped
+}
+
+type logProgress struct {
+	sthSize   uint64
+	processed uint64
+	fetched   bool // whether an STH was fetched yet
+}
+
+// NewReadiness creates a Readiness with the specified MaxLag.
+func NewReadiness(maxLag uint64) *Readiness {
+	return &Readiness{MaxLag: maxLag, logs: make(map[uint64]*logProgress)}
+}
+
+// AddLog registers a source log, which isn't caught up until its first STH is fetched.
+func (r *Readiness) AddLog(logIndex uint64) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if _, ok := r.logs[logIndex]; !ok {
+		r.logs[logIndex] = &logProgress{}
+	}
+}
+
+// setProgress records the size of the latest STH of a log and the number
+// of entries that were passed to the worker.
+func (r *Readiness) setProgress(logIndex, sthSize, processed uint64) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.logs[logIndex] = &logProgress{sthSize, processed, true}
+}
+
+// SetUpstreamProgress records the map size of the latest upstream SMH and the map size
+// of the latest SMH published by the mirror.
+func (r *Readiness) SetUpstreamProgress(mapSize, mirrored uint64) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.upstream = &upstreamProgress{mapSize: mapSize, mirrored: mirrored}
+}
+
+// SetUpstreamError records why the mirror stopped following the upstream map.
+// The mirror isn't ready afterwards.
+func (r *Readiness) SetUpstreamError(err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.upstream == nil {
+		r.upstream = &upstreamProgress{}
+	}
+	r.upstream.err = err
+}
+
+// lagging returns a description of each log that hasn't caught up yet, in order of log index.
+func (r *Readiness) lagging() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	indices := make([]uint64, 0, len(r.logs))
+	for i := range r.logs {
+		indices = append(indices, i)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	var msgs []string
+	for _, i := range indices {
+		p := r.logs[i]
+		if !p.fetched {
+			msgs = append(msgs, fmt.Sprintf("log %d: no STH fetched yet", i))
+		} else if lag := p.sthSize - p.processed; lag > r.MaxLag {
+			msgs = append(msgs, fmt.Sprintf("log %d: %d of %d entries processed", i, p.processed, p.sthSize))
+		}
+	}
+	if u := r.upstream; u != nil {
+		if u.err != nil {
+			msgs = append(msgs, fmt.Sprintf("upstream map: stopped mirroring: %v", u.err))
+		} else if u.mapSize-u.mirrored > r.MaxLag {
+			msgs = append(msgs, fmt.Sprintf("upstream map: %d of %d entries mirrored", u.mirrored, u.mapSize))
+		}
+	}
+	return msgs
+}
+
+// errNotReady is returned by the data endpoints until the map publishes its first signed SMH.
+var errNotReady = errorf(CodeUnavailable, "not ready: no signed SMH was published yet, the map is still catching up with its source logs")
+
+// checkReady returns errNotReady if the map hasn't published a signed SMH yet.
+func (h *dtHandler) checkReady() error {
+	if !h.dm.HasPublishedSMH() {
+		return errNotReady
+	}
+	return nil
+}
+
+// requireSMH wraps the handler of a data endpoint, responding with errNotReady
+// until the map publishes its first signed SMH.
+func (h *dtHandler) requireSMH(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h.checkReady(); err != nil {
+			sendError(w, err)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// GET /healthz
+// Response (text/plain):
+//
+//	ok
+//
+// Always succeeds while the server is running.
+func (h *dtHandler) healthz(w http.ResponseWriter, r *http.Request) {
+	sendProbe(w, nil)
+}
+
+// GET /readyz
+// Response (text/plain):
+//
+//	ok, or the reasons why the server isn't ready (with status 503)
+//
+// The server is ready once a signed SMH was published and every source log
+// has caught up to within Readiness.MaxLag entries of its latest STH.
+func (h *dtHandler) readyz(w http.ResponseWriter, r *http.Request) {
+	var reasons []string
+	if !h.dm.HasPublishedSMH() {
+		reasons = append(reasons, "no signed SMH was published yet")
+	}
+	if h.Readiness != nil {
+		reasons = append(reasons, h.Readiness.lagging()...)
+	}
+	sendProbe(w, reasons)
+}
+
+func sendProbe(w http.ResponseWriter, reasons []string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if len(reasons) != 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "not ready\n%s\n", strings.Join(reasons, "\n"))
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,89 @@
+package ds
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/trillian/merkle/rfc6962"
+)
+
+// A LeafStore is an on-disk, content-addressed store of CT log entries. Each entry is saved
+// in a file named after the leaf hash of its leaf input, so the same certificate is only
+// stored once, and corrupted files are detected when they are read.
+type LeafStore struct {
+	dir string
+}
+
+// NewLeafStore opens the LeafStore in the specified directory, creating it if needed.
+func NewLeafStore(dir string) (*LeafStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating the leaf store: %w", err)
+	}
+	return &LeafStore{dir: dir}, nil
+}
+
+// leafHashOf returns the RFC 6962 leaf hash of a CT log entry.
+func leafHashOf(leaf *ct.LeafEntry) ct.SHA256Hash {
+	var h ct.SHA256Hash
+	copy(h[:], rfc6962.DefaultHasher.HashLeaf(leaf.LeafInput))
+	return h
+}
+
+func (s *LeafStore) path(leafHash ct.SHA256Hash) string {
+	name := hex.EncodeToString(leafHash[:])
+	return filepath.Join(s.dir, name[:2], name)
+}
+
+// Put saves a CT log entry, unless an entry with the same leaf hash is already stored.
+func (s *LeafStore) Put(leaf *ct.LeafEntry) error {
+	path := s.path(leafHashOf(leaf))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	data, err := json.Marshal(leaf)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that readers never see partial entries
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Get returns the CT log entry with the specified leaf hash, or nil if it isn't stored.
+func (s *LeafStore) Get(leafHash ct.SHA256Hash) (*ct.LeafEntry, error) {
+	data, err := os.ReadFile(s.path(leafHash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var leaf ct.LeafEntry
+	if err := json.Unmarshal(data, &leaf); err != nil {
+		return nil, fmt.Errorf("invalid entry %x in the leaf store: %w", leafHash, err)
+	}
+	if h := leafHashOf(&leaf); h != leafHash {
+		return nil, fmt.Errorf("corrupted entry %x in the leaf store: leaf hash is %x", leafHash, h)
+	}
+	return &leaf, nil
+}
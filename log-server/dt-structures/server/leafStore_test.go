@@ -0,0 +1,83 @@
+package ds_test
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/trillian/merkle/rfc6962"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+func TestLeafStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := ds.NewLeafStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := &ct.LeafEntry{LeafInput: []byte("leaf input"), ExtraData: []byte("extra data")}
+	var leafHash ct.SHA256Hash
+	copy(leafHash[:], rfc6962.DefaultHasher.HashLeaf(leaf.LeafInput))
+
+	if got, err := store.Get(leafHash); err != nil || got != nil {
+		t.Fatalf("Get (missing): got %v, %v; expected nil, nil", got, err)
+	}
+	if err := store.Put(leaf); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, err := store.Get(leafHash)
+	if err != nil || got == nil || string(got.LeafInput) != string(leaf.LeafInput) || string(got.ExtraData) != string(leaf.ExtraData) {
+		t.Fatalf("Get: got %v, %v; expected %v", got, err, leaf)
+	}
+
+	// the same leaf is only stored once, and the stored copy is kept
+	name := hex.EncodeToString(leafHash[:])
+	path := filepath.Join(dir, name[:2], name)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(&ct.LeafEntry{LeafInput: leaf.LeafInput, ExtraData: []byte("other extra data")}); err != nil {
+		t.Fatalf("Put (duplicate): %v", err)
+	}
+	if got, err := store.Get(leafHash); err != nil || string(got.ExtraData) != string(leaf.ExtraData) {
+		t.Errorf("Get after duplicate Put: got %v, %v; expected the first entry", got, err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d files after duplicate Put, expected 1", len(entries))
+	}
+	if newInfo, err := os.Stat(path); err != nil || !newInfo.ModTime().Equal(info.ModTime()) {
+		t.Errorf("duplicate Put rewrote the stored entry")
+	}
+
+	// corrupted entries are detected
+	other := &ct.LeafEntry{LeafInput: []byte("another leaf input")}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(other); err != nil {
+		t.Fatal(err)
+	}
+	var otherHash ct.SHA256Hash
+	copy(otherHash[:], rfc6962.DefaultHasher.HashLeaf(other.LeafInput))
+	otherName := hex.EncodeToString(otherHash[:])
+	if err := os.WriteFile(filepath.Join(dir, otherName[:2], otherName), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get(otherHash); err == nil {
+		t.Errorf("Get (corrupted): got %v, expected an error", got)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Get(leafHash); err == nil {
+		t.Errorf("Get (invalid JSON): got %v, expected an error", got)
+	}
+}
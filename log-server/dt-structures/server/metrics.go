@@ -0,0 +1,107 @@
+package ds
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/status"
+)
+
+// Prometheus metrics of the server and the fetchers, exposed at /metrics along with
+// the metrics of the domain map and the map store.
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dt_http_requests_total",
+		Help: "Number of HTTP requests, by endpoint and status code.",
+	}, []string{"endpoint", "code"})
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "dt_http_request_duration_seconds",
+		Help:    "Latency of HTTP requests, by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dt_grpc_requests_total",
+		Help: "Number of unary gRPC requests, by method and status code.",
+	}, []string{"method", "code"})
+	grpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "dt_grpc_request_duration_seconds",
+		Help:    "Latency of unary gRPC requests, by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	fetcherSTHSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dt_fetcher_sth_size",
+		Help: "Tree size of the latest STH fetched from each source log, by log index.",
+	}, []string{"log"})
+	fetcherFetchedIndex = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dt_fetcher_fetched_index",
+		Help: "Number of entries of each source log passed to the worker, by log index.",
+	}, []string{"log"})
+	fetcherLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "dt_fetcher_lag_entries",
+		Help: "Number of entries of the latest STH of each source log not yet passed to the worker, by log index.",
+	}, []string{"log"})
+	fetcherErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dt_fetcher_errors_total",
+		Help: "Number of failed fetcher iterations, by log index.",
+	}, []string{"log"})
+)
+
+// setFetcherProgress updates the fetcher metrics of a log, where fetched is the number of
+// entries already passed to the worker. The lag is zero if the log's STH is behind the fetcher.
+func setFetcherProgress(logIndex uint64, sthSize, fetched uint64) {
+	log := strconv.FormatUint(logIndex, 10)
+	fetcherSTHSize.WithLabelValues(log).Set(float64(sthSize))
+	fetcherFetchedIndex.WithLabelValues(log).Set(float64(fetched))
+	var lag uint64
+	if sthSize > fetched {
+		lag = sthSize - fetched
+	}
+	fetcherLag.WithLabelValues(log).Set(float64(lag))
+}
+
+// statusRecorder is a ResponseWriter that records the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// instrumented wraps the handler of an endpoint with the HTTP request metrics.
+// It should not be used for streaming endpoints, since it doesn't implement http.Flusher.
+func instrumented(endpoint string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			httpRequests.WithLabelValues(endpoint, strconv.Itoa(rec.status)).Inc()
+			httpRequestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+		}()
+		h.ServeHTTP(rec, r)
+	})
+}
+
+// observeGRPCRequest updates the gRPC request metrics of a method.
+func observeGRPCRequest(method string, start time.Time, err error) {
+	grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	grpcRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
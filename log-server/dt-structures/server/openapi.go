@@ -0,0 +1,268 @@
+package ds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	ct "github.com/google/certificate-transparency-go"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+)
+
+// An apiEndpoint describes an endpoint of the JSON HTTP API in the OpenAPI document.
+// The query parameters (or the body, for POST endpoints) are generated from the request
+// type, and the response schema from the response type.
+type apiEndpoint struct {
+	path        string
+	method      string
+	summary     string
+	request     interface{}
+	response    interface{} // nil if contentType isn't JSON
+	contentType string      // defaults to application/json
+}
+
+var apiEndpoints = []apiEndpoint{
+	{path: "/dt/v1/get-smh", method: "get", summary: "Get the latest SMH, or the SMH with the specified map size",
+		request: GetSMHRequest{}, response: GetSMHResponse{}},
+	{path: "/dt/v1/get-smh-range", method: "get", summary: "Get the SMHs with map sizes in [start, end]",
+		request: GetSMHRangeRequest{}, response: GetSMHRangeResponse{}},
+	{path: "/dt/v1/get-smh-at", method: "get", summary: "Get the SMH that was current at the specified timestamp",
+		request: GetSMHAtRequest{}, response: GetSMHResponse{}},
+	{path: "/dt/v1/checkpoint", method: "get", summary: "Get the latest SMH as a signed note checkpoint",
+		request: GetCheckpointRequest{}, contentType: "text/plain"},
+	{path: "/dt/v1/get-domain-root-and-proof", method: "get", summary: "Get the root of a domain tree and its proof in the map",
+		request: GetDomainRootAndProofRequest{}, response: GetDomainRootAndProofResponse{}},
+	{path: "/dt/v1/get-domain-roots-and-multiproof", method: "get", summary: "Get the roots of several domain trees and a single proof in the map",
+		request: GetDomainRootsAndMultiProofRequest{}, response: GetDomainRootsAndMultiProofResponse{}},
+	{path: "/dt/v1/get-changed-domains", method: "get", summary: "List the domains changed between two SMHs",
+		request: GetChangedDomainsRequest{}, response: GetChangedDomainsResponse{}},
+	{path: "/dt/v1/get-domain-updates", method: "get", summary: "Get the new entries of a domain tree with all their proofs",
+		request: GetDomainUpdatesRequest{}, response: GetDomainUpdatesResponse{}},
+	{path: "/dt/v1/get-certificate-placements", method: "get", summary: "List the domain trees that contain a certificate",
+		request: GetCertificatePlacementsRequest{}, response: GetCertificatePlacementsResponse{}},
+	{path: "/dt/v1/get-consistency-proof", method: "get", summary: "Get a consistency proof between two sizes of a domain tree",
+		request: GetConsistencyProofRequest{}, response: GetConsistencyProofResponse{}},
+	{path: "/dt/v1/get-entries", method: "get", summary: "Get the entries of a domain tree",
+		request: GetEntriesRequest{}, response: GetEntriesResponse{}},
+	{path: "/dt/v1/get-certificates", method: "get", summary: "Get the CT log entries of a domain tree with their CT inclusion proofs",
+		request: GetCertificatesRequest{}, response: GetCertificatesResponse{}},
+	{path: "/dt/v1/get-entry-and-proof", method: "get", summary: "Get an entry of a domain tree and its audit path",
+		request: GetEntryAndProofRequest{}, response: GetEntryAndProofResponse{}},
+	{path: "/dt/v1/get-domain-tree-index", method: "get", summary: "Get the index of a certificate in a domain tree",
+		request: GetDomainTreeIndexRequest{}, response: GetDomainTreeIndexResponse{}},
+	{path: "/dt/v1/get-source-logs", method: "get", summary: "Get the entries of the source tree",
+		request: GetSourceLogsRequest{}, response: GetSourceLogsResponse{}},
+	{path: "/dt/v1/get-source-log-and-proof", method: "get", summary: "Get an entry of the source tree and its audit path",
+		request: GetSourceLogAndProofRequest{}, response: GetSourceLogAndProofResponse{}},
+	{path: "/dt/v1/get-source-consistency-proof", method: "get", summary: "Get a consistency proof between two sizes of the source tree",
+		request: GetSourceConsistencyProofRequest{}, response: GetSourceConsistencyProofResponse{}},
+	{path: "/dt/v1/get-inclusion-promise", method: "get", summary: "Get a signed promise to include a certificate in a domain tree",
+		request: GetInclusionPromiseRequest{}, response: GetInclusionPromiseResponse{}},
+	{path: "/dt/v1/add-cosignature", method: "post", summary: "Add a witness cosignature to an SMH",
+		request: AddCosignatureRequest{}, response: AddCosignatureResponse{}},
+	{path: "/dt/v1/subscribe", method: "get", summary: "Receive new SMHs and domain updates as Server-Sent Events",
+		request: SubscribeRequest{}, contentType: "text/event-stream"},
+}
+
+var (
+	openAPIOnce     sync.Once
+	openAPIDocument []byte
+)
+
+// OpenAPIDocument returns the OpenAPI 3.0 document of the JSON HTTP API, served at /dt/v1/openapi.json.
+func OpenAPIDocument() []byte {
+	openAPIOnce.Do(func() {
+		doc, err := json.Marshal(generateOpenAPI(apiEndpoints))
+		if err != nil {
+			panic(fmt.Errorf("error marshaling the OpenAPI document: %w", err))
+		}
+		openAPIDocument = doc
+	})
+	return openAPIDocument
+}
+
+// getOpenAPI serves OpenAPIDocument through a cachedHandler.
+func getOpenAPI(context.Context, url.Values) (*cachedResponse, error) {
+	return newCachedResponse(OpenAPIDocument(), "application/json; charset=utf-8"), nil
+}
+
+type jsonObject = map[string]interface{}
+
+// openAPIGenerator generates the schemas of Go types, as encoding/json encodes them.
+// Named struct types are added to the components of the document.
+type openAPIGenerator struct {
+	schemas jsonObject
+}
+
+func generateOpenAPI(endpoints []apiEndpoint) jsonObject {
+	g := &openAPIGenerator{schemas: make(jsonObject)}
+	errorResponse := jsonObject{
+		"description": "An error (see the codes in API.md)",
+		"content":     jsonObject{"application/json": jsonObject{"schema": g.schema(reflect.TypeOf(ErrorResponse{}))}},
+	}
+
+	paths := make(jsonObject)
+	for _, e := range endpoints {
+		op := jsonObject{
+			"operationId": strings.TrimPrefix(e.path, "/dt/v1/"),
+			"summary":     e.summary,
+		}
+		requestType := reflect.TypeOf(e.request)
+		if e.method == "post" {
+			op["requestBody"] = jsonObject{
+				"required": true,
+				"content":  jsonObject{"application/json": jsonObject{"schema": g.schema(requestType)}},
+			}
+		} else if params := g.queryParameters(requestType); len(params) != 0 {
+			op["parameters"] = params
+		}
+
+		var content jsonObject
+		if e.contentType == "" {
+			content = jsonObject{"application/json": jsonObject{"schema": g.schema(reflect.TypeOf(e.response))}}
+		} else {
+			content = jsonObject{e.contentType: jsonObject{"schema": jsonObject{"type": "string"}}}
+		}
+		op["responses"] = jsonObject{
+			"200":     jsonObject{"description": "OK", "content": content},
+			"default": errorResponse,
+		}
+		paths[e.path] = jsonObject{e.method: op}
+	}
+
+	return jsonObject{
+		"openapi": "3.0.3",
+		"info": jsonObject{
+			"title":   "Domain Transparency",
+			"version": "v1",
+		},
+		"paths":      paths,
+		"components": jsonObject{"schemas": g.schemas},
+	}
+}
+
+// queryParameters returns the query parameters of a request type, from its `schema` tags.
+func (g *openAPIGenerator) queryParameters(t reflect.Type) []interface{} {
+	var params []interface{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("schema"), ",")
+		if tag[0] == "" || tag[0] == "-" {
+			continue
+		}
+		param := jsonObject{
+			"name":     tag[0],
+			"in":       "query",
+			"required": len(tag) > 1 && tag[1] == "required",
+			"schema":   g.schema(f.Type),
+		}
+		if f.Type.Kind() == reflect.Slice {
+			param["explode"] = true
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+var sha256HashType = reflect.TypeOf(ct.SHA256Hash{})
+
+// schema returns the schema of the JSON encoding of t.
+func (g *openAPIGenerator) schema(t reflect.Type) jsonObject {
+	if t == sha256HashType {
+		return jsonObject{"type": "string", "format": "byte"} // see ct.SHA256Hash.MarshalJSON
+	}
+	if t.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) {
+		panic(fmt.Errorf("no OpenAPI schema for %s, which implements json.Marshaler", t))
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return jsonObject{"type": "boolean"}
+	case reflect.String:
+		return jsonObject{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return jsonObject{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return jsonObject{"type": "integer", "minimum": 0}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return jsonObject{"type": "string", "format": "byte"}
+		}
+		return jsonObject{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Array:
+		// Unlike slices, byte arrays are encoded as arrays of numbers.
+		return jsonObject{"type": "array", "items": g.schema(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		return jsonObject{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = nil // in case of recursion
+			g.schemas[name] = g.structSchema(t)
+		}
+		return jsonObject{"$ref": "#/components/schemas/" + name}
+	default:
+		panic(fmt.Errorf("no OpenAPI schema for %s", t))
+	}
+}
+
+// schemaName returns the name of a struct type in the components of the document,
+// prefixed by "dt." for the types of the dt package.
+func schemaName(t reflect.Type) string {
+	if t.PkgPath() == reflect.TypeOf(dt.SignedMapHead{}).PkgPath() {
+		return "dt." + t.Name()
+	}
+	return t.Name()
+}
+
+func (g *openAPIGenerator) structSchema(t reflect.Type) jsonObject {
+	properties := make(jsonObject)
+	required := []string{}
+	g.addFields(t, properties, &required)
+	s := jsonObject{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) != 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds the fields of a struct type to the properties of its schema,
+// including the fields of embedded structs, as encoding/json does.
+func (g *openAPIGenerator) addFields(t reflect.Type, properties jsonObject, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag[0] == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(f.Type, properties, required)
+			continue
+		}
+		if f.PkgPath != "" || tag[0] == "-" {
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = f.Name
+		}
+		s := g.schema(f.Type)
+		omitEmpty := len(tag) > 1 && tag[1] == "omitempty"
+		if !omitEmpty {
+			*required = append(*required, name)
+			switch f.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				if _, isRef := s["$ref"]; !isRef {
+					s["nullable"] = true
+				}
+			}
+		}
+		properties[name] = s
+	}
+}
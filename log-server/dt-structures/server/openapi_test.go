@@ -0,0 +1,388 @@
+package ds_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	"github.com/google/certificate-transparency-go/logid"
+	"github.com/google/trillian/merkle/rfc6962"
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+)
+
+type jsonObject = map[string]interface{}
+
+// A recordedResponse is a response received by the map client.
+type recordedResponse struct {
+	method, path string
+	status       int
+	mediaType    string
+	body         []byte // only for JSON responses
+}
+
+// recorder is an http.RoundTripper that records the responses received by the map client.
+type recorder struct {
+	m         sync.Mutex
+	responses []recordedResponse
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	rec := recordedResponse{method: strings.ToLower(req.Method), path: req.URL.Path, status: res.StatusCode}
+	rec.mediaType, _, _ = mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if rec.mediaType == "application/json" {
+		rec.body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(rec.body))
+	}
+	r.m.Lock()
+	r.responses = append(r.responses, rec)
+	r.m.Unlock()
+	return res, nil
+}
+
+// A testCert is a certificate added to the map by the test.
+type testCert struct {
+	logIndex, certIndex uint64
+	domains             []string
+}
+
+func (c testCert) leafInput() []byte {
+	return []byte{byte(c.logIndex), byte(c.certIndex), 1}
+}
+
+func (c testCert) leafHash() ct.SHA256Hash {
+	var h ct.SHA256Hash
+	copy(h[:], rfc6962.DefaultHasher.HashLeaf(c.leafInput()))
+	return h
+}
+
+// newFakeCTLog serves inclusion proofs for the leaf hashes of the certificates of a log.
+func newFakeCTLog(t *testing.T, certs []testCert) *client.LogClient {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ct/v1/get-proof-by-hash" {
+			http.NotFound(w, r)
+			return
+		}
+		for _, c := range certs {
+			if h := c.leafHash(); r.URL.Query().Get("hash") == base64.StdEncoding.EncodeToString(h[:]) {
+				json.NewEncoder(w).Encode(ct.GetProofByHashResponse{LeafIndex: int64(c.certIndex), AuditPath: [][]byte{{1}}})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(hs.Close)
+	lc, err := client.New(hs.URL, hs.Client(), jsonclient.Options{})
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	return lc
+}
+
+// addCertificates adds the certificates of a log to the map, and waits for the next SMH.
+func addCertificates(t *testing.T, dm *dt.DomainMap, c chan<- dt.WorkerTransaction, ctLogs *ds.CTLogCache, logIndex, treeSize uint64, certs []testCert) {
+	tx := dt.WorkerTransaction{
+		LogIndex:               logIndex,
+		LogID:                  logid.LogID{byte(logIndex + 1)},
+		LogRevision:            dt.LogRevision{TreeSize: treeSize},
+		NewCertificatesIndices: make(map[string][]uint64),
+		CertificateHashes:      make(map[uint64]dt.CertificateHashes),
+	}
+	for _, cert := range certs {
+		for _, d := range cert.domains {
+			tx.NewCertificatesIndices[d] = append(tx.NewCertificatesIndices[d], cert.certIndex)
+		}
+		tx.CertificateHashes[cert.certIndex] = dt.CertificateHashes{LeafHash: cert.leafHash(), Fingerprint: ct.SHA256Hash{byte(cert.certIndex), 2}}
+		ctLogs.AddLeaf(logIndex, cert.certIndex, ct.LeafEntry{LeafInput: cert.leafInput(), ExtraData: []byte{}})
+	}
+	ctLogs.AddLog(logIndex, newFakeCTLog(t, certs))
+
+	mapSize := dm.GetLatestSMH().MapSize
+	c <- tx
+	for deadline := time.Now().Add(10 * time.Second); dm.GetLatestSMH().MapSize == mapSize; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for an SMH")
+		}
+	}
+}
+
+// TestOpenAPIConformance calls every endpoint of the JSON HTTP API through mapclient,
+// and checks the responses against the schemas in the OpenAPI document.
+func TestOpenAPIConformance(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dt.NewDomainMap(key)
+	if _, err := dm.AddWitness(&witnessKey.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	svr, h := ds.NewServer(dm, "localhost", 0)
+	h.CTLogs = ds.NewCTLogCache()
+	hs := httptest.NewServer(svr.Handler)
+	defer hs.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, stopped := dt.StartWorker(ctx, dm, dt.WorkerConfig{BufferSize: 4, UpdatePeriod: 10 * time.Millisecond, MMD: time.Hour})
+	defer func() { cancel(); <-stopped }()
+
+	addCertificates(t, dm, c, h.CTLogs, 0, 3, []testCert{
+		{0, 0, []string{"a.com", "b.com"}},
+		{0, 1, []string{"a.com"}},
+		{0, 2, []string{"c.com"}},
+	})
+	first := dm.GetLatestSMH().MapSize
+	addCertificates(t, dm, c, h.CTLogs, 1, 2, []testCert{
+		{1, 0, []string{"a.com"}},
+		{1, 1, []string{"d.com"}},
+	})
+
+	rec := &recorder{}
+	mc := mapclient.New(hs.URL+"/", &http.Client{Transport: rec}, &key.PublicKey)
+	check := func(name string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	smh, err := mc.GetAndVerifySMH(ctx)
+	if err != nil {
+		t.Fatalf("GetAndVerifySMH: %v", err)
+	}
+	second := smh.MapSize
+	cosig, err := mapclient.CosignSMH(witnessKey, &witnessKey.PublicKey, smh)
+	check("CosignSMH", err)
+	check("AddCosignature", mc.AddCosignature(ctx, cosig))
+
+	_, err = mc.GetAndVerifySMHForSize(ctx, first)
+	check("GetAndVerifySMHForSize", err)
+	_, err = mc.GetAndVerifySMHRange(ctx, 0, second)
+	check("GetAndVerifySMHRange", err)
+	_, err = mc.GetAndVerifySMHAt(ctx, smh.Timestamp)
+	check("GetAndVerifySMHAt", err)
+	_, err = mc.GetAndVerifyCheckpoint(ctx)
+	check("GetAndVerifyCheckpoint", err)
+	_, err = mc.GetDomainRootAndProof(ctx, &ds.GetDomainRootAndProofRequest{DomainName: "a.com", DomainMapSize: second})
+	check("GetDomainRootAndProof", err)
+	_, err = mc.GetDomainRootsAndMultiProof(ctx, &ds.GetDomainRootsAndMultiProofRequest{DomainNames: []string{"a.com", "missing.com"}, DomainMapSize: second})
+	check("GetDomainRootsAndMultiProof", err)
+	_, err = mc.GetChangedDomains(ctx, &ds.GetChangedDomainsRequest{First: first, Second: second})
+	check("GetChangedDomains", err)
+	_, err = mc.GetDomainUpdates(ctx, &ds.GetDomainUpdatesRequest{DomainName: "a.com", From: 1, MapSize: second})
+	check("GetDomainUpdates", err)
+	_, err = mc.GetCertificatePlacements(ctx, &ds.GetCertificatePlacementsRequest{LogIndex: 0, CertificateIndex: 0, MapSize: second})
+	check("GetCertificatePlacements", err)
+	_, err = mc.GetConsistencyProof(ctx, &ds.GetConsistencyProofRequest{DomainName: "a.com", First: 1, Second: 3})
+	check("GetConsistencyProof", err)
+	_, err = mc.GetEntries(ctx, &ds.GetEntriesRequest{DomainName: "a.com", Start: 0, End: 2})
+	check("GetEntries", err)
+	_, err = mc.GetCertificates(ctx, &ds.GetCertificatesRequest{DomainName: "a.com", Start: 1, End: 5})
+	check("GetCertificates", err)
+	_, err = mc.GetEntryAndProof(ctx, &ds.GetEntryAndProofRequest{DomainName: "a.com", Index: 1, DomainTreeSize: 3})
+	check("GetEntryAndProof", err)
+	_, err = mc.GetDomainTreeIndex(ctx, &ds.GetDomainTreeIndexRequest{DomainName: "a.com", LogIndex: 1, CertificateIndex: 0})
+	check("GetDomainTreeIndex", err)
+	_, err = mc.GetSourceLogs(ctx, &ds.GetSourceLogsRequest{Start: 0, End: 1})
+	check("GetSourceLogs", err)
+	_, err = mc.GetSourceLogAndProof(ctx, &ds.GetSourceLogAndProofRequest{Index: 1, SourceTreeSize: 2})
+	check("GetSourceLogAndProof", err)
+	_, err = mc.GetSourceConsistencyProof(ctx, &ds.GetSourceConsistencyProofRequest{First: 1, Second: 2})
+	check("GetSourceConsistencyProof", err)
+	_, err = mc.GetAndVerifyInclusionPromise(ctx, &ds.GetInclusionPromiseRequest{DomainName: "d.com", LogIndex: 1, CertificateIndex: 1})
+	check("GetAndVerifyInclusionPromise", err)
+
+	errStop := errors.New("stop")
+	err = mc.Subscribe(context.Background(), []string{"a.com"}, func(*mapclient.SubscriptionEvent) error { return errStop })
+	if !errors.Is(err, errStop) {
+		t.Errorf("Subscribe: %v", err)
+	}
+
+	// Error responses
+	_, err = mc.GetEntries(ctx, &ds.GetEntriesRequest{DomainName: "missing.com", Start: 0, End: 1})
+	if !errors.Is(err, mapclient.ErrNotFound) {
+		t.Errorf("GetEntries for a missing domain: expected ErrNotFound, got %v", err)
+	}
+	_, err = mc.GetEntryAndProof(ctx, &ds.GetEntryAndProofRequest{DomainName: "a.com", Index: 5, DomainTreeSize: 3})
+	if err == nil {
+		t.Errorf("GetEntryAndProof out of range: expected an error")
+	}
+
+	res, err := http.Get(hs.URL + "/dt/v1/openapi.json")
+	if err != nil {
+		t.Fatalf("GET /dt/v1/openapi.json: %v", err)
+	}
+	var doc jsonObject
+	err = json.NewDecoder(res.Body).Decode(&doc)
+	res.Body.Close()
+	if err != nil {
+		t.Fatalf("error decoding the OpenAPI document: %v", err)
+	}
+
+	called := make(map[string]bool)
+	for _, r := range rec.responses {
+		op, ok := lookup(doc, "paths", r.path, r.method).(jsonObject)
+		if !ok {
+			t.Errorf("%s %s: not in the OpenAPI document", r.method, r.path)
+			continue
+		}
+		status := "default"
+		if r.status == http.StatusOK {
+			status = "200"
+			called[r.path] = true
+		}
+		content, _ := lookup(op, "responses", status, "content").(jsonObject)
+		mediaType, _ := lookup(content, r.mediaType).(jsonObject)
+		if mediaType == nil {
+			t.Errorf("%s %s: unexpected %d response with content type %q", r.method, r.path, r.status, r.mediaType)
+			continue
+		}
+		if r.body == nil {
+			continue
+		}
+		var body interface{}
+		if err := json.Unmarshal(r.body, &body); err != nil {
+			t.Errorf("%s %s: invalid JSON: %v", r.method, r.path, err)
+			continue
+		}
+		schema, _ := mediaType["schema"].(jsonObject)
+		if err := validate(doc, schema, body, "response"); err != nil {
+			t.Errorf("%s %s (%d): %v\n%s", r.method, r.path, r.status, err, r.body)
+		}
+	}
+	for path := range doc["paths"].(jsonObject) {
+		if !called[path] && path != "/dt/v1/openapi.json" {
+			t.Errorf("%s was not called successfully", path)
+		}
+	}
+}
+
+// lookup returns the value at the specified path of a JSON document, or nil.
+func lookup(v interface{}, keys ...string) interface{} {
+	for _, k := range keys {
+		obj, ok := v.(jsonObject)
+		if !ok {
+			return nil
+		}
+		v = obj[k]
+	}
+	return v
+}
+
+// validate checks a decoded JSON value against the subset of OpenAPI schemas used by the document.
+func validate(doc, schema jsonObject, v interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, ok := lookup(doc, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...).(jsonObject)
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %q", path, ref)
+		}
+		return validate(doc, resolved, v, path)
+	}
+	if v == nil {
+		if schema["nullable"] == true {
+			return nil
+		}
+		return fmt.Errorf("%s: unexpected null", path)
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := v.(jsonObject)
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %T", path, v)
+		}
+		properties, _ := schema["properties"].(jsonObject)
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing property %q", path, name)
+			}
+		}
+		for name, value := range obj {
+			s, ok := properties[name].(jsonObject)
+			if !ok {
+				s, ok = schema["additionalProperties"].(jsonObject)
+			}
+			if !ok {
+				return fmt.Errorf("%s: unexpected property %q", path, name)
+			}
+			if err := validate(doc, s, value, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array, got %T", path, v)
+		}
+		if min, ok := schema["minItems"].(float64); ok && float64(len(arr)) < min {
+			return fmt.Errorf("%s: expected at least %v items, got %d", path, min, len(arr))
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(arr)) > max {
+			return fmt.Errorf("%s: expected at most %v items, got %d", path, max, len(arr))
+		}
+		items, _ := schema["items"].(jsonObject)
+		for i, item := range arr {
+			if err := validate(doc, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string, got %T", path, v)
+		}
+		if schema["format"] == "byte" {
+			if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+				return fmt.Errorf("%s: invalid base64: %v", path, err)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: expected an integer, got %v", path, v)
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			return fmt.Errorf("%s: %v is less than %v", path, n, min)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected a boolean, got %T", path, v)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %v", path, schema["type"])
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package ds
+
+import (
+	"context"
+)
+
+// GET /dt/v1/get-certificate-placements
+// Params:
+//
+//	log_index: integer
+//	certificate_index: integer
+//	map_size: integer
+//	start: integer (optional)
+//
+// Response:
+//
+//	total: integer
+//	placements: array of {
+//	  normalized_domain_name: string,
+//	  index: integer,
+//	  entry, leaf_hash, certificate_fingerprint, audit_path: as in get-entry-and-proof
+//	}
+//	domain_roots: the roots of the domain trees of the returned placements
+//	  (as in get-domain-roots-and-multiproof)
+//
+// Lists the positions of the certificate with the specified index of a source log
+// in all domain trees of the map with the specified size, sorted by domain name and
+// index, starting at the `start`-th placement. At most maxMultiProofDomains
+// placements are returned. The audit paths are inclusion proofs in the domain trees
+// listed in domain_roots.
+func (h *dtHandler) getCertificatePlacements(ctx context.Context, req *GetCertificatePlacementsRequest) (*GetCertificatePlacementsResponse, error) {
+	smh := h.dm.GetSMH(req.MapSize)
+	if smh == nil {
+		return nil, errorf(CodeNotFound, "no SMH with map size %d", req.MapSize)
+	}
+	root := smh.MapRootHash[:]
+
+	var placements []GetCertificatePlacementsResponsePlacement
+	treeSizes := make(map[string]uint64)
+	for _, p := range h.dm.GetCertificatePlacements(req.LogIndex, req.CertificateIndex) {
+		treeSize, ok := treeSizes[p.DomainName]
+		if !ok {
+			dtr, err := h.dm.GetDomainTreeRoot(root, p.DomainName)
+			if err != nil {
+				return nil, errorf(CodeInternal, "%s", err)
+			}
+			treeSize = dtr.DomainTreeSize
+			treeSizes[p.DomainName] = treeSize
+		}
+		if p.Index < treeSize {
+			placements = append(placements, GetCertificatePlacementsResponsePlacement{
+				NormalizedDomainName: p.DomainName,
+				Index:                p.Index,
+			})
+		}
+	}
+	if req.Start > uint64(len(placements)) {
+		return nil, errorf(CodeOutOfRange, "invalid start: %d > number of placements (%d)", req.Start, len(placements))
+	}
+	page := placements[req.Start:]
+	if len(page) > maxMultiProofDomains {
+		page = page[:maxMultiProofDomains]
+	}
+
+	var domains []string
+	for i := range page {
+		p := &page[i]
+		if len(domains) == 0 || domains[len(domains)-1] != p.NormalizedDomainName {
+			domains = append(domains, p.NormalizedDomainName)
+		}
+		tree, err := h.dm.GetDomainTree(p.NormalizedDomainName)
+		if err != nil {
+			return nil, errorf(CodeInternal, "%s", err)
+		}
+		entry, proof, err := tree.GetEntryAndProof(treeSizes[p.NormalizedDomainName], p.Index)
+		if err != nil {
+			return nil, errorf(CodeInternal, "%s", err)
+		}
+		p.GetEntryAndProofResponse = GetEntryAndProofResponse{
+			Entry:                  [2]uint64{entry.LogIndex, entry.CertificateIndex},
+			LeafHash:               entry.LeafHash[:],
+			CertificateFingerprint: entry.CertificateFingerprint[:],
+			AuditPath:              proof,
+		}
+	}
+
+	resp := GetCertificatePlacementsResponse{
+		Total:       uint64(len(placements)),
+		Placements:  []GetCertificatePlacementsResponsePlacement{},
+		DomainRoots: GetDomainRootsAndMultiProofResponse{Domains: []DomainRootInMultiProof{}, SideNodes: [][]byte{}},
+	}
+	if len(page) == 0 {
+		return &resp, nil
+	}
+	domainRoots, err := h.domainRootsAndMultiProof(root, domains)
+	if err != nil {
+		return nil, err
+	}
+	resp.Placements, resp.DomainRoots = page, *domainRoots
+	return &resp, nil
+}
@@ -0,0 +1,208 @@
+package ds
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// APIKeyHeader is the HTTP header with which clients send their API key.
+// gRPC clients send it as metadata, with the lowercase name (see GRPCAPIKeyMetadata).
+const APIKeyHeader = "X-API-Key"
+
+// GRPCAPIKeyMetadata is the gRPC metadata key with which clients send their API key.
+const GRPCAPIKeyMetadata = "x-api-key"
+
+// rateLimiterSweepInterval is how often idle clients are removed from a RateLimiter.
+const rateLimiterSweepInterval = time.Minute
+
+// Costs of the requests to each endpoint, in tokens. Endpoints that only read
+// the trees cost costCheap, those that walk the sparse Merkle tree (and thus
+// hold the domain map's read lock) cost costSMT, and those that walk it for
+// many domains cost costMultiSMT.
+const (
+	costCheap    = 1
+	costSMT      = 5
+	costMultiSMT = 20
+)
+
+// A RateLimit is the rate of a token bucket, in tokens per second, and its size.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// A RateLimiter limits the requests of each client with a token bucket.
+// Clients are identified by their API key (see APIKeyHeader), if it is known,
+// or else by their IP address (or /64 prefix, for IPv6 addresses).
+// Each request takes as many tokens as the cost of its endpoint.
+type RateLimiter struct {
+	clientLimit RateLimit
+	keyLimits   map[string]RateLimit
+
+	m         sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a RateLimiter that limits each client IP to clientLimit.
+func NewRateLimiter(clientLimit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		clientLimit: clientLimit,
+		keyLimits:   make(map[string]RateLimit),
+		clients:     make(map[string]*clientLimiter),
+		lastSweep:   time.Now(),
+	}
+}
+
+// AddAPIKey accepts an API key, whose requests are limited to the specified limit.
+// It must be called before the server starts.
+func (l *RateLimiter) AddAPIKey(key string, limit RateLimit) {
+	l.keyLimits[key] = limit
+}
+
+// clientID returns the key of the bucket of the client with the specified API key (which may
+// be empty) and remote address, and its limit.
+func (l *RateLimiter) clientID(apiKey, remoteAddr string) (string, RateLimit) {
+	if apiKey != "" {
+		if limit, ok := l.keyLimits[apiKey]; ok {
+			return "key:" + apiKey, limit
+		}
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		host = ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+	}
+	return "ip:" + host, l.clientLimit
+}
+
+// reserve takes cost tokens from the bucket of the client with the specified API key and address.
+// If there aren't enough tokens, no tokens are taken and reserve returns how long the client should wait.
+func (l *RateLimiter) reserve(apiKey, remoteAddr string, cost int) (bool, time.Duration) {
+	id, limit := l.clientID(apiKey, remoteAddr)
+	now := time.Now()
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	if now.Sub(l.lastSweep) > rateLimiterSweepInterval {
+		l.sweep(now)
+	}
+	c, ok := l.clients[id]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)}
+		l.clients[id] = c
+	}
+	c.lastSeen = now
+
+	if cost > limit.Burst {
+		cost = limit.Burst
+	}
+	res := c.limiter.ReserveN(now, cost)
+	if !res.OK() {
+		return false, rateLimiterSweepInterval
+	}
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep removes the clients whose buckets were refilled since their last request.
+// Buckets that are never refilled (i.e. with a rate of 0) are kept.
+func (l *RateLimiter) sweep(now time.Time) {
+	for id, c := range l.clients {
+		if c.limiter.Limit() <= 0 {
+			continue
+		}
+		idle := time.Duration(float64(c.limiter.Burst()) / float64(c.limiter.Limit()) * float64(time.Second))
+		if now.Sub(c.lastSeen) > idle {
+			delete(l.clients, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimited wraps the handler of an endpoint, responding with CodeResourceExhausted and
+// a Retry-After header to the clients that exceed their rate limit (if h.RateLimiter is set).
+func (h *dtHandler) rateLimited(cost int, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.RateLimiter != nil {
+			if ok, wait := h.RateLimiter.reserve(r.Header.Get(APIKeyHeader), r.RemoteAddr, cost); !ok {
+				w.Header().Set("Retry-After", retryAfter(wait))
+				sendError(w, rateLimitError(wait))
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// retryAfter returns the value of the Retry-After header for the specified wait.
+func retryAfter(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
+
+func rateLimitError(wait time.Duration) error {
+	return errorf(CodeResourceExhausted, "rate limit exceeded, retry in %s", wait.Round(time.Millisecond))
+}
+
+// grpcRateLimit takes the tokens of a call to the gRPC method with the specified full name from
+// the bucket of the client (if h.RateLimiter is set). If the client exceeded its rate limit, it
+// returns a ResourceExhausted error and sets the "retry-after" header, as rateLimited does.
+func (h *dtHandler) grpcRateLimit(ctx context.Context, fullMethod string, setHeader func(metadata.MD) error) error {
+	if h.RateLimiter == nil {
+		return nil
+	}
+	var apiKey, addr string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(GRPCAPIKeyMetadata); len(keys) > 0 {
+			apiKey = keys[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	if ok, wait := h.RateLimiter.reserve(apiKey, addr, h.grpcCost(fullMethod)); !ok {
+		if err := setHeader(metadata.Pairs("retry-after", retryAfter(wait))); err != nil {
+			fmt.Printf("Error setting the retry-after header of %s: %v\n", fullMethod, err)
+		}
+		return grpcError(rateLimitError(wait))
+	}
+	return nil
+}
+
+// grpcUnaryRateLimit is a gRPC interceptor that applies the rate limits to unary calls.
+func (h *dtHandler) grpcUnaryRateLimit(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	setHeader := func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }
+	if err := h.grpcRateLimit(ctx, info.FullMethod, setHeader); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// grpcStreamRateLimit is a gRPC interceptor that applies the rate limits to streaming calls.
+func (h *dtHandler) grpcStreamRateLimit(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := h.grpcRateLimit(ss.Context(), info.FullMethod, ss.SetHeader); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,66 @@
+package ds_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net"
+	"testing"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/mapclient"
+	ds "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGRPCRateLimit(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, h := ds.NewServer(dt.NewDomainMap(key), "localhost", 0)
+	h.RateLimiter = ds.NewRateLimiter(ds.RateLimit{Rate: 1e-6, Burst: 1})
+	h.RateLimiter.AddAPIKey("secret", ds.RateLimit{Rate: 1e-6, Burst: 1})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr := ds.NewGRPCServer(h)
+	go svr.Serve(lis)
+	defer svr.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	mc := mapclient.NewGRPC(conn, &key.PublicKey)
+	mc.SetRetryPolicy(mapclient.RetryPolicy{})
+
+	getEntries := func(ctx context.Context) error {
+		_, err := mc.GetEntries(ctx, &ds.GetEntriesRequest{DomainName: "a.com"})
+		return err
+	}
+	ctx := context.Background()
+	if err := getEntries(ctx); errors.Is(err, mapclient.ErrResourceExhausted) {
+		t.Fatalf("first call: got error %v, expected it to be allowed", err)
+	}
+	var apiErr *mapclient.APIError
+	if err := getEntries(ctx); !errors.Is(err, mapclient.ErrResourceExhausted) || !errors.As(err, &apiErr) {
+		t.Fatalf("second call: got error %v, expected %v", err, mapclient.ErrResourceExhausted)
+	}
+	if apiErr.RetryAfter <= 0 {
+		t.Errorf("second call: got no retry-after header")
+	}
+	// API keys have their own bucket
+	keyCtx := metadata.AppendToOutgoingContext(ctx, ds.GRPCAPIKeyMetadata, "secret")
+	if err := getEntries(keyCtx); errors.Is(err, mapclient.ErrResourceExhausted) {
+		t.Fatalf("API key: got error %v, expected the call to be allowed", err)
+	}
+	if err := getEntries(keyCtx); !errors.Is(err, mapclient.ErrResourceExhausted) {
+		t.Fatalf("API key: got error %v, expected %v", err, mapclient.ErrResourceExhausted)
+	}
+}
@@ -0,0 +1,179 @@
+package ds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
+)
+
+const (
+	// maxSubscribedDomains is the maximum number of domains in a single subscription.
+	maxSubscribedDomains = 256
+	// subscriptionKeepAlive is how often a comment is sent to idle subscribers.
+	subscriptionKeepAlive = 30 * time.Second
+	// subscriptionWriteTimeout replaces the server's WriteTimeout for each event,
+	// since subscriptions are expected to outlive it.
+	subscriptionWriteTimeout = 10 * time.Second
+)
+
+// connContextKey is the request context key for the request's net.Conn (see NewServer).
+type connContextKey struct{}
+
+// GET /dt/v1/subscribe
+// Params:
+//
+//	domain_name: string (optional, repeatable)
+//
+// Response (text/event-stream):
+//
+//	a stream of Server-Sent Events, each one with the following event names and data:
+//	smh: an SMH (as in get-smh), sent upon connection and whenever an SMH is published
+//	domain: {normalized_domain_name: string, domain_tree_size: integer, map_size: integer},
+//	  sent after an smh event for each requested domain whose tree size changed
+//	  (after the first smh event, for each requested domain with a non-empty tree)
+//
+// The stream is closed if the client falls too far behind, in which case it should reconnect.
+func (h *dtHandler) subscribe(w http.ResponseWriter, r *http.Request) {
+	var req SubscribeRequest
+	if err := decoder.Decode(&req, r.URL.Query()); err != nil {
+		sendError(w, err)
+		return
+	}
+	if len(req.DomainNames) > maxSubscribedDomains {
+		sendError(w, errorf(CodeInvalidArgument, "too many domains: %d > %d", len(req.DomainNames), maxSubscribedDomains))
+		return
+	}
+	normalizedDomains, err := normalizeDomainNames(req.DomainNames)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendError(w, errorf(CodeInternal, "streaming is not supported"))
+		return
+	}
+
+	// Subscribe before fetching the latest SMH, so that no SMH is missed
+	smhs, cancel := h.dm.SubscribeSMHs()
+	defer cancel()
+
+	s := &subscription{
+		w:         w,
+		flusher:   flusher,
+		domains:   normalizedDomains,
+		treeSizes: make(map[string]uint64),
+		dm:        h.dm,
+	}
+	s.conn, _ = r.Context().Value(connContextKey{}).(net.Conn)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if err := s.sendSMH(h.dm.GetLatestSMH()); err != nil {
+		fmt.Printf("Error responding to %q: %v\n", r.URL, err)
+		return
+	}
+
+	keepAlive := time.NewTicker(subscriptionKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		var err error
+		select {
+		case smh, ok := <-smhs:
+			if !ok {
+				return
+			}
+			err = s.sendSMH(smh)
+		case <-keepAlive.C:
+			err = s.write([]byte(": keep-alive\n\n"))
+		case <-r.Context().Done():
+			return
+		case <-h.shutdown:
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error responding to %q: %v\n", r.URL, err)
+			return
+		}
+	}
+}
+
+// A subscription is the state of a single /dt/v1/subscribe stream.
+type subscription struct {
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	conn      net.Conn // may be nil
+	domains   []string // normalized
+	treeSizes map[string]uint64
+	dm        *dt.DomainMap
+	last      *dt.SignedMapHead
+}
+
+// sendSMH sends an smh event, followed by the domain events for that SMH.
+func (s *subscription) sendSMH(smh *dt.SignedMapHead) error {
+	if s.last != nil && bytes.Equal(s.last.MapHeadSignature, smh.MapHeadSignature) {
+		return nil
+	}
+	changedRoot := s.last == nil || s.last.MapRootHash != smh.MapRootHash
+	s.last = smh
+
+	var buf bytes.Buffer
+	if err := writeEvent(&buf, "smh", &GetSMHResponse{SignedMapHead: *smh}); err != nil {
+		return err
+	}
+	if changedRoot {
+		for _, domain := range s.domains {
+			dtr, err := s.dm.GetDomainTreeRoot(smh.MapRootHash[:], domain)
+			if err != nil {
+				return err
+			}
+			if dtr.DomainTreeSize == s.treeSizes[domain] {
+				continue
+			}
+			s.treeSizes[domain] = dtr.DomainTreeSize
+			event := DomainUpdateEvent{
+				NormalizedDomainName: domain,
+				DomainTreeSize:       dtr.DomainTreeSize,
+				MapSize:              smh.MapSize,
+			}
+			if err := writeEvent(&buf, "domain", &event); err != nil {
+				return err
+			}
+		}
+	}
+	return s.write(buf.Bytes())
+}
+
+func (s *subscription) write(data []byte) error {
+	if s.conn != nil {
+		if err := s.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout)); err != nil {
+			return err
+		}
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func writeEvent(buf *bytes.Buffer, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(buf, "event: %s\ndata: %s\n\n", event, data)
+	return nil
+}
+
+// saveConn stores the connection in the context of its requests, to be used by subscribe.
+func saveConn(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
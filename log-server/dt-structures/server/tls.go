@@ -0,0 +1,117 @@
+package ds
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// certCheckInterval is how often a CertificateReloader checks whether its files changed.
+const certCheckInterval = 10 * time.Second
+
+// A CertificateReloader serves a TLS certificate loaded from a pair of PEM files,
+// and reloads it when the files change (e.g. when the certificate is renewed).
+type CertificateReloader struct {
+	certFile, keyFile string
+
+	m         sync.Mutex
+	cert      *tls.Certificate
+	modTimes  [2]time.Time
+	lastCheck time.Time
+}
+
+// NewCertificateReloader loads the certificate and key from the specified files.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTimes); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// statFiles returns the modification times of the certificate and key files.
+func (r *CertificateReloader) statFiles() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for i, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// load loads the certificate and key. It must be called with r.m locked (or before r is shared).
+func (r *CertificateReloader) load(modTimes [2]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// GetCertificate returns the current certificate, reloading it if the files changed.
+// If reloading fails (e.g. while the files are being replaced), the previous certificate is kept.
+// It can be used as tls.Config.GetCertificate.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if time.Since(r.lastCheck) < certCheckInterval {
+		return r.cert, nil
+	}
+	r.lastCheck = time.Now()
+	modTimes, err := r.statFiles()
+	if err != nil {
+		fmt.Printf("Error checking TLS certificate files: %v\n", err)
+		return r.cert, nil
+	}
+	if modTimes != r.modTimes {
+		if err := r.load(modTimes); err != nil {
+			fmt.Printf("Error reloading TLS certificate: %v\n", err)
+		} else {
+			fmt.Printf("Reloaded TLS certificate from %s\n", r.certFile)
+		}
+	}
+	return r.cert, nil
+}
+
+// TLSConfig returns a TLS configuration that serves the reloaded certificate.
+func (r *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// AdminTLSConfig returns the TLS configuration of the admin server, which requires
+// clients to present a certificate issued by one of the CAs in the specified PEM files.
+func AdminTLSConfig(certs *CertificateReloader, clientCAFiles []string) (*tls.Config, error) {
+	if len(clientCAFiles) == 0 {
+		return nil, fmt.Errorf("no client CAs specified")
+	}
+	pool := x509.NewCertPool()
+	for _, name := range clientCAFiles {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client CA: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", name)
+		}
+	}
+	config := certs.TLSConfig()
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	config.ClientCAs = pool
+	return config, nil
+}
@@ -3,26 +3,130 @@ package ds
 import dt "github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures"
 
 type GetSMHRequest struct {
+	MapSize      *uint64 `schema:"map_size,omitempty" json:"map_size,omitempty"`
+	Cosignatures bool    `schema:"cosignatures" json:"cosignatures"`
 }
 
-type GetSMHResponse dt.SignedMapHead
+type GetSMHResponse struct {
+	dt.SignedMapHead
+	Cosignatures []dt.MapHeadCosignature `json:"cosignatures,omitempty"`
+}
+
+type GetSMHRangeRequest struct {
+	Start uint64 `schema:"start,required" json:"start"`
+	End   uint64 `schema:"end,required" json:"end"`
+}
+
+type GetSMHRangeResponse struct {
+	SMHs []dt.SignedMapHead `json:"smhs"`
+}
+
+type GetSMHAtRequest struct {
+	Timestamp uint64 `schema:"timestamp,required" json:"timestamp"`
+}
+
+type GetCheckpointRequest struct {
+}
 
 type GetDomainRootAndProofRequest struct {
-	DomainName    string `schema:"domain_name,required"`
-	DomainMapSize uint64 `schema:"domain_map_size,required"`
+	DomainName    string `schema:"domain_name,required" json:"domain_name"`
+	DomainMapSize uint64 `schema:"domain_map_size,required" json:"domain_map_size"`
 }
 
 type GetDomainRootAndProofResponse struct {
-	DomainTreeSize       uint64   `json:"domain_tree_size"`
-	DomainTreeRootHash   []byte   `json:"domain_tree_root_hash"`
-	NormalizedDomainName string   `json:"normalized_domain_name"`
-	AuditPath            [][]byte `json:"audit_path"`
+	DomainTreeSize        uint64   `json:"domain_tree_size"`
+	DomainTreeRootHash    []byte   `json:"domain_tree_root_hash"`
+	NormalizedDomainName  string   `json:"normalized_domain_name"`
+	AuditPath             [][]byte `json:"audit_path"`
+	NonMembershipLeafData []byte   `json:"non_membership_leaf_data,omitempty"`
+}
+
+type GetDomainRootsAndMultiProofRequest struct {
+	DomainNames   []string `schema:"domain_name,required" json:"domain_name"`
+	DomainMapSize uint64   `schema:"domain_map_size,required" json:"domain_map_size"`
+}
+
+type DomainRootInMultiProof struct {
+	DomainTreeSize        uint64 `json:"domain_tree_size"`
+	DomainTreeRootHash    []byte `json:"domain_tree_root_hash"`
+	NormalizedDomainName  string `json:"normalized_domain_name"`
+	ProofDepth            int    `json:"proof_depth"`
+	NonMembershipLeafData []byte `json:"non_membership_leaf_data,omitempty"`
+}
+
+type GetDomainRootsAndMultiProofResponse struct {
+	Domains   []DomainRootInMultiProof `json:"domains"`
+	SideNodes [][]byte                 `json:"side_nodes"`
+}
+
+type GetChangedDomainsRequest struct {
+	First  uint64 `schema:"first,required" json:"first"`
+	Second uint64 `schema:"second,required" json:"second"`
+	Start  uint64 `schema:"start" json:"start"`
+}
+
+type GetChangedDomainsResponse struct {
+	Total  uint64                              `json:"total"`
+	First  GetDomainRootsAndMultiProofResponse `json:"first"`
+	Second GetDomainRootsAndMultiProofResponse `json:"second"`
+}
+
+type GetDomainUpdatesRequest struct {
+	DomainName string `schema:"domain_name,required" json:"domain_name"`
+	From       uint64 `schema:"from,required" json:"from"`
+	MapSize    uint64 `schema:"map_size,required" json:"map_size"`
+	Start      uint64 `schema:"start" json:"start"`
+}
+
+// A DomainUpdateEntry is a domain tree entry in a get-domain-updates bundle,
+// along with its CT log entry.
+type DomainUpdateEntry struct {
+	Index uint64 `json:"index"`
+	GetEntryAndProofResponse
+	LeafInput   []byte   `json:"leaf_input"`
+	ExtraData   []byte   `json:"extra_data"`
+	CTAuditPath [][]byte `json:"ct_audit_path"`
+}
+
+// A SourceLogInBundle is a source log in a get-domain-updates bundle.
+type SourceLogInBundle struct {
+	LogIndex uint64 `json:"log_index"`
+	GetSourceLogAndProofResponse
+}
+
+type GetDomainUpdatesResponse struct {
+	SMH              GetSMHResponse                      `json:"smh"`
+	DomainRoot       GetDomainRootsAndMultiProofResponse `json:"domain_root"`
+	ConsistencyProof [][]byte                            `json:"consistency_proof"`
+	Entries          []DomainUpdateEntry                 `json:"entries"`
+	SourceLogs       []SourceLogInBundle                 `json:"source_logs"`
+}
+
+type GetCertificatePlacementsRequest struct {
+	LogIndex         uint64 `schema:"log_index,required" json:"log_index"`
+	CertificateIndex uint64 `schema:"certificate_index,required" json:"certificate_index"`
+	MapSize          uint64 `schema:"map_size,required" json:"map_size"`
+	Start            uint64 `schema:"start" json:"start"`
+}
+
+// A GetCertificatePlacementsResponsePlacement is the position of a certificate
+// in a domain tree, along with the entry and its audit path.
+type GetCertificatePlacementsResponsePlacement struct {
+	NormalizedDomainName string `json:"normalized_domain_name"`
+	Index                uint64 `json:"index"`
+	GetEntryAndProofResponse
+}
+
+type GetCertificatePlacementsResponse struct {
+	Total       uint64                                      `json:"total"`
+	Placements  []GetCertificatePlacementsResponsePlacement `json:"placements"`
+	DomainRoots GetDomainRootsAndMultiProofResponse         `json:"domain_roots"`
 }
 
 type GetConsistencyProofRequest struct {
-	DomainName string `schema:"domain_name,required"`
-	First      uint64 `schema:"first,required"`
-	Second     uint64 `schema:"second,required"`
+	DomainName string `schema:"domain_name,required" json:"domain_name"`
+	First      uint64 `schema:"first,required" json:"first"`
+	Second     uint64 `schema:"second,required" json:"second"`
 }
 
 type GetConsistencyProofResponse struct {
@@ -30,30 +134,54 @@ type GetConsistencyProofResponse struct {
 }
 
 type GetEntriesRequest struct {
-	DomainName string `schema:"domain_name,required"`
-	Start      uint64 `schema:"start,required"`
-	End        uint64 `schema:"end,required"`
+	DomainName string `schema:"domain_name,required" json:"domain_name"`
+	Start      uint64 `schema:"start,required" json:"start"`
+	End        uint64 `schema:"end,required" json:"end"`
 }
 
 type GetEntriesResponse struct {
-	Entries [][2]uint64 `json:"entries"`
+	Entries                 [][2]uint64 `json:"entries"`
+	LeafHashes              [][]byte    `json:"leaf_hashes,omitempty"`
+	CertificateFingerprints [][]byte    `json:"certificate_fingerprints,omitempty"`
+}
+
+type GetCertificatesRequest struct {
+	DomainName string `schema:"domain_name,required" json:"domain_name"`
+	Start      uint64 `schema:"start,required" json:"start"`
+	End        uint64 `schema:"end,required" json:"end"`
+}
+
+// A CertificateEntry is the CT log entry of a domain tree entry, along with
+// its inclusion proof in the source log.
+type CertificateEntry struct {
+	Entry       [2]uint64 `json:"entry"`
+	LeafInput   []byte    `json:"leaf_input"`
+	ExtraData   []byte    `json:"extra_data"`
+	CTAuditPath [][]byte  `json:"ct_audit_path"`
+}
+
+type GetCertificatesResponse struct {
+	MapSize      uint64             `json:"map_size"`
+	Certificates []CertificateEntry `json:"certificates"`
 }
 
 type GetEntryAndProofRequest struct {
-	DomainName     string `schema:"domain_name,required"`
-	Index          uint64 `schema:"index,required"`
-	DomainTreeSize uint64 `schema:"domain_tree_size,required"`
+	DomainName     string `schema:"domain_name,required" json:"domain_name"`
+	Index          uint64 `schema:"index,required" json:"index"`
+	DomainTreeSize uint64 `schema:"domain_tree_size,required" json:"domain_tree_size"`
 }
 
 type GetEntryAndProofResponse struct {
-	Entry     [2]uint64 `json:"entry"`
-	AuditPath [][]byte  `json:"audit_path"`
+	Entry                  [2]uint64 `json:"entry"`
+	LeafHash               []byte    `json:"leaf_hash,omitempty"`
+	CertificateFingerprint []byte    `json:"certificate_fingerprint,omitempty"`
+	AuditPath              [][]byte  `json:"audit_path"`
 }
 
 type GetDomainTreeIndexRequest struct {
-	DomainName       string `schema:"domain_name,required"`
-	LogIndex         uint64 `schema:"log_index,required"`
-	CertificateIndex uint64 `schema:"certificate_index,required"`
+	DomainName       string `schema:"domain_name,required" json:"domain_name"`
+	LogIndex         uint64 `schema:"log_index,required" json:"log_index"`
+	CertificateIndex uint64 `schema:"certificate_index,required" json:"certificate_index"`
 }
 
 type GetDomainTreeIndexResponse struct {
@@ -61,8 +189,8 @@ type GetDomainTreeIndexResponse struct {
 }
 
 type GetSourceLogsRequest struct {
-	Start uint64 `schema:"start,required"`
-	End   uint64 `schema:"end,required"`
+	Start uint64 `schema:"start,required" json:"start"`
+	End   uint64 `schema:"end,required" json:"end"`
 }
 
 type GetSourceLogsResponse struct {
@@ -70,8 +198,8 @@ type GetSourceLogsResponse struct {
 }
 
 type GetSourceLogAndProofRequest struct {
-	Index          uint64 `schema:"index,required"`
-	SourceTreeSize uint64 `schema:"source_tree_size,required"`
+	Index          uint64 `schema:"index,required" json:"index"`
+	SourceTreeSize uint64 `schema:"source_tree_size,required" json:"source_tree_size"`
 }
 
 type GetSourceLogAndProofResponse struct {
@@ -80,10 +208,49 @@ type GetSourceLogAndProofResponse struct {
 }
 
 type GetSourceConsistencyProofRequest struct {
-	First  uint64 `schema:"first,required"`
-	Second uint64 `schema:"second,required"`
+	First  uint64 `schema:"first,required" json:"first"`
+	Second uint64 `schema:"second,required" json:"second"`
 }
 
 type GetSourceConsistencyProofResponse struct {
 	Proof [][]byte `json:"proof"`
 }
+
+type GetInclusionPromiseRequest struct {
+	DomainName       string `schema:"domain_name,required" json:"domain_name"`
+	LogIndex         uint64 `schema:"log_index,required" json:"log_index"`
+	CertificateIndex uint64 `schema:"certificate_index,required" json:"certificate_index"`
+}
+
+type GetInclusionPromiseResponse struct {
+	Timestamp            uint64 `json:"timestamp"`
+	LogIndex             uint64 `json:"log_index"`
+	CertificateIndex     uint64 `json:"certificate_index"`
+	NormalizedDomainName string `json:"normalized_domain_name"`
+	PromiseSignature     []byte `json:"promise_signature"`
+}
+
+type AddCosignatureRequest struct {
+	MapSize        uint64 `json:"map_size"`
+	WitnessKeyHash []byte `json:"witness_key_hash"`
+	Timestamp      uint64 `json:"timestamp"`
+	Signature      []byte `json:"signature"`
+}
+
+type AddCosignatureResponse struct {
+}
+
+type SubscribeRequest struct {
+	DomainNames []string `schema:"domain_name" json:"domain_name"`
+}
+
+// DomainUpdateEvent is the data of a `domain` event sent by /dt/v1/subscribe.
+type DomainUpdateEvent struct {
+	NormalizedDomainName string `json:"normalized_domain_name"`
+	DomainTreeSize       uint64 `json:"domain_tree_size"`
+	MapSize              uint64 `json:"map_size"`
+}
+
+type AdminFetcherRequest struct {
+	LogIndex *uint64 `json:"log_index"`
+}
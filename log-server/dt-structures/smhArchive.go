@@ -0,0 +1,68 @@
+package dt
+
+import "sort"
+
+// An smhArchive stores the SMHs published by a map, indexed both by map size and by timestamp.
+// Republished SMHs only differ in their timestamps and signatures, so the archive keeps a single
+// entry per map size, with the first and the latest SMH published for that size.
+type smhArchive struct {
+	entries []smhArchiveEntry // one entry per published map size, in increasing order
+}
+
+type smhArchiveEntry struct {
+	first  *SignedMapHead // the first SMH published with this map size
+	latest *SignedMapHead // the latest SMH published with this map size
+}
+
+func newSMHArchive() smhArchive {
+	return smhArchive{}
+}
+
+// add adds a newly published SMH to the archive.
+// The SMH's timestamp and map size must not be less than those of the previous SMH.
+func (a *smhArchive) add(smh *SignedMapHead) {
+	if n := len(a.entries); n > 0 && a.entries[n-1].latest.MapSize == smh.MapSize {
+		a.entries[n-1].latest = smh
+		return
+	}
+	a.entries = append(a.entries, smhArchiveEntry{smh, smh})
+}
+
+// find returns the index of the first entry whose map size is not less than mapSize.
+func (a *smhArchive) find(mapSize uint64) int {
+	return sort.Search(len(a.entries), func(i int) bool { return a.entries[i].latest.MapSize >= mapSize })
+}
+
+// get returns the latest SMH with the specified map size, or nil if there is none.
+func (a *smhArchive) get(mapSize uint64) *SignedMapHead {
+	if i := a.find(mapSize); i < len(a.entries) && a.entries[i].latest.MapSize == mapSize {
+		return a.entries[i].latest
+	}
+	return nil
+}
+
+// getRange returns the latest SMH for each published map size in the interval [start, end],
+// in increasing order of map size, up to a maximum of `max` SMHs.
+func (a *smhArchive) getRange(start, end uint64, max int) []*SignedMapHead {
+	var smhs []*SignedMapHead
+	for i := a.find(start); i < len(a.entries) && a.entries[i].latest.MapSize <= end && len(smhs) < max; i++ {
+		smhs = append(smhs, a.entries[i].latest)
+	}
+	return smhs
+}
+
+// getAt returns the SMH that was current at the specified timestamp, i.e., an SMH with the map size
+// of the latest SMH whose timestamp is not greater than `timestamp`, or nil if there is none.
+// Only the first and the latest SMH of each map size are archived, so if `timestamp` falls
+// between two republishes, the first SMH with that map size is returned.
+func (a *smhArchive) getAt(timestamp uint64) *SignedMapHead {
+	i := sort.Search(len(a.entries), func(i int) bool { return a.entries[i].first.Timestamp > timestamp })
+	if i == 0 {
+		return nil
+	}
+	e := a.entries[i-1]
+	if e.latest.Timestamp <= timestamp {
+		return e.latest
+	}
+	return e.first
+}
@@ -0,0 +1,73 @@
+package dt
+
+import "testing"
+
+func TestSMHArchive(t *testing.T) {
+	smh := func(mapSize, timestamp uint64) *SignedMapHead {
+		return &SignedMapHead{MapHead: MapHead{MapSize: mapSize, Timestamp: timestamp}}
+	}
+	// Map size 3 is republished at 20 and 30, map size 5 at 50 and 60
+	heads := []*SignedMapHead{smh(3, 10), smh(3, 20), smh(3, 30), smh(5, 40), smh(5, 50), smh(5, 60), smh(8, 70)}
+	a := newSMHArchive()
+	for _, h := range heads {
+		a.add(h)
+	}
+	if len(a.entries) != 3 {
+		t.Fatalf("got %d entries, expected one per map size", len(a.entries))
+	}
+
+	for _, tt := range []struct {
+		mapSize uint64
+		want    *SignedMapHead
+	}{
+		{0, nil}, {3, heads[2]}, {4, nil}, {5, heads[5]}, {8, heads[6]}, {9, nil},
+	} {
+		if got := a.get(tt.mapSize); got != tt.want {
+			t.Errorf("get(%d): got %+v, expected %+v", tt.mapSize, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		start, end uint64
+		max        int
+		want       []*SignedMapHead
+	}{
+		{0, 100, 10, []*SignedMapHead{heads[2], heads[5], heads[6]}},
+		{4, 8, 10, []*SignedMapHead{heads[5], heads[6]}},
+		{3, 3, 10, []*SignedMapHead{heads[2]}},
+		{0, 7, 1, []*SignedMapHead{heads[2]}},
+		{6, 7, 10, nil},
+		{9, 100, 10, nil},
+	} {
+		got := a.getRange(tt.start, tt.end, tt.max)
+		if len(got) != len(tt.want) {
+			t.Errorf("getRange(%d, %d, %d): got %d SMHs, expected %d", tt.start, tt.end, tt.max, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("getRange(%d, %d, %d)[%d]: got %+v, expected %+v", tt.start, tt.end, tt.max, i, got[i], tt.want[i])
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		timestamp uint64
+		want      *SignedMapHead
+	}{
+		{9, nil},
+		{10, heads[0]},
+		{25, heads[0]}, // between republishes: the first SMH with the same map size
+		{30, heads[2]},
+		{39, heads[2]},
+		{40, heads[3]},
+		{55, heads[3]},
+		{60, heads[5]},
+		{70, heads[6]},
+		{1000, heads[6]},
+	} {
+		if got := a.getAt(tt.timestamp); got != tt.want {
+			t.Errorf("getAt(%d): got %+v, expected %+v", tt.timestamp, got, tt.want)
+		}
+	}
+}
@@ -25,6 +25,8 @@ func NewSourceTree() *SourceTree {
 }
 
 // GetEntries returns the entries in the specified interval, inclusive.
+// As in CT's get-entries, the interval is truncated to the current tree size,
+// so fewer entries are returned if end is not in the tree; start must be in the tree.
 func (st *SourceTree) GetEntries(start, end uint64) ([]logid.LogID, error) {
 	if start > end {
 		return nil, fmt.Errorf("invalid interval: start (%d) > end (%d)", start, end)
@@ -33,8 +35,11 @@ func (st *SourceTree) GetEntries(start, end uint64) ([]logid.LogID, error) {
 	st.m.RLock()
 	defer st.m.RUnlock()
 
+	if start >= uint64(len(st.leaves)) {
+		return nil, fmt.Errorf("invalid interval: start (%d) >= tree size (%d)", start, len(st.leaves))
+	}
 	if end >= uint64(len(st.leaves)) {
-		return nil, fmt.Errorf("invalid interval: end (%d) >= tree size (%d)", end, len(st.leaves))
+		end = uint64(len(st.leaves)) - 1
 	}
 	entries := st.leaves[start : end+1]
 	cp := make([]logid.LogID, len(entries))
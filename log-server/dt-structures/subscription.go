@@ -0,0 +1,40 @@
+package dt
+
+// smhSubscriberBuffer is the number of published SMHs buffered for each subscriber.
+const smhSubscriberBuffer = 16
+
+// SubscribeSMHs returns a channel that receives every SMH published after
+// this call (including republished SMHs), and a function that cancels the subscription.
+//
+// Publishing never blocks on subscribers: if a subscriber falls more than
+// smhSubscriberBuffer SMHs behind, its channel is closed.
+func (dm *DomainMap) SubscribeSMHs() (<-chan *SignedMapHead, func()) {
+	c := make(chan *SignedMapHead, smhSubscriberBuffer)
+	dm.mSubscribers.Lock()
+	dm.subscribers[c] = struct{}{}
+	dm.mSubscribers.Unlock()
+
+	cancel := func() {
+		dm.mSubscribers.Lock()
+		defer dm.mSubscribers.Unlock()
+		if _, ok := dm.subscribers[c]; ok {
+			delete(dm.subscribers, c)
+			close(c)
+		}
+	}
+	return c, cancel
+}
+
+// notifySubscribers sends a newly published SMH to all subscribers.
+func (dm *DomainMap) notifySubscribers(smh *SignedMapHead) {
+	dm.mSubscribers.Lock()
+	defer dm.mSubscribers.Unlock()
+	for c := range dm.subscribers {
+		select {
+		case c <- smh:
+		default:
+			delete(dm.subscribers, c)
+			close(c)
+		}
+	}
+}
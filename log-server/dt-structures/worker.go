@@ -7,8 +7,8 @@ import (
 	"sort"
 	"time"
 
+	ct "github.com/google/certificate-transparency-go"
 	"github.com/google/certificate-transparency-go/logid"
-	"github.com/larc-domain-transparency/domain-transparency/log-server/dt-structures/util"
 )
 
 // A WorkerTransaction specified the actions to be taken by the worker.
@@ -17,12 +17,22 @@ type WorkerTransaction struct {
 	LogID                  logid.LogID
 	LogRevision            LogRevision
 	NewCertificatesIndices map[string][]uint64
+	CertificateHashes      map[uint64]CertificateHashes // indexed by certificate index
+}
+
+// CertificateHashes identifies the exact certificate found at an index of a CT log.
+type CertificateHashes struct {
+	LeafHash    ct.SHA256Hash
+	Fingerprint ct.SHA256Hash
 }
 
 type WorkerConfig struct {
 	BufferSize   int
 	UpdatePeriod time.Duration
 	MMD          time.Duration // This value should be slightly less than the actual MMD
+
+	// Control, if set, sends commands to the worker (e.g. from an admin server).
+	Control *WorkerControl
 }
 
 type worker struct {
@@ -32,6 +42,8 @@ type worker struct {
 	mapRoot         []byte
 	config          WorkerConfig
 	queue           []WorkerTransaction
+	c               <-chan WorkerTransaction
+	changedSince    time.Time // time of the first change not yet included in an SMH
 }
 
 func newWorker(dm *DomainMap, config WorkerConfig) *worker {
@@ -67,12 +79,19 @@ func StartWorker(ctx context.Context, dm *DomainMap, config WorkerConfig) (chan<
 }
 
 func (w *worker) run(ctx context.Context, c <-chan WorkerTransaction) error {
+	w.c = c
+	var requests <-chan workerRequest
+	if w.config.Control != nil {
+		requests = w.config.Control.requests
+	}
 	updateTicker := time.NewTicker(w.config.UpdatePeriod)
 	defer updateTicker.Stop()
 	mmdTicker := time.NewTicker(w.config.MMD)
 	defer mmdTicker.Stop()
+	workerChannelCapacity.Set(float64(cap(c)))
 
 	for {
+		workerChannelLength.Set(float64(len(c)))
 		notePublishSMH := ""
 		select {
 		case <-ctx.Done():
@@ -91,6 +110,9 @@ func (w *worker) run(ctx context.Context, c <-chan WorkerTransaction) error {
 			if err := w.addToQueueAndProcess(t); err != nil {
 				return err
 			}
+			workerQueueLength.Set(float64(len(w.queue)))
+		case req := <-requests:
+			req.result <- req.fn(w)
 		}
 		continue
 	publishSHM:
@@ -98,20 +120,31 @@ func (w *worker) run(ctx context.Context, c <-chan WorkerTransaction) error {
 			fmt.Printf("Warning: the MMD expired, but the first STH hasn't been fetched yet. Resetting MMD timer.\n")
 			continue
 		}
-		err := w.dm.CheckAndPublishSMH(w.mapRoot, w.mapSize, w.sourceRevisions)
-		if err != nil {
-			return fmt.Errorf("error publishing new SMH: %w", err)
+		if err := w.publishSMH(notePublishSMH); err != nil {
+			return err
 		}
-		smh := w.dm.GetLatestSMH()
-		fmt.Printf("New SMH: hash=%s, signature=%s, size=%d, timestamp=%d%s\n",
-			base64.StdEncoding.EncodeToString(smh.MapRootHash[:])[:12],
-			base64.StdEncoding.EncodeToString(smh.MapHeadSignature[:])[:12],
-			smh.MapSize,
-			smh.Timestamp,
-			notePublishSMH)
 	}
 }
 
+func (w *worker) publishSMH(note string) error {
+	err := w.dm.CheckAndPublishSMH(w.mapRoot, w.mapSize, w.sourceRevisions)
+	if err != nil {
+		return fmt.Errorf("error publishing new SMH: %w", err)
+	}
+	if !w.changedSince.IsZero() {
+		smhPublishLatency.Observe(time.Since(w.changedSince).Seconds())
+		w.changedSince = time.Time{}
+	}
+	smh := w.dm.GetLatestSMH()
+	fmt.Printf("New SMH: hash=%s, signature=%s, size=%d, timestamp=%d%s\n",
+		base64.StdEncoding.EncodeToString(smh.MapRootHash[:])[:12],
+		base64.StdEncoding.EncodeToString(smh.MapHeadSignature[:])[:12],
+		smh.MapSize,
+		smh.Timestamp,
+		note)
+	return nil
+}
+
 func (w *worker) addToQueueAndProcess(t WorkerTransaction) error {
 	tryProcess := func(t WorkerTransaction) (bool, error) {
 		if uint64(len(w.sourceRevisions)) < t.LogIndex {
@@ -153,22 +186,35 @@ func (w *worker) processTransaction(t WorkerTransaction) error {
 
 	w.mapSize += newRev.TreeSize - oldRev.TreeSize
 	w.sourceRevisions[t.LogIndex] = newRev
+	if w.changedSince.IsZero() {
+		w.changedSince = time.Now()
+	}
 
+	// The placements are recorded under a single lock, including those of the entries added before an error
+	var placements []placedCertificate
+	defer func() { w.dm.addCertificatePlacements(placements) }()
 	for domain, certIndices := range t.NewCertificatesIndices {
 		if len(certIndices) == 0 {
 			continue
 		}
-		dtree, err := w.getDomainTree(domain)
+		dtree, err := w.dm.getOrAddDomainTree(domain)
 		if err != nil {
 			return err
 		}
 		sort.Slice(certIndices, func(i, j int) bool { return certIndices[i] < certIndices[j] })
 		var treeSize uint64
 		for _, certIndex := range certIndices {
+			hashes, ok := t.CertificateHashes[certIndex]
+			if !ok {
+				return fmt.Errorf("missing hashes for certificate %d of log %d", certIndex, t.LogIndex)
+			}
 			treeSize = dtree.AddEntry(DomainTreeEntry{
-				LogIndex:         t.LogIndex,
-				CertificateIndex: certIndex,
+				LogIndex:               t.LogIndex,
+				CertificateIndex:       certIndex,
+				LeafHash:               hashes.LeafHash,
+				CertificateFingerprint: hashes.Fingerprint,
 			})
+			placements = append(placements, placedCertificate{t.LogIndex, certIndex, CertificatePlacement{dtree.DomainName, treeSize - 1}})
 		}
 		w.mapRoot, err = w.dm.UpdateDomainTreeRoot(w.mapRoot, dtree.DomainName, treeSize)
 		if err != nil {
@@ -177,23 +223,3 @@ func (w *worker) processTransaction(t WorkerTransaction) error {
 	}
 	return nil
 }
-
-func (w *worker) getDomainTree(domain string) (*DomainTree, error) {
-	normalizedDomain, err := util.NormalizeDomainName(domain)
-	if err != nil {
-		return nil, fmt.Errorf("error normalizing domain name %q: %w", domain, err)
-	}
-	dtree, err := w.dm.GetDomainTree(normalizedDomain)
-	if err == nil {
-		return dtree, nil
-	}
-
-	dtree, err = NewDomainTree(normalizedDomain)
-	if err != nil {
-		return nil, fmt.Errorf("error creating domain tree for %q: %w", normalizedDomain, err)
-	}
-	if err := w.dm.AddDomainTree(dtree); err != nil {
-		return nil, fmt.Errorf("error adding new domain tree do the domain map: %w", err)
-	}
-	return dtree, nil
-}